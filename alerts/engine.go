@@ -0,0 +1,224 @@
+// F1Gopher - Copyright (C) 2025 f1gopher
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package alerts
+
+import (
+	"f1gopher/raceControl"
+	"fmt"
+	"time"
+
+	"github.com/f1gopher/f1gopherlib/Messages"
+)
+
+// Engine checks the rules against the session data and returns the alerts for any that are triggered. Rules only
+// trigger when something changes so nothing is triggered by the first message of each type. Not safe for concurrent
+// use.
+type Engine struct {
+	rules []Rule
+
+	names   map[int]string
+	timing  map[int]Messages.Timing
+	event   *Messages.Event
+	weather *Messages.Weather
+
+	// Gap rules trigger when the gap drops below the limit and again only after it has gone back above it
+	gapArmed map[int]bool
+}
+
+func NewEngine() *Engine {
+	engine := &Engine{}
+	engine.Reset()
+	return engine
+}
+
+// Reset clears the session data but keeps the rules
+func (e *Engine) Reset() {
+	e.names = map[int]string{}
+	e.timing = map[int]Messages.Timing{}
+	e.event = nil
+	e.weather = nil
+	e.gapArmed = map[int]bool{}
+}
+
+// SetRules replaces the rules. Gap rules that still compare the same cars keep their state so editing a rule doesn't
+// trigger it again.
+func (e *Engine) SetRules(rules []Rule) {
+	gapArmed := map[int]bool{}
+	for x, rule := range rules {
+		if x < len(e.rules) && e.gapArmed[x] &&
+			rule.Condition == e.rules[x].Condition &&
+			rule.Driver == e.rules[x].Driver &&
+			rule.OtherDriver == e.rules[x].OtherDriver {
+			gapArmed[x] = true
+		}
+	}
+
+	e.rules = make([]Rule, len(rules))
+	copy(e.rules, rules)
+	e.gapArmed = gapArmed
+}
+
+func (e *Engine) ProcessDrivers(data Messages.Drivers) {
+	for _, driver := range data.Drivers {
+		e.names[driver.Number] = driver.ShortName
+	}
+}
+
+func (e *Engine) ProcessTiming(data Messages.Timing) []Alert {
+	previous, exists := e.timing[data.Number]
+	e.timing[data.Number] = data
+
+	alerts := []Alert{}
+	for x := range e.rules {
+		rule := &e.rules[x]
+		if !rule.Enabled {
+			continue
+		}
+
+		switch rule.Condition {
+		case DriverPits:
+			if exists && rule.matchesDriver(data.Number) &&
+				previous.Location != Messages.Pitlane && data.Location == Messages.Pitlane {
+				alerts = append(alerts, e.alert(rule, data.Timestamp, fmt.Sprintf("%s pits", e.name(data.Number))))
+			}
+
+		case SectorPersonalBest:
+			if !exists || !rule.matchesDriver(data.Number) {
+				continue
+			}
+
+			sectors := []struct {
+				time     time.Duration
+				previous time.Duration
+				best     bool
+			}{
+				{time: data.Sector1, previous: previous.Sector1, best: data.Sector1PersonalFastest || data.Sector1OverallFastest},
+				{time: data.Sector2, previous: previous.Sector2, best: data.Sector2PersonalFastest || data.Sector2OverallFastest},
+				{time: data.Sector3, previous: previous.Sector3, best: data.Sector3PersonalFastest || data.Sector3OverallFastest},
+			}
+			for sector, value := range sectors {
+				if (rule.Sector == 0 || rule.Sector == sector+1) && value.best && value.time > 0 && value.time != value.previous {
+					alerts = append(alerts, e.alert(rule, data.Timestamp,
+						fmt.Sprintf("%s personal best sector %d: %.3fs", e.name(data.Number), sector+1, value.time.Seconds())))
+				}
+			}
+
+		case GapBelow:
+			if data.Number != rule.Driver && data.Number != rule.OtherDriver {
+				continue
+			}
+
+			gap, known := e.gapBetween(rule.Driver, rule.OtherDriver)
+			if !known {
+				continue
+			}
+			if gap >= rule.gap() {
+				e.gapArmed[x] = true
+			} else if e.gapArmed[x] {
+				e.gapArmed[x] = false
+				alerts = append(alerts, e.alert(rule, data.Timestamp, fmt.Sprintf("%s - %s gap %.1fs",
+					e.name(rule.Driver), e.name(rule.OtherDriver), gap.Seconds())))
+			}
+		}
+	}
+	return alerts
+}
+
+// gapBetween returns the gap between two cars if they are both on the lead lap
+func (e *Engine) gapBetween(driver int, other int) (time.Duration, bool) {
+	a, aExists := e.timing[driver]
+	b, bExists := e.timing[other]
+	if !aExists || !bExists || driver == other {
+		return 0, false
+	}
+
+	// The gap to the leader is zero for the leader and for lapped cars
+	if (a.Position != 1 && a.GapToLeader <= 0) || (b.Position != 1 && b.GapToLeader <= 0) {
+		return 0, false
+	}
+	return (a.GapToLeader - b.GapToLeader).Abs(), true
+}
+
+func (e *Engine) ProcessEvent(data Messages.Event) []Alert {
+	previous := e.event
+	e.event = &data
+	if previous == nil {
+		return nil
+	}
+
+	text := ""
+	switch {
+	case data.TrackStatus == Messages.RedFlag && previous.TrackStatus != Messages.RedFlag:
+		text = "Red flag"
+	case data.SafetyCar == Messages.SafetyCar && previous.SafetyCar != Messages.SafetyCar:
+		text = "Safety car deployed"
+	case data.SafetyCar == Messages.VirtualSafetyCar && previous.SafetyCar != Messages.VirtualSafetyCar:
+		text = "Virtual safety car deployed"
+	default:
+		return nil
+	}
+
+	alerts := []Alert{}
+	for x := range e.rules {
+		if e.rules[x].Enabled && e.rules[x].Condition == TrackNeutralised {
+			alerts = append(alerts, e.alert(&e.rules[x], data.Timestamp, text))
+		}
+	}
+	return alerts
+}
+
+func (e *Engine) ProcessRaceControlMessage(data Messages.RaceControlMessage) []Alert {
+	msg := raceControl.Parse(data)
+	if msg.Category != raceControl.Investigations {
+		return nil
+	}
+
+	alerts := []Alert{}
+	for x := range e.rules {
+		rule := &e.rules[x]
+		if rule.Enabled && rule.Condition == Investigation && (rule.Driver == AnyDriver || msg.MentionsCar(rule.Driver)) {
+			alerts = append(alerts, e.alert(rule, data.Timestamp, data.Msg))
+		}
+	}
+	return alerts
+}
+
+func (e *Engine) ProcessWeather(data Messages.Weather) []Alert {
+	previous := e.weather
+	e.weather = &data
+	if previous == nil || previous.Rainfall || !data.Rainfall {
+		return nil
+	}
+
+	alerts := []Alert{}
+	for x := range e.rules {
+		if e.rules[x].Enabled && e.rules[x].Condition == RainStart {
+			alerts = append(alerts, e.alert(&e.rules[x], data.Timestamp, "Rain has started"))
+		}
+	}
+	return alerts
+}
+
+func (e *Engine) alert(rule *Rule, timestamp time.Time, text string) Alert {
+	return Alert{Rule: *rule, Timestamp: timestamp, Text: text}
+}
+
+func (e *Engine) name(driverNumber int) string {
+	if name, exists := e.names[driverNumber]; exists {
+		return name
+	}
+	return fmt.Sprintf("%d", driverNumber)
+}
@@ -0,0 +1,173 @@
+package alerts
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/f1gopher/f1gopherlib/Messages"
+)
+
+func TestDriverPits(t *testing.T) {
+	engine := NewEngine()
+	rule := NewRule(DriverPits)
+	rule.Driver = 44
+	engine.SetRules([]Rule{rule})
+	engine.ProcessDrivers(Messages.Drivers{Drivers: []Messages.DriverInfo{{Number: 44, ShortName: "HAM"}}})
+
+	if alerts := engine.ProcessTiming(Messages.Timing{Number: 44, Location: Messages.Pitlane}); len(alerts) != 0 {
+		t.Errorf("the first timing shouldn't trigger an alert")
+	}
+	engine.ProcessTiming(Messages.Timing{Number: 44, Location: Messages.OnTrack})
+	if alerts := engine.ProcessTiming(Messages.Timing{Number: 1, Location: Messages.OnTrack}); len(alerts) != 0 {
+		t.Errorf("only car 44 is watched")
+	}
+	engine.ProcessTiming(Messages.Timing{Number: 1, Location: Messages.Pitlane})
+
+	alerts := engine.ProcessTiming(Messages.Timing{Number: 44, Location: Messages.Pitlane})
+	if len(alerts) != 1 || alerts[0].Text != "HAM pits" {
+		t.Errorf("expected HAM to pit, got %v", alerts)
+	}
+	if alerts := engine.ProcessTiming(Messages.Timing{Number: 44, Location: Messages.Pitlane}); len(alerts) != 0 {
+		t.Errorf("staying in the pits shouldn't trigger again")
+	}
+}
+
+func TestGapBelow(t *testing.T) {
+	engine := NewEngine()
+	rule := NewRule(GapBelow)
+	rule.Driver = 1
+	rule.OtherDriver = 4
+	rule.GapSeconds = 1.0
+	engine.SetRules([]Rule{rule})
+
+	gaps := []struct {
+		gap      time.Duration
+		expected int
+	}{
+		// Starts within the gap so doesn't trigger until it has been above it
+		{gap: 500 * time.Millisecond, expected: 0},
+		{gap: 1500 * time.Millisecond, expected: 0},
+		{gap: 900 * time.Millisecond, expected: 1},
+		{gap: 800 * time.Millisecond, expected: 0},
+		{gap: 1100 * time.Millisecond, expected: 0},
+		{gap: 700 * time.Millisecond, expected: 1},
+	}
+
+	engine.ProcessTiming(Messages.Timing{Number: 1, Position: 1})
+	for _, gap := range gaps {
+		alerts := engine.ProcessTiming(Messages.Timing{Number: 4, Position: 2, GapToLeader: gap.gap})
+		if len(alerts) != gap.expected {
+			t.Errorf("gap %s: expected %d alerts, got %d", gap.gap, gap.expected, len(alerts))
+		}
+	}
+
+	// Lapped cars don't have a gap
+	if alerts := engine.ProcessTiming(Messages.Timing{Number: 4, Position: 15}); len(alerts) != 0 {
+		t.Errorf("lapped cars shouldn't trigger")
+	}
+}
+
+func TestGapBelowKeptWhenEditingRules(t *testing.T) {
+	engine := NewEngine()
+	rule := NewRule(GapBelow)
+	rule.Driver = 1
+	rule.OtherDriver = 4
+	rule.GapSeconds = 1.0
+	engine.SetRules([]Rule{rule})
+
+	engine.ProcessTiming(Messages.Timing{Number: 1, Position: 1})
+	engine.ProcessTiming(Messages.Timing{Number: 4, Position: 2, GapToLeader: 1500 * time.Millisecond})
+
+	// Renaming or changing the gap keeps the rule armed
+	rule.Name = "Close"
+	rule.GapSeconds = 1.2
+	engine.SetRules([]Rule{rule})
+	if alerts := engine.ProcessTiming(Messages.Timing{Number: 4, Position: 2, GapToLeader: 1100 * time.Millisecond}); len(alerts) != 1 {
+		t.Errorf("expected the edited rule to trigger, got %d alerts", len(alerts))
+	}
+
+	engine.ProcessTiming(Messages.Timing{Number: 4, Position: 2, GapToLeader: 1500 * time.Millisecond})
+
+	// Comparing different cars starts again
+	rule.OtherDriver = 16
+	engine.SetRules([]Rule{rule})
+	engine.ProcessTiming(Messages.Timing{Number: 16, Position: 2, GapToLeader: 500 * time.Millisecond})
+	if alerts := engine.ProcessTiming(Messages.Timing{Number: 16, Position: 2, GapToLeader: 400 * time.Millisecond}); len(alerts) != 0 {
+		t.Errorf("expected the changed rule not to trigger until it has been above the gap, got %d alerts", len(alerts))
+	}
+}
+
+func TestSectorPersonalBest(t *testing.T) {
+	engine := NewEngine()
+	rule := NewRule(SectorPersonalBest)
+	rule.Sector = 2
+	engine.SetRules([]Rule{rule})
+
+	engine.ProcessTiming(Messages.Timing{Number: 16})
+	if alerts := engine.ProcessTiming(Messages.Timing{Number: 16, Sector1: 30 * time.Second, Sector1PersonalFastest: true}); len(alerts) != 0 {
+		t.Errorf("only sector 2 is watched")
+	}
+
+	alerts := engine.ProcessTiming(Messages.Timing{Number: 16, Sector1: 30 * time.Second, Sector1PersonalFastest: true,
+		Sector2: 28123 * time.Millisecond, Sector2OverallFastest: true})
+	if len(alerts) != 1 || alerts[0].Text != "16 personal best sector 2: 28.123s" {
+		t.Errorf("expected a sector 2 personal best, got %v", alerts)
+	}
+}
+
+func TestSessionAlerts(t *testing.T) {
+	engine := NewEngine()
+	investigation := NewRule(Investigation)
+	investigation.Driver = 4
+	disabled := NewRule(RainStart)
+	disabled.Enabled = false
+	engine.SetRules([]Rule{NewRule(TrackNeutralised), investigation, NewRule(RainStart), disabled})
+
+	engine.ProcessEvent(Messages.Event{TrackStatus: Messages.GreenFlag})
+	if alerts := engine.ProcessEvent(Messages.Event{SafetyCar: Messages.VirtualSafetyCar}); len(alerts) != 1 || alerts[0].Text != "Virtual safety car deployed" {
+		t.Errorf("expected the VSC, got %v", alerts)
+	}
+	if alerts := engine.ProcessEvent(Messages.Event{SafetyCar: Messages.VirtualSafetyCar}); len(alerts) != 0 {
+		t.Errorf("the VSC should only trigger once")
+	}
+	if alerts := engine.ProcessEvent(Messages.Event{TrackStatus: Messages.RedFlag}); len(alerts) != 1 || alerts[0].Text != "Red flag" {
+		t.Errorf("expected a red flag, got %v", alerts)
+	}
+
+	if alerts := engine.ProcessRaceControlMessage(Messages.RaceControlMessage{
+		Msg: "FIA STEWARDS: TURN 1 INCIDENT INVOLVING CARS 3 (RIC) AND 4 (NOR) UNDER INVESTIGATION - CAUSING A COLLISION"}); len(alerts) != 1 {
+		t.Errorf("expected an investigation alert for car 4")
+	}
+	if alerts := engine.ProcessRaceControlMessage(Messages.RaceControlMessage{
+		Msg: "FIA STEWARDS: UNSAFE RELEASE OF CAR 44 (HAM) UNDER INVESTIGATION"}); len(alerts) != 0 {
+		t.Errorf("car 44 isn't watched")
+	}
+
+	engine.ProcessWeather(Messages.Weather{Rainfall: false})
+	if alerts := engine.ProcessWeather(Messages.Weather{Rainfall: true}); len(alerts) != 1 || alerts[0].Text != "Rain has started" {
+		t.Errorf("expected rain, got %v", alerts)
+	}
+}
+
+func TestRulesSavedByName(t *testing.T) {
+	rule := NewRule(GapBelow)
+	rule.Action = Sound
+
+	data, err := json.Marshal(rule)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var loaded Rule
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		t.Fatal(err)
+	}
+	if loaded != rule {
+		t.Errorf("expected %v, got %v from %s", rule, loaded, data)
+	}
+
+	if err := json.Unmarshal([]byte(`{"condition":"Not A Condition"}`), &loaded); err == nil {
+		t.Errorf("expected an error for an unknown condition")
+	}
+}
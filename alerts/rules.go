@@ -0,0 +1,140 @@
+// F1Gopher - Copyright (C) 2025 f1gopher
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package alerts
+
+import (
+	"fmt"
+	"time"
+)
+
+type Condition int
+
+const (
+	DriverPits Condition = iota
+	GapBelow
+	TrackNeutralised
+	SectorPersonalBest
+	Investigation
+	RainStart
+)
+
+var conditionNames = [...]string{"Driver Pits", "Gap Below", "Red Flag/SC", "Sector Personal Best", "Investigation",
+	"Rain Start"}
+
+func (c Condition) String() string {
+	return conditionNames[c]
+}
+
+// Conditions returns every condition in display order
+func Conditions() []Condition {
+	return []Condition{DriverPits, GapBelow, TrackNeutralised, SectorPersonalBest, Investigation, RainStart}
+}
+
+// Rules are saved by name so the order can change without breaking saved configs
+func (c Condition) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+func (c *Condition) UnmarshalText(text []byte) error {
+	for x, name := range conditionNames {
+		if name == string(text) {
+			*c = Condition(x)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown alert condition: %s", text)
+}
+
+type Action int
+
+const (
+	Toast Action = iota
+	Sound
+	Log
+)
+
+var actionNames = [...]string{"Toast", "Sound", "Log"}
+
+func (a Action) String() string {
+	return actionNames[a]
+}
+
+// Actions returns every action in display order
+func Actions() []Action {
+	return []Action{Toast, Sound, Log}
+}
+
+func (a Action) MarshalText() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+func (a *Action) UnmarshalText(text []byte) error {
+	for x, name := range actionNames {
+		if name == string(text) {
+			*a = Action(x)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown alert action: %s", text)
+}
+
+// AnyDriver is used for rules that apply to every car
+const AnyDriver = 0
+
+// Rule is a condition to watch for and what to do when it happens. Only the fields for the condition are used.
+type Rule struct {
+	Name      string    `json:"name"`
+	Enabled   bool      `json:"enabled"`
+	Condition Condition `json:"condition"`
+	Action    Action    `json:"action"`
+
+	// Car number for pits, gap, personal best and investigation rules or AnyDriver
+	Driver int `json:"driver"`
+	// The other car and the gap in seconds for gap rules
+	OtherDriver int     `json:"otherDriver,omitempty"`
+	GapSeconds  float64 `json:"gapSeconds,omitempty"`
+	// Sector 1 to 3 for personal best rules or zero for any sector
+	Sector int `json:"sector,omitempty"`
+}
+
+// NewRule returns an enabled rule for the condition with defaults for its settings
+func NewRule(condition Condition) Rule {
+	rule := Rule{
+		Name:      condition.String(),
+		Enabled:   true,
+		Condition: condition,
+		Action:    Toast,
+	}
+	if condition == GapBelow {
+		rule.GapSeconds = 1.0
+	}
+	return rule
+}
+
+func (r *Rule) gap() time.Duration {
+	return time.Duration(r.GapSeconds * float64(time.Second))
+}
+
+func (r *Rule) matchesDriver(driverNumber int) bool {
+	return r.Driver == AnyDriver || r.Driver == driverNumber
+}
+
+// Alert is a rule that has been triggered
+type Alert struct {
+	Rule      Rule
+	Timestamp time.Time
+	Text      string
+}
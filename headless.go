@@ -0,0 +1,126 @@
+// F1Gopher - Copyright (C) 2025 f1gopher
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"f1gopher/headless"
+	"f1gopher/mqttPublisher"
+	"f1gopher/webhooks"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/f1gopher/f1gopherlib"
+	"github.com/f1gopher/f1gopherlib/parser"
+	"go.uber.org/zap"
+)
+
+// Headless mode only needs the data used by the webhooks and MQTT publisher
+const headlessDataSources = parser.Timing | parser.Event | parser.RaceControl | parser.Drivers
+
+// runHeadless sends the webhooks and publishes to MQTT for each live session without the GUI until it is interrupted
+func runHeadless(logger *zap.SugaredLogger, hooks []webhooks.Hook, mqttSettings mqttPublisher.Settings, mqttEnabled bool) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if len(hooks) == 0 && !mqttEnabled {
+		logger.Warnln("Nothing to send to, add webhooks from the Webhooks panel or enable MQTT in the options")
+	}
+
+	sink := webhooks.NewSink(hooks)
+	defer sink.Close()
+
+	var publisher *mqttPublisher.Publisher
+	if mqttEnabled {
+		var err error
+		if publisher, err = mqttPublisher.Connect(mqttSettings); err != nil {
+			logger.Errorln("Connecting to the MQTT broker", err)
+		} else {
+			logger.Infof("Publishing to the MQTT broker %s", mqttSettings.Broker)
+			defer publisher.Close()
+		}
+	}
+
+	// A session is still reported as live after it has finished so remember the ones that are done
+	finished := map[time.Time]bool{}
+
+	for {
+		live, next, hasLive, hasNext := f1gopherlib.HappeningSessions()
+		if hasLive && !finished[live.EventTime] {
+			logger.Infof("Following live session %s - %s", live.Name, live.Type)
+
+			if err := followLiveSession(ctx, sink, publisher); err != nil {
+				logger.Errorln("Following live session", err)
+			} else {
+				finished[live.EventTime] = true
+				logger.Infof("Session %s - %s has finished", live.Name, live.Type)
+			}
+		} else if hasNext {
+			logger.Infof("Waiting for %s - %s at %s", next.Name, next.Type, next.EventTime.Local().Format(time.RFC1123))
+		}
+
+		select {
+		case <-ctx.Done():
+			logger.Infoln("Shutting down...")
+			return
+		case <-time.After(time.Minute):
+		}
+	}
+}
+
+// followLiveSession passes the live data to the sink and publisher, if there is one, until the session is over or the
+// context is cancelled
+func followLiveSession(ctx context.Context, sink *webhooks.Sink, publisher *mqttPublisher.Publisher) error {
+	data, err := f1gopherlib.CreateLive(headlessDataSources, "", "")
+	if err != nil {
+		return err
+	}
+	defer data.Close()
+
+	outputs := []headless.Output{sink}
+	if publisher != nil {
+		outputs = append(outputs, publisher)
+	}
+	return headless.Follow(ctx, data, outputs...)
+}
+
+// runWebhookTestEndpoint logs the payloads posted to address/webhook so hooks can be tested locally
+func runWebhookTestEndpoint(logger *zap.SugaredLogger, address string, hooks []webhooks.Hook) {
+	secrets := []string{}
+	for _, hook := range hooks {
+		if hook.Secret != "" {
+			secrets = append(secrets, hook.Secret)
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/webhook", webhooks.NewReceiver(secrets, func(received webhooks.Received) {
+		logger.Infof("Webhook test endpoint received %s (signed: %t, valid signature: %t): %s",
+			received.Payload.Event, received.Signed, received.Valid, received.Body)
+	}))
+
+	logger.Infof("Webhook test endpoint listening on http://%s/webhook", address)
+	go func() {
+		server := &http.Server{Addr: address, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Errorln("Webhook test endpoint", err)
+		}
+	}()
+}
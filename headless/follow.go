@@ -0,0 +1,81 @@
+// F1Gopher - Copyright (C) 2025 f1gopher
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package headless
+
+import (
+	"context"
+
+	"github.com/f1gopher/f1gopherlib"
+	"github.com/f1gopher/f1gopherlib/Messages"
+)
+
+// Output is sent the session data, the webhooks sink and MQTT publisher both implement it
+type Output interface {
+	Reset(name string, session Messages.SessionType)
+	ProcessDrivers(data Messages.Drivers)
+	ProcessTiming(data Messages.Timing)
+	ProcessEvent(data Messages.Event)
+	ProcessRaceControlMessage(data Messages.RaceControlMessage)
+}
+
+// Follow passes the session data to the outputs until the session is over or the context is cancelled. Every channel
+// of the data source is read even if the outputs don't use it because the library blocks when one of them is full.
+func Follow(ctx context.Context, data f1gopherlib.F1GopherLib, outputs ...Output) error {
+	for _, output := range outputs {
+		output.Reset(data.Name(), data.Session())
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+
+		case msg := <-data.Drivers():
+			for _, output := range outputs {
+				output.ProcessDrivers(msg)
+			}
+
+		case msg := <-data.Timing():
+			// Same as the GUI, empty records are sometimes sent on shutdown
+			if msg.Position == 0 {
+				continue
+			}
+			for _, output := range outputs {
+				output.ProcessTiming(msg)
+			}
+
+		case msg := <-data.Event():
+			for _, output := range outputs {
+				output.ProcessEvent(msg)
+			}
+			if msg.Status == Messages.Finalised || msg.Status == Messages.Ended {
+				return nil
+			}
+
+		case msg := <-data.RaceControlMessages():
+			for _, output := range outputs {
+				output.ProcessRaceControlMessage(msg)
+			}
+
+		// Not used but have to be drained
+		case <-data.Time():
+		case <-data.Weather():
+		case <-data.Telemetry():
+		case <-data.Location():
+		case <-data.Radio():
+		}
+	}
+}
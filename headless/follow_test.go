@@ -0,0 +1,99 @@
+package headless
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/f1gopher/f1gopherlib"
+	"github.com/f1gopher/f1gopherlib/Messages"
+)
+
+// fakeSource has the same sized channels as the live data so a channel that isn't read blocks the sender
+type fakeSource struct {
+	f1gopherlib.F1GopherLib
+
+	drivers   chan Messages.Drivers
+	timing    chan Messages.Timing
+	event     chan Messages.Event
+	rcm       chan Messages.RaceControlMessage
+	time      chan Messages.EventTime
+	weather   chan Messages.Weather
+	telemetry chan Messages.Telemetry
+	location  chan Messages.Location
+	radio     chan Messages.Radio
+}
+
+func newFakeSource() *fakeSource {
+	return &fakeSource{
+		drivers:   make(chan Messages.Drivers, 10),
+		timing:    make(chan Messages.Timing, 10),
+		event:     make(chan Messages.Event, 10),
+		rcm:       make(chan Messages.RaceControlMessage, 10),
+		time:      make(chan Messages.EventTime, 10),
+		weather:   make(chan Messages.Weather, 10),
+		telemetry: make(chan Messages.Telemetry, 10),
+		location:  make(chan Messages.Location, 10),
+		radio:     make(chan Messages.Radio, 10),
+	}
+}
+
+func (f *fakeSource) Name() string                                            { return "Test Grand Prix" }
+func (f *fakeSource) Session() Messages.SessionType                           { return Messages.RaceSession }
+func (f *fakeSource) Drivers() <-chan Messages.Drivers                        { return f.drivers }
+func (f *fakeSource) Timing() <-chan Messages.Timing                          { return f.timing }
+func (f *fakeSource) Event() <-chan Messages.Event                            { return f.event }
+func (f *fakeSource) RaceControlMessages() <-chan Messages.RaceControlMessage { return f.rcm }
+func (f *fakeSource) Time() <-chan Messages.EventTime                         { return f.time }
+func (f *fakeSource) Weather() <-chan Messages.Weather                        { return f.weather }
+func (f *fakeSource) Telemetry() <-chan Messages.Telemetry                    { return f.telemetry }
+func (f *fakeSource) Location() <-chan Messages.Location                      { return f.location }
+func (f *fakeSource) Radio() <-chan Messages.Radio                            { return f.radio }
+
+type fakeOutput struct {
+	name   string
+	timing int
+	events int
+}
+
+func (f *fakeOutput) Reset(name string, session Messages.SessionType)            { f.name = name }
+func (f *fakeOutput) ProcessDrivers(data Messages.Drivers)                       {}
+func (f *fakeOutput) ProcessTiming(data Messages.Timing)                         { f.timing++ }
+func (f *fakeOutput) ProcessEvent(data Messages.Event)                           { f.events++ }
+func (f *fakeOutput) ProcessRaceControlMessage(data Messages.RaceControlMessage) {}
+
+func TestFollowDrainsUnusedChannels(t *testing.T) {
+	source := newFakeSource()
+	output := &fakeOutput{}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Only the time channel is sent to, far more than fits in its buffer, and then the session ends
+	go func() {
+		for x := 0; x < 100; x++ {
+			select {
+			case source.time <- Messages.EventTime{Timestamp: time.Unix(int64(x), 0)}:
+			case <-ctx.Done():
+				return
+			}
+		}
+		source.event <- Messages.Event{Status: Messages.Finalised}
+	}()
+
+	if err := Follow(ctx, source, output); err != nil {
+		t.Fatalf("expected the session to finish, got %v", err)
+	}
+	if output.name != "Test Grand Prix" || output.events != 1 {
+		t.Errorf("expected the output to be reset and sent the event, got %+v", output)
+	}
+}
+
+func TestFollowCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := Follow(ctx, newFakeSource(), &fakeOutput{}); err != context.Canceled {
+		t.Errorf("expected the context error, got %v", err)
+	}
+}
@@ -38,12 +38,15 @@ var BuildTime string
 func main() {
 	autoLivePtr := flag.Bool("autoLive", false, "If a live session is in progress display it on startup")
 	logPtr := flag.Bool("log", false, "Enable logging")
+	headlessPtr := flag.Bool("headless", false, "Send the webhooks and publish to MQTT for live sessions without displaying the GUI")
+	webhookTestPtr := flag.String("webhookTest", "", "Log the webhooks posted to http://<address>/webhook, for example localhost:8090")
 	flag.Parse()
 
-	config := ui.NewConfig()
+	config, configErr := ui.LoadConfig()
 
 	var logger *zap.Logger
-	if !*logPtr {
+	// Headless mode and the webhook test endpoint only report through the log
+	if !*logPtr && !*headlessPtr && *webhookTestPtr == "" {
 		logger = zap.NewNop()
 	} else {
 		// Logging goes to stderr for both the library and app
@@ -54,6 +57,19 @@ func main() {
 	sugar := logger.Sugar()
 
 	sugar.Infof("F1Gopher v%s", Version)
+	if configErr != nil {
+		sugar.Errorln("Loading config, using the defaults", configErr)
+	}
+
+	if *webhookTestPtr != "" {
+		runWebhookTestEndpoint(sugar, *webhookTestPtr, config.Webhooks())
+	}
+
+	if *headlessPtr {
+		mqttSettings, mqttEnabled := config.MQTTSettings()
+		runHeadless(sugar, config.Webhooks(), mqttSettings, mqttEnabled)
+		return
+	}
 
 	wnd := giu.NewMasterWindow(
 		fmt.Sprintf("F1Gopher - v%s", Version),
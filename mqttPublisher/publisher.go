@@ -0,0 +1,277 @@
+// F1Gopher - Copyright (C) 2025 f1gopher
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package mqttPublisher
+
+import (
+	"encoding/json"
+	"f1gopher/outputFormat"
+	"f1gopher/raceControl"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/f1gopher/f1gopherlib/Messages"
+)
+
+const connectTimeout = 10 * time.Second
+const publishTimeout = time.Second
+
+type Settings struct {
+	// For example tcp://localhost:1883
+	Broker      string
+	TopicPrefix string
+	QoS         byte
+	Username    string
+	Password    string
+}
+
+// Topic is the latest value published to a retained topic
+type Topic struct {
+	Name  string
+	Value string
+}
+
+// Publisher publishes the session state to retained topics and the race control messages as events. Retained topics
+// are only published when their value changes. Safe for concurrent use.
+type Publisher struct {
+	client mqtt.Client
+	prefix string
+	qos    byte
+
+	retained map[string]string
+	// Topics from the previous session to remove from the broker once connected
+	removed map[string]bool
+	names   map[int]string
+	leader  int
+	lock    sync.Mutex
+}
+
+// Connect starts connecting to the broker in the background, it keeps retrying and reconnects if the connection is
+// lost. The retained topics are published again on each connect so the broker is brought up to date. Race control
+// messages are dropped while disconnected.
+func Connect(settings Settings) (*Publisher, error) {
+	if settings.Broker == "" {
+		return nil, fmt.Errorf("no MQTT broker set")
+	}
+	if settings.QoS > 2 {
+		return nil, fmt.Errorf("invalid MQTT QoS: %d", settings.QoS)
+	}
+
+	p := &Publisher{
+		prefix:   strings.Trim(settings.TopicPrefix, "/"),
+		qos:      settings.QoS,
+		retained: map[string]string{},
+		removed:  map[string]bool{},
+		names:    map[int]string{},
+	}
+
+	options := mqtt.NewClientOptions().
+		AddBroker(settings.Broker).
+		SetClientID(fmt.Sprintf("f1gopher-%d", time.Now().UnixNano())).
+		SetUsername(settings.Username).
+		SetPassword(settings.Password).
+		SetConnectTimeout(connectTimeout).
+		SetConnectRetry(true).
+		SetAutoReconnect(true).
+		SetOnConnectHandler(func(client mqtt.Client) { p.publishAll() })
+
+	p.client = mqtt.NewClient(options)
+	p.client.Connect()
+	return p, nil
+}
+
+func (p *Publisher) IsConnected() bool {
+	return p.client.IsConnectionOpen()
+}
+
+// publishAll brings the broker up to date after connecting
+func (p *Publisher) publishAll() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	for topic := range p.removed {
+		p.client.Publish(topic, p.qos, true, "")
+	}
+	p.removed = map[string]bool{}
+
+	for topic, value := range p.retained {
+		p.client.Publish(topic, p.qos, true, value)
+	}
+}
+
+// Reset removes the retained topics from the previous session and publishes the new session name
+func (p *Publisher) Reset(name string, session Messages.SessionType) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	nameTopic := p.topic("session/name")
+	for topic := range p.retained {
+		// An empty retained message removes the topic from the broker, the name is replaced instead
+		if topic == nameTopic {
+			continue
+		}
+
+		if p.client.IsConnectionOpen() {
+			p.client.Publish(topic, p.qos, true, "")
+		} else {
+			p.removed[topic] = true
+		}
+	}
+	p.retained = map[string]string{}
+	p.names = map[int]string{}
+	p.leader = 0
+
+	p.publishRetained("session/name", fmt.Sprintf("%s - %s", name, session.String()))
+}
+
+func (p *Publisher) ProcessDrivers(data Messages.Drivers) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	for _, driver := range data.Drivers {
+		p.names[driver.Number] = driver.ShortName
+		p.publishRetained(driverTopic(driver.Number, "name"), driver.ShortName)
+	}
+}
+
+func (p *Publisher) ProcessTiming(data Messages.Timing) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if _, exists := p.names[data.Number]; !exists && data.ShortName != "" {
+		p.names[data.Number] = data.ShortName
+		p.publishRetained(driverTopic(data.Number, "name"), data.ShortName)
+	}
+
+	p.publishRetained(driverTopic(data.Number, "position"), fmt.Sprintf("%d", data.Position))
+	p.publishRetained(driverTopic(data.Number, "gap"), gap(data))
+	p.publishRetained(driverTopic(data.Number, "location"), data.Location.String())
+	if data.Tire != Messages.Unknown {
+		p.publishRetained(driverTopic(data.Number, "tyre"), data.Tire.String())
+	}
+
+	if data.Position == 1 && data.Number != p.leader {
+		p.leader = data.Number
+		p.publishRetained("leader", p.name(data.Number))
+		p.publishRetained("leader/number", fmt.Sprintf("%d", data.Number))
+	}
+}
+
+func (p *Publisher) ProcessEvent(data Messages.Event) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.publishRetained("session/status", data.Status.String())
+	if data.TrackStatus != Messages.NoFlag {
+		p.publishRetained("track/status", data.TrackStatus.String())
+	}
+	p.publishRetained("track/safetycar", outputFormat.SafetyCarStatus(data.SafetyCar))
+}
+
+// rcmEvent is the payload for race control messages
+type rcmEvent struct {
+	Timestamp time.Time `json:"timestamp"`
+	Message   string    `json:"message"`
+	Category  string    `json:"category"`
+	Flag      string    `json:"flag,omitempty"`
+	Cars      []int     `json:"cars,omitempty"`
+	Lap       int       `json:"lap,omitempty"`
+}
+
+func (p *Publisher) ProcessRaceControlMessage(data Messages.RaceControlMessage) {
+	msg := raceControl.Parse(data)
+	event := rcmEvent{
+		Timestamp: data.Timestamp,
+		Message:   data.Msg,
+		Category:  msg.Category.String(),
+		Cars:      msg.Cars,
+		Lap:       msg.Lap,
+	}
+	if data.Flag != Messages.NoFlag {
+		event.Flag = data.Flag.String()
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil || !p.client.IsConnectionOpen() {
+		return
+	}
+	p.client.Publish(p.topic("rcm"), p.qos, false, payload)
+}
+
+// Topics returns the latest values of the retained topics sorted by name
+func (p *Publisher) Topics() []Topic {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	topics := make([]Topic, 0, len(p.retained))
+	for name, value := range p.retained {
+		topics = append(topics, Topic{Name: name, Value: value})
+	}
+	sort.Slice(topics, func(i, j int) bool { return topics[i].Name < topics[j].Name })
+	return topics
+}
+
+// Close waits briefly for queued messages to be sent then disconnects. The retained topics are left on the broker.
+func (p *Publisher) Close() {
+	p.client.Disconnect(uint(publishTimeout.Milliseconds()))
+}
+
+// publishRetained must be called with the lock held
+func (p *Publisher) publishRetained(subTopic string, value string) {
+	topic := p.topic(subTopic)
+	if previous, exists := p.retained[topic]; exists && previous == value {
+		return
+	}
+
+	p.retained[topic] = value
+	delete(p.removed, topic)
+	if p.client.IsConnectionOpen() {
+		p.client.Publish(topic, p.qos, true, value)
+	}
+}
+
+func (p *Publisher) topic(subTopic string) string {
+	if p.prefix == "" {
+		return subTopic
+	}
+	return p.prefix + "/" + subTopic
+}
+
+// name must be called with the lock held
+func (p *Publisher) name(driverNumber int) string {
+	if name, exists := p.names[driverNumber]; exists {
+		return name
+	}
+	return fmt.Sprintf("%d", driverNumber)
+}
+
+func driverTopic(driverNumber int, value string) string {
+	return fmt.Sprintf("drivers/%d/%s", driverNumber, value)
+}
+
+// gap returns the gap to the leader in seconds, the leader is zero and cars without a gap (lapped) are "-"
+func gap(data Messages.Timing) string {
+	if data.Position == 1 {
+		return "0.000"
+	}
+	if data.GapToLeader <= 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.3f", data.GapToLeader.Seconds())
+}
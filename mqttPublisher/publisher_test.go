@@ -0,0 +1,382 @@
+package mqttPublisher
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/f1gopher/f1gopherlib/Messages"
+)
+
+// testBroker is just enough of an MQTT 3.1.1 broker for the tests: retained messages and QoS 0 delivery
+type testBroker struct {
+	listener net.Listener
+	retained map[string][]byte
+	clients  map[*testClient]bool
+	lock     sync.Mutex
+}
+
+type testClient struct {
+	conn    net.Conn
+	filters []string
+	lock    sync.Mutex
+}
+
+// startBroker runs an in-process broker on a free port and returns its address
+func startBroker(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b := &testBroker{listener: listener, retained: map[string][]byte{}, clients: map[*testClient]bool{}}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go b.serve(&testClient{conn: conn})
+		}
+	}()
+	t.Cleanup(b.close)
+
+	return "tcp://" + listener.Addr().String()
+}
+
+func (b *testBroker) close() {
+	b.listener.Close()
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	for client := range b.clients {
+		client.conn.Close()
+	}
+}
+
+func (b *testBroker) serve(client *testClient) {
+	b.lock.Lock()
+	b.clients[client] = true
+	b.lock.Unlock()
+
+	defer func() {
+		b.lock.Lock()
+		delete(b.clients, client)
+		b.lock.Unlock()
+		client.conn.Close()
+	}()
+
+	reader := bufio.NewReader(client.conn)
+	for {
+		header, body, err := readPacket(reader)
+		if err != nil {
+			return
+		}
+
+		switch header >> 4 {
+		case 1: // CONNECT
+			client.write(0x20, []byte{0, 0})
+		case 3: // PUBLISH
+			qos := (header >> 1) & 3
+			topicLength := int(binary.BigEndian.Uint16(body))
+			topic := string(body[2 : 2+topicLength])
+			payload := body[2+topicLength:]
+			if qos > 0 {
+				client.write(0x40, payload[:2])
+				payload = payload[2:]
+			}
+			b.publish(topic, payload, header&1 == 1)
+		case 8: // SUBSCRIBE
+			filterLength := int(binary.BigEndian.Uint16(body[2:]))
+			filter := string(body[4 : 4+filterLength])
+			client.lock.Lock()
+			client.filters = append(client.filters, filter)
+			client.lock.Unlock()
+			// Everything is delivered at QoS 0
+			client.write(0x90, []byte{body[0], body[1], 0})
+			b.sendRetained(client, filter)
+		case 12: // PINGREQ
+			client.write(0xD0, nil)
+		case 14: // DISCONNECT
+			return
+		}
+	}
+}
+
+func (b *testBroker) publish(topic string, payload []byte, retain bool) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if retain {
+		// An empty retained message removes the topic
+		if len(payload) == 0 {
+			delete(b.retained, topic)
+		} else {
+			b.retained[topic] = payload
+		}
+	}
+
+	for client := range b.clients {
+		if client.subscribed(topic) {
+			client.publish(topic, payload, false)
+		}
+	}
+}
+
+func (b *testBroker) sendRetained(client *testClient, filter string) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	for topic, payload := range b.retained {
+		if topicMatches(filter, topic) {
+			client.publish(topic, payload, true)
+		}
+	}
+}
+
+func (c *testClient) subscribed(topic string) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for _, filter := range c.filters {
+		if topicMatches(filter, topic) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *testClient) publish(topic string, payload []byte, retain bool) {
+	header := byte(0x30)
+	if retain {
+		header |= 1
+	}
+
+	body := binary.BigEndian.AppendUint16(nil, uint16(len(topic)))
+	body = append(body, topic...)
+	c.write(header, append(body, payload...))
+}
+
+func (c *testClient) write(header byte, body []byte) {
+	packet := []byte{header}
+	// Remaining length, 7 bits at a time
+	for length := len(body); ; {
+		digit := byte(length % 128)
+		length /= 128
+		if length > 0 {
+			digit |= 0x80
+		}
+		packet = append(packet, digit)
+		if length == 0 {
+			break
+		}
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.conn.Write(append(packet, body...))
+}
+
+func readPacket(reader *bufio.Reader) (byte, []byte, error) {
+	header, err := reader.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	length := 0
+	for multiplier := 1; ; multiplier *= 128 {
+		digit, err := reader.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		length += int(digit&0x7F) * multiplier
+		if digit&0x80 == 0 {
+			break
+		}
+	}
+
+	body := make([]byte, length)
+	_, err = io.ReadFull(reader, body)
+	return header, body, err
+}
+
+// topicMatches only handles exact topics and filters ending with a multi level wildcard
+func topicMatches(filter string, topic string) bool {
+	if prefix, found := strings.CutSuffix(filter, "#"); found {
+		return strings.HasPrefix(topic, prefix)
+	}
+	return filter == topic
+}
+
+// subscriber collects the latest value of every topic
+type subscriber struct {
+	client mqtt.Client
+	values map[string]string
+	lock   sync.Mutex
+}
+
+func subscribe(t *testing.T, address string, filter string) *subscriber {
+	s := &subscriber{values: map[string]string{}}
+	s.client = mqtt.NewClient(mqtt.NewClientOptions().AddBroker(address).SetClientID("test-subscriber"))
+	if token := s.client.Connect(); !token.WaitTimeout(5*time.Second) || token.Error() != nil {
+		t.Fatalf("connecting the subscriber: %v", token.Error())
+	}
+	t.Cleanup(func() { s.client.Disconnect(0) })
+
+	token := s.client.Subscribe(filter, 1, func(client mqtt.Client, msg mqtt.Message) {
+		s.lock.Lock()
+		s.values[msg.Topic()] = string(msg.Payload())
+		s.lock.Unlock()
+	})
+	if !token.WaitTimeout(5*time.Second) || token.Error() != nil {
+		t.Fatalf("subscribing: %v", token.Error())
+	}
+	return s
+}
+
+// waitFor waits for the topic to have the value
+func (s *subscriber) waitFor(t *testing.T, topic string, value string) {
+	for start := time.Now(); time.Since(start) < 5*time.Second; time.Sleep(10 * time.Millisecond) {
+		s.lock.Lock()
+		current, exists := s.values[topic]
+		s.lock.Unlock()
+		if exists && current == value {
+			return
+		}
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	t.Fatalf("expected %s to be %q, got %q", topic, value, s.values[topic])
+}
+
+func connect(t *testing.T, address string) *Publisher {
+	publisher, err := Connect(Settings{Broker: address, TopicPrefix: "f1/", QoS: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(publisher.Close)
+
+	for start := time.Now(); !publisher.IsConnected(); time.Sleep(10 * time.Millisecond) {
+		if time.Since(start) > 5*time.Second {
+			t.Fatal("timed out connecting")
+		}
+	}
+	return publisher
+}
+
+func TestRetainedTopics(t *testing.T) {
+	address := startBroker(t)
+	publisher := connect(t, address)
+
+	publisher.Reset("Monaco Grand Prix", Messages.RaceSession)
+	publisher.ProcessDrivers(Messages.Drivers{Drivers: []Messages.DriverInfo{
+		{Number: 16, ShortName: "LEC"}, {Number: 81, ShortName: "PIA"}}})
+	publisher.ProcessEvent(Messages.Event{Status: Messages.Started, TrackStatus: Messages.YellowFlag,
+		SafetyCar: Messages.VirtualSafetyCar})
+	publisher.ProcessTiming(Messages.Timing{Number: 16, Position: 1, Tire: Messages.Hard, Location: Messages.OnTrack})
+	publisher.ProcessTiming(Messages.Timing{Number: 81, Position: 2, GapToLeader: 1234 * time.Millisecond,
+		Tire: Messages.Medium, Location: Messages.Pitlane})
+
+	// Subscribe afterwards so the values can only come from the retained messages
+	s := subscribe(t, address, "f1/#")
+	s.waitFor(t, "f1/session/name", "Monaco Grand Prix - Race")
+	s.waitFor(t, "f1/session/status", "Started")
+	s.waitFor(t, "f1/track/status", "Yellow")
+	s.waitFor(t, "f1/track/safetycar", "VSC")
+	s.waitFor(t, "f1/leader", "LEC")
+	s.waitFor(t, "f1/leader/number", "16")
+	s.waitFor(t, "f1/drivers/16/gap", "0.000")
+	s.waitFor(t, "f1/drivers/81/name", "PIA")
+	s.waitFor(t, "f1/drivers/81/position", "2")
+	s.waitFor(t, "f1/drivers/81/gap", "1.234")
+	s.waitFor(t, "f1/drivers/81/tyre", "Medium")
+	s.waitFor(t, "f1/drivers/81/location", "Pitlane")
+
+	publisher.ProcessTiming(Messages.Timing{Number: 81, Position: 1, Tire: Messages.Medium, Location: Messages.OnTrack})
+	s.waitFor(t, "f1/leader", "PIA")
+	s.waitFor(t, "f1/drivers/81/location", "On Track")
+
+	topics := publisher.Topics()
+	if len(topics) == 0 || topics[0].Name != "f1/drivers/16/gap" {
+		t.Errorf("expected the topics sorted by name, got %v", topics)
+	}
+}
+
+func TestResetRemovesOldTopics(t *testing.T) {
+	address := startBroker(t)
+	publisher := connect(t, address)
+
+	publisher.Reset("Practice", Messages.Practice1Session)
+	publisher.ProcessTiming(Messages.Timing{Number: 2, Position: 20, ShortName: "SAR"})
+	publisher.Reset("Qualifying", Messages.QualifyingSession)
+
+	// Give the broker time to handle the removals before checking nothing is retained
+	time.Sleep(100 * time.Millisecond)
+	s := subscribe(t, address, "f1/#")
+	s.waitFor(t, "f1/session/name", "Qualifying - Qualifying")
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if _, exists := s.values["f1/drivers/2/position"]; exists {
+		t.Errorf("expected the previous session's drivers to be removed, got %v", s.values)
+	}
+}
+
+func TestRaceControlEvents(t *testing.T) {
+	address := startBroker(t)
+	publisher := connect(t, address)
+	s := subscribe(t, address, "f1/rcm")
+
+	publisher.ProcessRaceControlMessage(Messages.RaceControlMessage{Msg: "BLUE FLAG FOR CAR 2 (SAR)", Flag: Messages.BlueFlag})
+
+	var event rcmEvent
+	for start := time.Now(); time.Since(start) < 5*time.Second; time.Sleep(10 * time.Millisecond) {
+		s.lock.Lock()
+		value, exists := s.values["f1/rcm"]
+		s.lock.Unlock()
+		if exists {
+			if err := json.Unmarshal([]byte(value), &event); err != nil {
+				t.Fatal(err)
+			}
+			break
+		}
+	}
+
+	if event.Category != "Flags" || event.Flag != "Blue" || len(event.Cars) != 1 || event.Cars[0] != 2 {
+		t.Errorf("unexpected event %v", event)
+	}
+}
+
+func TestInvalidSettings(t *testing.T) {
+	if _, err := Connect(Settings{}); err == nil {
+		t.Errorf("expected an error without a broker")
+	}
+	if _, err := Connect(Settings{Broker: "tcp://localhost:1883", QoS: 3}); err == nil {
+		t.Errorf("expected an error for QoS 3")
+	}
+}
+
+func TestPublishedBeforeConnecting(t *testing.T) {
+	address := startBroker(t)
+	publisher, err := Connect(Settings{Broker: address, TopicPrefix: "f1", QoS: 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer publisher.Close()
+
+	// Sent once the connection is made
+	publisher.Reset("Japanese Grand Prix", Messages.SprintSession)
+	publisher.ProcessEvent(Messages.Event{Status: Messages.Inactive, TrackStatus: Messages.GreenFlag})
+
+	s := subscribe(t, address, "f1/#")
+	s.waitFor(t, "f1/session/name", "Japanese Grand Prix - Sprint")
+	s.waitFor(t, "f1/track/status", "Green")
+}
@@ -0,0 +1,160 @@
+// F1Gopher - Copyright (C) 2025 f1gopher
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package oscOutput
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Message is an OSC 1.0 message. The arguments can be int32, float32 or string.
+type Message struct {
+	Address string
+	Args    []any
+}
+
+func (m Message) String() string {
+	args := make([]string, len(m.Args))
+	for x, arg := range m.Args {
+		args[x] = fmt.Sprintf("%v", arg)
+	}
+	return strings.TrimSpace(m.Address + " " + strings.Join(args, " "))
+}
+
+func (m Message) equal(other Message) bool {
+	if m.Address != other.Address || len(m.Args) != len(other.Args) {
+		return false
+	}
+	for x := range m.Args {
+		if m.Args[x] != other.Args[x] {
+			return false
+		}
+	}
+	return true
+}
+
+func (m Message) MarshalBinary() ([]byte, error) {
+	if !strings.HasPrefix(m.Address, "/") {
+		return nil, fmt.Errorf("invalid OSC address: %s", m.Address)
+	}
+
+	tags := ","
+	var args bytes.Buffer
+	for _, arg := range m.Args {
+		switch value := arg.(type) {
+		case int32:
+			tags += "i"
+			binary.Write(&args, binary.BigEndian, value)
+		case float32:
+			tags += "f"
+			binary.Write(&args, binary.BigEndian, math.Float32bits(value))
+		case string:
+			tags += "s"
+			writeString(&args, value)
+		default:
+			return nil, fmt.Errorf("unsupported OSC argument type %T for %s", arg, m.Address)
+		}
+	}
+
+	var data bytes.Buffer
+	writeString(&data, m.Address)
+	writeString(&data, tags)
+	data.Write(args.Bytes())
+	return data.Bytes(), nil
+}
+
+func (m *Message) UnmarshalBinary(data []byte) error {
+	reader := bytes.NewReader(data)
+
+	address, err := readString(reader)
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(address, "/") {
+		return fmt.Errorf("invalid OSC address: %s", address)
+	}
+	m.Address = address
+	m.Args = nil
+
+	// The type tags are optional in old implementations
+	if reader.Len() == 0 {
+		return nil
+	}
+	tags, err := readString(reader)
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(tags, ",") {
+		return fmt.Errorf("invalid OSC type tags: %s", tags)
+	}
+
+	for _, tag := range tags[1:] {
+		switch tag {
+		case 'i':
+			var value int32
+			if err = binary.Read(reader, binary.BigEndian, &value); err != nil {
+				return err
+			}
+			m.Args = append(m.Args, value)
+		case 'f':
+			var bits uint32
+			if err = binary.Read(reader, binary.BigEndian, &bits); err != nil {
+				return err
+			}
+			m.Args = append(m.Args, math.Float32frombits(bits))
+		case 's':
+			value, err := readString(reader)
+			if err != nil {
+				return err
+			}
+			m.Args = append(m.Args, value)
+		default:
+			return fmt.Errorf("unsupported OSC type tag: %c", tag)
+		}
+	}
+	return nil
+}
+
+// Strings are null terminated and padded to a multiple of 4 bytes
+func writeString(buffer *bytes.Buffer, value string) {
+	buffer.WriteString(value)
+	padding := 4 - len(value)%4
+	buffer.Write(make([]byte, padding))
+}
+
+func readString(reader *bytes.Reader) (string, error) {
+	var value []byte
+	for {
+		b, err := reader.ReadByte()
+		if err != nil {
+			return "", fmt.Errorf("unterminated OSC string")
+		}
+		if b == 0 {
+			break
+		}
+		value = append(value, b)
+	}
+
+	// Skip the padding after the terminator
+	padding := (4 - (len(value)+1)%4) % 4
+	if _, err := reader.Seek(int64(padding), 1); err != nil {
+		return "", err
+	}
+	return string(value), nil
+}
@@ -0,0 +1,57 @@
+package oscOutput
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestMarshalBinary(t *testing.T) {
+	msg := Message{Address: "/a", Args: []any{int32(1), "abcd"}}
+	data, err := msg.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []byte{
+		'/', 'a', 0, 0,
+		',', 'i', 's', 0,
+		0, 0, 0, 1,
+		'a', 'b', 'c', 'd', 0, 0, 0, 0,
+	}
+	if !bytes.Equal(data, expected) {
+		t.Errorf("expected %v, got %v", expected, data)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	msg := Message{Address: "/f1gopher/tower/1", Args: []any{int32(44), "HAM", float32(1.5), "", int32(-3)}}
+	data, err := msg.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded Message
+	if err = decoded.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if !decoded.equal(msg) {
+		t.Errorf("expected %v, got %v", msg, decoded)
+	}
+}
+
+func TestInvalidMessages(t *testing.T) {
+	if _, err := (Message{Address: "no-slash"}).MarshalBinary(); err == nil {
+		t.Errorf("expected an error for an address without a slash")
+	}
+	if _, err := (Message{Address: "/a", Args: []any{1.5}}).MarshalBinary(); err == nil {
+		t.Errorf("expected an error for a float64 argument")
+	}
+
+	var msg Message
+	if err := msg.UnmarshalBinary([]byte{'/', 'a'}); err == nil {
+		t.Errorf("expected an error for an unterminated address")
+	}
+	if err := msg.UnmarshalBinary([]byte{'/', 'a', 0, 0, ',', 'i', 0, 0, 0, 1}); err == nil {
+		t.Errorf("expected an error for a short argument")
+	}
+}
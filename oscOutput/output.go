@@ -0,0 +1,440 @@
+// F1Gopher - Copyright (C) 2025 f1gopher
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package oscOutput
+
+import (
+	"errors"
+	"f1gopher/outputFormat"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/f1gopher/f1gopherlib/Messages"
+)
+
+// Every address starts with this
+const Prefix = "/f1gopher"
+
+// Messages sent to the output's port, connect adds the sender as a target and sends it the current state, snapshot only
+// sends the current state and is ignored from anything that isn't a target
+const ConnectAddress = Prefix + "/connect"
+const SnapshotAddress = Prefix + "/snapshot"
+
+// Most targets that can be added by connecting
+const maxConnectedTargets = 16
+
+type Settings struct {
+	// host:port of each graphics engine
+	Targets []string
+	// The most times a second an address is sent, changes in between are combined
+	Rate int
+	// Send the whole state when a session starts and let graphics engines connect and ask for it
+	SnapshotOnConnect bool
+	// Local address to send from and listen for snapshot requests on, any free port if empty
+	ListenAddress string
+}
+
+// Battle is a group of cars close enough to be racing each other, in position order
+type Battle struct {
+	Drivers []int
+	Spread  time.Duration
+	Trend   string
+}
+
+// Output sends the session state as OSC messages over UDP. It keeps the latest message for each address and sends
+// the ones that have changed at the configured rate. Safe for concurrent use.
+type Output struct {
+	conn              *net.UDPConn
+	snapshotOnConnect bool
+	battles           func() []Battle
+
+	targets    []*net.UDPAddr
+	connected  int
+	values     map[string]Message
+	dirty      map[string]bool
+	names      map[int]string
+	battleRows int
+	sent       int
+	lastErr    error
+	lock       sync.Mutex
+
+	stop    chan struct{}
+	running sync.WaitGroup
+}
+
+// Start opens the socket and starts sending, battles is called before each send to get the current battles
+func Start(settings Settings, battles func() []Battle) (*Output, error) {
+	if settings.Rate <= 0 {
+		return nil, fmt.Errorf("invalid OSC rate: %d", settings.Rate)
+	}
+
+	o := &Output{
+		snapshotOnConnect: settings.SnapshotOnConnect,
+		battles:           battles,
+		values:            map[string]Message{},
+		dirty:             map[string]bool{},
+		names:             map[int]string{},
+		stop:              make(chan struct{}),
+	}
+
+	for _, target := range settings.Targets {
+		target = strings.TrimSpace(target)
+		if target == "" {
+			continue
+		}
+
+		address, err := net.ResolveUDPAddr("udp", target)
+		if err != nil {
+			return nil, fmt.Errorf("invalid OSC target %s: %w", target, err)
+		}
+		o.targets = append(o.targets, address)
+	}
+	if len(o.targets) == 0 && !settings.SnapshotOnConnect {
+		return nil, fmt.Errorf("no OSC targets set")
+	}
+
+	listen := settings.ListenAddress
+	if listen == "" {
+		listen = ":0"
+	}
+	local, err := net.ResolveUDPAddr("udp", listen)
+	if err != nil {
+		return nil, fmt.Errorf("invalid OSC listen address %s: %w", listen, err)
+	}
+	if o.conn, err = net.ListenUDP("udp", local); err != nil {
+		return nil, err
+	}
+
+	o.running.Add(2)
+	go o.sendChanges(time.Second / time.Duration(settings.Rate))
+	go o.listen()
+
+	return o, nil
+}
+
+// LocalAddress is where snapshot requests should be sent
+func (o *Output) LocalAddress() string {
+	return o.conn.LocalAddr().String()
+}
+
+func (o *Output) Close() {
+	close(o.stop)
+	o.conn.Close()
+	o.running.Wait()
+}
+
+// Stats returns the number of messages sent and the last error sending
+func (o *Output) Stats() (int, error) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	return o.sent, o.lastErr
+}
+
+// Values returns the latest message for every address sorted by address
+func (o *Output) Values() []Message {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	return o.sortedValues()
+}
+
+// Reset clears the previous session and, in snapshot mode, sends the new session to every target
+func (o *Output) Reset(name string, session Messages.SessionType) {
+	o.lock.Lock()
+	o.values = map[string]Message{}
+	o.dirty = map[string]bool{}
+	o.names = map[int]string{}
+	o.battleRows = 0
+	o.set(Prefix+"/session/name", fmt.Sprintf("%s - %s", name, session.String()))
+	targets := o.targets
+	o.lock.Unlock()
+
+	if o.snapshotOnConnect {
+		for _, target := range targets {
+			o.sendSnapshot(target)
+		}
+	}
+}
+
+func (o *Output) ProcessDrivers(data Messages.Drivers) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	for _, driver := range data.Drivers {
+		o.names[driver.Number] = driver.ShortName
+	}
+}
+
+func (o *Output) ProcessTiming(data Messages.Timing) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	if _, exists := o.names[data.Number]; !exists && data.ShortName != "" {
+		o.names[data.Number] = data.ShortName
+	}
+
+	// A row for each position so the tower can be laid out by position
+	o.set(fmt.Sprintf("%s/tower/%d", Prefix, data.Position),
+		int32(data.Number),
+		o.name(data.Number),
+		data.Team,
+		data.HexColor,
+		formatGap(data.Position, data.GapToLeader),
+		formatGap(data.Position, data.TimeDiffToPositionAhead),
+		outputFormat.LapTime(data.LastLap),
+		data.Tire.String(),
+		int32(data.LapsOnTire),
+		int32(data.Pitstops),
+		data.Location.String())
+}
+
+func (o *Output) ProcessEvent(data Messages.Event) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	o.set(Prefix+"/session/status", data.Status.String())
+	o.set(Prefix+"/flag", data.TrackStatus.String())
+	o.set(Prefix+"/safetycar", outputFormat.SafetyCarStatus(data.SafetyCar))
+	o.set(Prefix+"/lap", int32(data.CurrentLap), int32(data.TotalLaps))
+
+	running := int32(1)
+	if data.ClockStopped {
+		running = 0
+	}
+	if previous, exists := o.values[Prefix+"/clock"]; exists {
+		o.set(Prefix+"/clock", previous.Args[0], previous.Args[1], running)
+	} else {
+		o.set(Prefix+"/clock", outputFormat.Clock(data.RemainingTime), int32(data.RemainingTime.Seconds()), running)
+	}
+}
+
+func (o *Output) ProcessEventTime(data Messages.EventTime) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	running := int32(1)
+	if previous, exists := o.values[Prefix+"/clock"]; exists {
+		running = previous.Args[2].(int32)
+	}
+	o.set(Prefix+"/clock", outputFormat.Clock(data.Remaining), int32(data.Remaining.Seconds()), running)
+}
+
+// set stores the message for the address and marks it to be sent if it has changed, must be called with the lock held
+func (o *Output) set(address string, args ...any) {
+	msg := Message{Address: address, Args: args}
+	if previous, exists := o.values[address]; exists && previous.equal(msg) {
+		return
+	}
+
+	o.values[address] = msg
+	o.dirty[address] = true
+}
+
+// updateBattles must be called with the lock held
+func (o *Output) updateBattles() {
+	if o.battles == nil {
+		return
+	}
+
+	battles := o.battles()
+	o.set(Prefix+"/battles", int32(len(battles)))
+
+	for x, battle := range battles {
+		names := make([]string, len(battle.Drivers))
+		args := []any{battle.Trend, float32(battle.Spread.Seconds()), ""}
+		for y, driverNumber := range battle.Drivers {
+			names[y] = o.name(driverNumber)
+			args = append(args, int32(driverNumber))
+		}
+		args[2] = strings.Join(names, " - ")
+		o.set(fmt.Sprintf("%s/battle/%d", Prefix, x+1), args...)
+	}
+
+	// Blank the rows for battles that have finished
+	for x := len(battles); x < o.battleRows; x++ {
+		o.set(fmt.Sprintf("%s/battle/%d", Prefix, x+1), "", float32(0), "")
+	}
+	o.battleRows = max(o.battleRows, len(battles))
+}
+
+func (o *Output) sendChanges(interval time.Duration) {
+	defer o.running.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-o.stop:
+			return
+		case <-ticker.C:
+		}
+
+		o.lock.Lock()
+		o.updateBattles()
+		changes := make([]Message, 0, len(o.dirty))
+		for address := range o.dirty {
+			changes = append(changes, o.values[address])
+		}
+		o.dirty = map[string]bool{}
+		targets := o.targets
+		o.lock.Unlock()
+
+		sort.Slice(changes, func(i, j int) bool { return changes[i].Address < changes[j].Address })
+		for _, target := range targets {
+			o.send(target, changes)
+		}
+	}
+}
+
+// listen sends a snapshot to anything that asks for one
+func (o *Output) listen() {
+	defer o.running.Done()
+
+	buffer := make([]byte, 1024)
+	for {
+		size, from, err := o.conn.ReadFromUDP(buffer)
+		if errors.Is(err, net.ErrClosed) {
+			return
+		} else if err != nil {
+			continue
+		}
+
+		var msg Message
+		if msg.UnmarshalBinary(buffer[:size]) != nil {
+			continue
+		}
+
+		if !o.snapshotOnConnect {
+			continue
+		}
+
+		switch msg.Address {
+		case ConnectAddress:
+			o.addTarget(from)
+			o.sendSnapshot(from)
+		case SnapshotAddress:
+			// Never reply to an unknown address so the port can't be used to send the state somewhere else
+			if o.isTarget(from) {
+				o.sendSnapshot(from)
+			}
+		}
+	}
+}
+
+func (o *Output) isTarget(target *net.UDPAddr) bool {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	for _, existing := range o.targets {
+		if existing.String() == target.String() {
+			return true
+		}
+	}
+	return false
+}
+
+func (o *Output) addTarget(target *net.UDPAddr) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	for _, existing := range o.targets {
+		if existing.String() == target.String() {
+			return
+		}
+	}
+	if o.connected == maxConnectedTargets {
+		return
+	}
+
+	o.connected++
+	// Copied so the senders can use the old list without the lock
+	o.targets = append(append([]*net.UDPAddr{}, o.targets...), target)
+}
+
+// Targets returns where the messages are being sent
+func (o *Output) Targets() []string {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	targets := make([]string, len(o.targets))
+	for x, target := range o.targets {
+		targets[x] = target.String()
+	}
+	return targets
+}
+
+func (o *Output) sendSnapshot(target *net.UDPAddr) {
+	o.lock.Lock()
+	o.updateBattles()
+	snapshot := o.sortedValues()
+	o.lock.Unlock()
+
+	o.send(target, snapshot)
+}
+
+func (o *Output) send(target *net.UDPAddr, messages []Message) {
+	var sendErr error
+	sent := 0
+	for _, msg := range messages {
+		data, err := msg.MarshalBinary()
+		if err == nil {
+			_, err = o.conn.WriteToUDP(data, target)
+		}
+		if err != nil {
+			sendErr = err
+			continue
+		}
+		sent++
+	}
+
+	o.lock.Lock()
+	o.sent += sent
+	if sendErr != nil {
+		o.lastErr = sendErr
+	}
+	o.lock.Unlock()
+}
+
+// sortedValues must be called with the lock held
+func (o *Output) sortedValues() []Message {
+	values := make([]Message, 0, len(o.values))
+	for _, msg := range o.values {
+		values = append(values, msg)
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i].Address < values[j].Address })
+	return values
+}
+
+// name must be called with the lock held
+func (o *Output) name(driverNumber int) string {
+	if name, exists := o.names[driverNumber]; exists {
+		return name
+	}
+	return fmt.Sprintf("%d", driverNumber)
+}
+
+// formatGap returns the gap in seconds, empty for the leader and cars without a gap
+func formatGap(position int, gap time.Duration) string {
+	if position == 1 || gap <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("+%.3f", gap.Seconds())
+}
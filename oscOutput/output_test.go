@@ -0,0 +1,271 @@
+package oscOutput
+
+import (
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/f1gopher/f1gopherlib/Messages"
+)
+
+// receiver collects the messages sent to a local UDP port
+type receiver struct {
+	conn     *net.UDPConn
+	messages []Message
+	lock     sync.Mutex
+}
+
+func listen(t *testing.T) *receiver {
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	r := &receiver{conn: conn}
+	go func() {
+		buffer := make([]byte, 1024)
+		for {
+			size, err := conn.Read(buffer)
+			if err != nil {
+				return
+			}
+			var msg Message
+			if msg.UnmarshalBinary(buffer[:size]) == nil {
+				r.lock.Lock()
+				r.messages = append(r.messages, msg)
+				r.lock.Unlock()
+			}
+		}
+	}()
+	return r
+}
+
+func (r *receiver) address() string {
+	return r.conn.LocalAddr().String()
+}
+
+// waitFor waits for a message with the address and returns the latest one
+func (r *receiver) waitFor(t *testing.T, address string) Message {
+	for start := time.Now(); time.Since(start) < 5*time.Second; time.Sleep(10 * time.Millisecond) {
+		if msg, count := r.latest(address); count > 0 {
+			return msg
+		}
+	}
+	t.Fatalf("no message received for %s", address)
+	return Message{}
+}
+
+func (r *receiver) latest(address string) (Message, int) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	var latest Message
+	count := 0
+	for _, msg := range r.messages {
+		if msg.Address == address {
+			latest = msg
+			count++
+		}
+	}
+	return latest, count
+}
+
+func start(t *testing.T, settings Settings, battles func() []Battle) *Output {
+	output, err := Start(settings, battles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(output.Close)
+	return output
+}
+
+func TestTowerClockAndFlags(t *testing.T) {
+	r := listen(t)
+	output := start(t, Settings{Targets: []string{r.address()}, Rate: 20}, nil)
+
+	output.Reset("Italian Grand Prix", Messages.RaceSession)
+	output.ProcessDrivers(Messages.Drivers{Drivers: []Messages.DriverInfo{{Number: 4, ShortName: "NOR"}}})
+	output.ProcessTiming(Messages.Timing{Number: 4, Position: 2, Team: "McLaren", HexColor: "ff8000",
+		GapToLeader: 2500 * time.Millisecond, TimeDiffToPositionAhead: 2500 * time.Millisecond,
+		LastLap: 81234 * time.Millisecond, Tire: Messages.Soft, LapsOnTire: 5, Pitstops: 1, Location: Messages.OnTrack})
+	output.ProcessEvent(Messages.Event{Status: Messages.Started, TrackStatus: Messages.RedFlag,
+		SafetyCar: Messages.SafetyCar, CurrentLap: 12, TotalLaps: 53})
+	output.ProcessEventTime(Messages.EventTime{Remaining: time.Hour + 2*time.Minute + 3*time.Second})
+
+	expected := Message{Address: "/f1gopher/tower/2", Args: []any{int32(4), "NOR", "McLaren", "ff8000", "+2.500",
+		"+2.500", "1:21.234", "Soft", int32(5), int32(1), "On Track"}}
+	if tower := r.waitFor(t, "/f1gopher/tower/2"); !tower.equal(expected) {
+		t.Errorf("expected %v, got %v", expected, tower)
+	}
+
+	if name := r.waitFor(t, "/f1gopher/session/name"); name.Args[0] != "Italian Grand Prix - Race" {
+		t.Errorf("unexpected session name %v", name)
+	}
+	if flag := r.waitFor(t, "/f1gopher/flag"); flag.Args[0] != "Red" {
+		t.Errorf("unexpected flag %v", flag)
+	}
+	if safetyCar := r.waitFor(t, "/f1gopher/safetycar"); safetyCar.Args[0] != "Safety Car" {
+		t.Errorf("unexpected safety car %v", safetyCar)
+	}
+	if lap := r.waitFor(t, "/f1gopher/lap"); lap.Args[0] != int32(12) || lap.Args[1] != int32(53) {
+		t.Errorf("unexpected lap %v", lap)
+	}
+	clock := Message{Address: "/f1gopher/clock", Args: []any{"1:02:03", int32(3723), int32(1)}}
+	if current := r.waitFor(t, "/f1gopher/clock"); !current.equal(clock) {
+		t.Errorf("expected %v, got %v", clock, current)
+	}
+
+	sent, err := output.Stats()
+	if sent == 0 || err != nil {
+		t.Errorf("expected messages to be sent without errors, got %d %v", sent, err)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	r := listen(t)
+	output := start(t, Settings{Targets: []string{r.address()}, Rate: 2}, nil)
+
+	// All sent before the first tick so only the last should arrive
+	for x := 1; x <= 20; x++ {
+		output.ProcessTiming(Messages.Timing{Number: 1, Position: 1, LapsOnTire: x})
+	}
+
+	tower := r.waitFor(t, "/f1gopher/tower/1")
+	time.Sleep(600 * time.Millisecond)
+	if _, count := r.latest("/f1gopher/tower/1"); count != 1 || tower.Args[8] != int32(20) {
+		t.Errorf("expected the updates to be combined into one message, got %d ending with %v", count, tower)
+	}
+}
+
+func TestSnapshotOnConnect(t *testing.T) {
+	output := start(t, Settings{Rate: 20, SnapshotOnConnect: true, ListenAddress: "127.0.0.1:0"}, nil)
+	output.Reset("Singapore Grand Prix", Messages.QualifyingSession)
+	output.ProcessEvent(Messages.Event{Status: Messages.Started, TrackStatus: Messages.YellowFlag})
+
+	r := listen(t)
+	request, err := Message{Address: ConnectAddress}.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	local, err := net.ResolveUDPAddr("udp", output.LocalAddress())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = r.conn.WriteToUDP(request, local); err != nil {
+		t.Fatal(err)
+	}
+
+	// The current state is sent straight away
+	if name := r.waitFor(t, "/f1gopher/session/name"); name.Args[0] != "Singapore Grand Prix - Qualifying" {
+		t.Errorf("unexpected session name %v", name)
+	}
+	r.waitFor(t, "/f1gopher/flag")
+
+	// Then it gets the changes
+	output.ProcessEvent(Messages.Event{Status: Messages.Started, TrackStatus: Messages.GreenFlag})
+	for start := time.Now(); time.Since(start) < 5*time.Second; time.Sleep(10 * time.Millisecond) {
+		if flag, _ := r.latest("/f1gopher/flag"); flag.Args[0] == "Green" {
+			break
+		}
+	}
+	if flag, _ := r.latest("/f1gopher/flag"); flag.Args[0] != "Green" {
+		t.Errorf("expected the connected target to get changes, got %v", flag)
+	}
+	if targets := output.Targets(); len(targets) != 1 || targets[0] != r.address() {
+		t.Errorf("expected the connected target to be added, got %v", targets)
+	}
+}
+
+func TestSnapshotOnlyForTargets(t *testing.T) {
+	target := listen(t)
+	output := start(t, Settings{Rate: 20, SnapshotOnConnect: true, ListenAddress: "127.0.0.1:0", Targets: []string{target.address()}}, nil)
+	output.Reset("Singapore Grand Prix", Messages.QualifyingSession)
+	target.waitFor(t, "/f1gopher/session/name")
+
+	request, err := Message{Address: SnapshotAddress}.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	local, err := net.ResolveUDPAddr("udp", output.LocalAddress())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Anything else asking for a snapshot is ignored
+	other := listen(t)
+	if _, err = other.conn.WriteToUDP(request, local); err != nil {
+		t.Fatal(err)
+	}
+
+	// A target gets the state again
+	_, before := target.latest("/f1gopher/session/name")
+	if _, err = target.conn.WriteToUDP(request, local); err != nil {
+		t.Fatal(err)
+	}
+	for start := time.Now(); time.Since(start) < 5*time.Second; time.Sleep(10 * time.Millisecond) {
+		if _, count := target.latest("/f1gopher/session/name"); count > before {
+			break
+		}
+	}
+	if _, count := target.latest("/f1gopher/session/name"); count != before+1 {
+		t.Errorf("expected the target to get a snapshot, got %d session names after %d", count, before)
+	}
+
+	other.lock.Lock()
+	defer other.lock.Unlock()
+	if len(other.messages) != 0 {
+		t.Errorf("expected nothing sent to an address that isn't a target, got %v", other.messages)
+	}
+	if targets := output.Targets(); len(targets) != 1 {
+		t.Errorf("expected a snapshot request not to add a target, got %v", targets)
+	}
+}
+
+func TestBattles(t *testing.T) {
+	r := listen(t)
+	battles := []Battle{{Drivers: []int{1, 16}, Spread: 1500 * time.Millisecond, Trend: "Closing"}}
+	var lock sync.Mutex
+	output := start(t, Settings{Targets: []string{r.address()}, Rate: 20}, func() []Battle {
+		lock.Lock()
+		defer lock.Unlock()
+		return battles
+	})
+	output.ProcessDrivers(Messages.Drivers{Drivers: []Messages.DriverInfo{
+		{Number: 1, ShortName: "VER"}, {Number: 16, ShortName: "LEC"}}})
+
+	expected := Message{Address: "/f1gopher/battle/1", Args: []any{"Closing", float32(1.5), "VER - LEC", int32(1), int32(16)}}
+	if battle := r.waitFor(t, "/f1gopher/battle/1"); !battle.equal(expected) {
+		t.Errorf("expected %v, got %v", expected, battle)
+	}
+
+	lock.Lock()
+	battles = nil
+	lock.Unlock()
+
+	blank := Message{Address: "/f1gopher/battle/1", Args: []any{"", float32(0), ""}}
+	for start := time.Now(); time.Since(start) < 5*time.Second; time.Sleep(10 * time.Millisecond) {
+		if battle, _ := r.latest("/f1gopher/battle/1"); battle.equal(blank) {
+			break
+		}
+	}
+	if battle, _ := r.latest("/f1gopher/battle/1"); !battle.equal(blank) {
+		t.Errorf("expected the finished battle to be blanked, got %v", battle)
+	}
+	if count, _ := r.latest("/f1gopher/battles"); count.Args[0] != int32(0) {
+		t.Errorf("expected no battles, got %v", count)
+	}
+}
+
+func TestInvalidSettings(t *testing.T) {
+	if _, err := Start(Settings{Targets: []string{"localhost:9000"}}, nil); err == nil {
+		t.Errorf("expected an error without a rate")
+	}
+	if _, err := Start(Settings{Rate: 10}, nil); err == nil {
+		t.Errorf("expected an error without any targets")
+	}
+	if _, err := Start(Settings{Targets: []string{"not a target"}, Rate: 10}, nil); err == nil {
+		t.Errorf("expected an error for an invalid target")
+	}
+}
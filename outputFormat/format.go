@@ -0,0 +1,57 @@
+// F1Gopher - Copyright (C) 2025 f1gopher
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package outputFormat
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/f1gopher/f1gopherlib/Messages"
+)
+
+// LapTime formats a lap as m:ss.sss or empty if there isn't a time
+func LapTime(lap time.Duration) string {
+	if lap <= 0 {
+		return ""
+	}
+	minutes := int(lap.Minutes())
+	return fmt.Sprintf("%d:%06.3f", minutes, (lap - time.Duration(minutes)*time.Minute).Seconds())
+}
+
+// Clock formats the time remaining in a session as h:mm:ss
+func Clock(remaining time.Duration) string {
+	remaining = max(remaining, 0).Truncate(time.Second)
+	hours := int(remaining.Hours())
+	minutes := int(remaining.Minutes()) % 60
+	seconds := int(remaining.Seconds()) % 60
+	return fmt.Sprintf("%d:%02d:%02d", hours, minutes, seconds)
+}
+
+// SafetyCarStatus is the name of the safety car state
+func SafetyCarStatus(state Messages.TrackState) string {
+	switch state {
+	case Messages.SafetyCar:
+		return "Safety Car"
+	case Messages.SafetyCarEnding:
+		return "Safety Car Ending"
+	case Messages.VirtualSafetyCar:
+		return "VSC"
+	case Messages.VirtualSafetyCarEnding:
+		return "VSC Ending"
+	default:
+		return "Clear"
+	}
+}
@@ -0,0 +1,64 @@
+package outputFormat
+
+import (
+	"testing"
+	"time"
+
+	"github.com/f1gopher/f1gopherlib/Messages"
+)
+
+func TestLapTime(t *testing.T) {
+	tests := []struct {
+		lap      time.Duration
+		expected string
+	}{
+		{lap: 0, expected: ""},
+		{lap: -time.Second, expected: ""},
+		{lap: 59*time.Second + 5*time.Millisecond, expected: "0:59.005"},
+		{lap: 92*time.Second + 345*time.Millisecond, expected: "1:32.345"},
+		{lap: 10*time.Minute + 3*time.Second, expected: "10:03.000"},
+	}
+
+	for _, test := range tests {
+		if result := LapTime(test.lap); result != test.expected {
+			t.Errorf("%s: expected %q, got %q", test.lap, test.expected, result)
+		}
+	}
+}
+
+func TestClock(t *testing.T) {
+	tests := []struct {
+		remaining time.Duration
+		expected  string
+	}{
+		{remaining: 0, expected: "0:00:00"},
+		{remaining: -time.Minute, expected: "0:00:00"},
+		{remaining: 59*time.Minute + 59*time.Second + 900*time.Millisecond, expected: "0:59:59"},
+		{remaining: 2*time.Hour + 5*time.Second, expected: "2:00:05"},
+	}
+
+	for _, test := range tests {
+		if result := Clock(test.remaining); result != test.expected {
+			t.Errorf("%s: expected %q, got %q", test.remaining, test.expected, result)
+		}
+	}
+}
+
+func TestSafetyCarStatus(t *testing.T) {
+	tests := []struct {
+		state    Messages.TrackState
+		expected string
+	}{
+		{state: Messages.Clear, expected: "Clear"},
+		{state: Messages.SafetyCar, expected: "Safety Car"},
+		{state: Messages.SafetyCarEnding, expected: "Safety Car Ending"},
+		{state: Messages.VirtualSafetyCar, expected: "VSC"},
+		{state: Messages.VirtualSafetyCarEnding, expected: "VSC Ending"},
+	}
+
+	for _, test := range tests {
+		if result := SafetyCarStatus(test.state); result != test.expected {
+			t.Errorf("%d: expected %q, got %q", test.state, test.expected, result)
+		}
+	}
+}
@@ -0,0 +1,216 @@
+// F1Gopher - Copyright (C) 2025 f1gopher
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package raceControl
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type IncidentState int
+
+const (
+	Noted IncidentState = iota
+	UnderInvestigation
+	InvestigatedAfterSession
+	NoFurtherAction
+	Penalised
+)
+
+func (s IncidentState) String() string {
+	return [...]string{"Noted", "Under Investigation", "After Session", "No Further Action", "Penalty"}[s]
+}
+
+// Decided returns true once the stewards have made a decision and the incident is closed
+func (s IncidentState) Decided() bool {
+	return s == NoFurtherAction || s == Penalised
+}
+
+const stewardsPrefix = "FIA STEWARDS:"
+
+// The phrases that give the state of an incident, the text before them describes the incident
+var statePhrases = []struct {
+	state  IncidentState
+	phrase string
+}{
+	{state: UnderInvestigation, phrase: "UNDER INVESTIGATION"},
+	{state: InvestigatedAfterSession, phrase: "WILL BE INVESTIGATED AFTER"},
+	{state: NoFurtherAction, phrase: "REVIEWED NO FURTHER"},
+	{state: NoFurtherAction, phrase: "NO FURTHER"},
+	{state: Noted, phrase: "NOTED"},
+}
+
+var penaltySecondsRegex = regexp.MustCompile(`(\d+) SECOND`)
+
+// Incident links together the stewards messages about the same cars and reason
+type Incident struct {
+	Id int
+	// Like "TURN 1 INCIDENT INVOLVING CARS 3 (RIC) AND 4 (NOR)", empty if it was only mentioned in the decision
+	Description string
+	// Like "CAUSING A COLLISION", empty if the messages didn't give one
+	Reason string
+	Cars   []int
+	State  IncidentState
+	// The penalty given, like "5 SECOND TIME PENALTY", and the cars it was given to
+	Decision    string
+	Penalised   []int
+	PenaltyTime time.Duration
+	// Drive through and stop and go penalties are served in the pitlane
+	PitPenalty    bool
+	PenaltyServed bool
+
+	Opened   time.Time
+	Decided  time.Time
+	Messages []Message
+}
+
+// Elapsed returns how long the stewards took to decide, or how long the incident has been open so far
+func (i *Incident) Elapsed(now time.Time) time.Duration {
+	if i.State.Decided() {
+		return i.Decided.Sub(i.Opened)
+	}
+	return max(now.Sub(i.Opened), 0)
+}
+
+// IncidentTracker follows each incident from when it is noted to the stewards decision. Not safe for concurrent use.
+type IncidentTracker struct {
+	incidents []*Incident
+	nextId    int
+}
+
+// Process adds the stewards message to the incident it is about, or starts a new incident. Returns a copy of the
+// incident and true if the message was from the stewards.
+func (s *IncidentTracker) Process(msg Message) (Incident, bool) {
+	text, isStewards := strings.CutPrefix(msg.Msg, stewardsPrefix)
+	if !isStewards {
+		return Incident{}, false
+	}
+	text = strings.TrimSpace(text)
+
+	reason := ""
+	if index := strings.LastIndex(text, " - "); index >= 0 {
+		reason = strings.TrimSpace(text[index+3:])
+		text = text[:index]
+	}
+
+	if msg.PenaltyServed() {
+		for x := len(s.incidents) - 1; x >= 0; x-- {
+			incident := s.incidents[x]
+			if incident.State == Penalised && !incident.PenaltyServed && overlaps(incident.Penalised, msg.Cars) {
+				incident.PenaltyServed = true
+				incident.Messages = append(incident.Messages, msg)
+				return *incident, true
+			}
+		}
+		return Incident{}, true
+	}
+
+	if msg.Category == Penalties {
+		incident := s.find("", reason, msg.Cars)
+		if incident == nil {
+			incident = s.open(msg, "", reason)
+		}
+		incident.State = Penalised
+		incident.Decided = msg.Timestamp
+		incident.Penalised = msg.Cars
+		incident.Decision, _, _ = strings.Cut(text, " FOR CAR")
+		incident.PitPenalty = strings.Contains(text, "DRIVE THROUGH") || strings.Contains(text, "STOP AND GO")
+		if seconds := penaltySecondsRegex.FindStringSubmatch(text); seconds != nil {
+			value, _ := strconv.Atoi(seconds[1])
+			incident.PenaltyTime = time.Duration(value) * time.Second
+		}
+		incident.Messages = append(incident.Messages, msg)
+		return *incident, true
+	}
+
+	for _, state := range statePhrases {
+		index := strings.Index(text, state.phrase)
+		if index < 0 {
+			continue
+		}
+
+		description := strings.TrimSpace(text[:index])
+		incident := s.find(description, reason, msg.Cars)
+		if incident == nil {
+			incident = s.open(msg, description, reason)
+		}
+		incident.State = state.state
+		if state.state.Decided() {
+			incident.Decided = msg.Timestamp
+		}
+		incident.Messages = append(incident.Messages, msg)
+		return *incident, true
+	}
+
+	return Incident{}, true
+}
+
+// All returns a copy of every incident in the order they were opened
+func (s *IncidentTracker) All() []Incident {
+	result := make([]Incident, 0, len(s.incidents))
+	for _, incident := range s.incidents {
+		result = append(result, *incident)
+	}
+	return result
+}
+
+func (s *IncidentTracker) open(msg Message, description string, reason string) *Incident {
+	s.nextId++
+	incident := &Incident{
+		Id:          s.nextId,
+		Description: description,
+		Reason:      reason,
+		Cars:        msg.Cars,
+		Opened:      msg.Timestamp,
+	}
+	s.incidents = append(s.incidents, incident)
+	return incident
+}
+
+// find returns the most recent open incident with the same description or, failing that, one involving the cars for
+// the same reason
+func (s *IncidentTracker) find(description string, reason string, cars []int) *Incident {
+	for x := len(s.incidents) - 1; x >= 0; x-- {
+		incident := s.incidents[x]
+		if !incident.State.Decided() && description != "" && incident.Description == description {
+			return incident
+		}
+	}
+
+	for x := len(s.incidents) - 1; x >= 0; x-- {
+		incident := s.incidents[x]
+		if incident.State.Decided() || !overlaps(incident.Cars, cars) {
+			continue
+		}
+		if reason == "" || incident.Reason == "" || incident.Reason == reason {
+			return incident
+		}
+	}
+	return nil
+}
+
+func overlaps(a []int, b []int) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if x == y {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+package raceControl
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/f1gopher/f1gopherlib/Messages"
+)
+
+func stewardsMessage(start time.Time, minutes int, msg string) Message {
+	return Parse(Messages.RaceControlMessage{Timestamp: start.Add(time.Duration(minutes) * time.Minute), Msg: msg})
+}
+
+func TestIncidentLifecycle(t *testing.T) {
+	start := time.Date(2024, 7, 7, 15, 0, 0, 0, time.UTC)
+	incidents := IncidentTracker{}
+
+	messages := []struct {
+		minutes  int
+		msg      string
+		expected IncidentState
+	}{
+		{minutes: 0, msg: "FIA STEWARDS: TURN 1 INCIDENT INVOLVING CARS 3 (RIC) AND 4 (NOR) NOTED - CAUSING A COLLISION", expected: Noted},
+		{minutes: 2, msg: "FIA STEWARDS: TURN 1 INCIDENT INVOLVING CARS 3 (RIC) AND 4 (NOR) UNDER INVESTIGATION - CAUSING A COLLISION", expected: UnderInvestigation},
+		{minutes: 6, msg: "FIA STEWARDS: 5 SECOND TIME PENALTY FOR CAR 3 (RIC) - CAUSING A COLLISION", expected: Penalised},
+	}
+
+	for _, message := range messages {
+		incident, isStewards := incidents.Process(stewardsMessage(start, message.minutes, message.msg))
+		if !isStewards {
+			t.Fatalf("%s: expected a stewards message", message.msg)
+		}
+		if incident.Id != 1 || incident.State != message.expected {
+			t.Errorf("%s: expected incident 1 %s, got incident %d %s", message.msg, message.expected, incident.Id, incident.State)
+		}
+	}
+
+	all := incidents.All()
+	if len(all) != 1 {
+		t.Fatalf("expected 1 incident, got %d", len(all))
+	}
+	incident := all[0]
+	if incident.Description != "TURN 1 INCIDENT INVOLVING CARS 3 (RIC) AND 4 (NOR)" || incident.Reason != "CAUSING A COLLISION" {
+		t.Errorf("unexpected description %q and reason %q", incident.Description, incident.Reason)
+	}
+	if !slices.Equal(incident.Cars, []int{3, 4}) || !slices.Equal(incident.Penalised, []int{3}) {
+		t.Errorf("unexpected cars %v and penalised %v", incident.Cars, incident.Penalised)
+	}
+	if incident.Decision != "5 SECOND TIME PENALTY" || incident.PenaltyTime != 5*time.Second || incident.PitPenalty {
+		t.Errorf("unexpected decision %q %s", incident.Decision, incident.PenaltyTime)
+	}
+	if elapsed := incident.Elapsed(start.Add(time.Hour)); elapsed != 6*time.Minute {
+		t.Errorf("expected the decision after 6 minutes, got %s", elapsed)
+	}
+	if len(incident.Messages) != 3 {
+		t.Errorf("expected 3 messages, got %d", len(incident.Messages))
+	}
+
+	served, _ := incidents.Process(stewardsMessage(start, 20, "FIA STEWARDS: 5 SECOND TIME PENALTY SERVED BY CAR 3 (RIC)"))
+	if served.Id != 1 || !served.PenaltyServed {
+		t.Errorf("expected the penalty for incident 1 to be served")
+	}
+}
+
+func TestIncidentNoFurtherAction(t *testing.T) {
+	start := time.Date(2024, 7, 7, 15, 0, 0, 0, time.UTC)
+	incidents := IncidentTracker{}
+
+	incidents.Process(stewardsMessage(start, 0, "FIA STEWARDS: LAP 1 TURN 4 INCIDENT INVOLVING CAR 18 (STR) NOTED - MOVING UNDER BRAKING"))
+	incidents.Process(stewardsMessage(start, 1, "FIA STEWARDS: UNSAFE RELEASE OF CAR 44 (HAM) UNDER INVESTIGATION"))
+	decided, _ := incidents.Process(stewardsMessage(start, 3, "FIA STEWARDS: LAP 1 TURN 4 INCIDENT INVOLVING CAR 18 (STR) REVIEWED NO FURTHER INVESTIGATION"))
+
+	if decided.Id != 1 || decided.State != NoFurtherAction || decided.Elapsed(start.Add(time.Hour)) != 3*time.Minute {
+		t.Errorf("expected incident 1 closed after 3 minutes, got incident %d %s", decided.Id, decided.State)
+	}
+
+	all := incidents.All()
+	if len(all) != 2 || all[1].State.Decided() || all[1].Elapsed(start.Add(5*time.Minute)) != 4*time.Minute {
+		t.Errorf("expected the unsafe release to still be open")
+	}
+
+	// Penalties for incidents that weren't noted first start a new incident
+	penalty, _ := incidents.Process(stewardsMessage(start, 10, "FIA STEWARDS: DRIVE THROUGH PENALTY FOR CAR 1 (VER) - IGNORING YELLOW FLAGS"))
+	if penalty.Id != 3 || !penalty.PitPenalty || penalty.Decision != "DRIVE THROUGH PENALTY" {
+		t.Errorf("expected a new drive through incident, got %d %q", penalty.Id, penalty.Decision)
+	}
+
+	if _, isStewards := incidents.Process(stewardsMessage(start, 11, "SAFETY CAR DEPLOYED")); isStewards {
+		t.Errorf("only stewards messages are incidents")
+	}
+}
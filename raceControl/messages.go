@@ -0,0 +1,149 @@
+// F1Gopher - Copyright (C) 2025 f1gopher
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package raceControl
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/f1gopher/f1gopherlib/Messages"
+)
+
+type Category int
+
+const (
+	Other Category = iota
+	Flags
+	SafetyCar
+	Penalties
+	Investigations
+	TrackLimits
+	DRS
+	PitExit
+	Weather
+	Incidents
+)
+
+func (c Category) String() string {
+	return [...]string{"Other", "Flags", "SC/VSC", "Penalties", "Investigations", "Track Limits", "DRS", "Pit Exit",
+		"Weather", "Incidents"}[c]
+}
+
+// Categories returns every category in display order
+func Categories() []Category {
+	return []Category{Flags, SafetyCar, Penalties, Investigations, TrackLimits, DRS, PitExit, Weather, Incidents, Other}
+}
+
+// Race control messages refer to cars as "CAR 44 (HAM)" or "CARS 1 (VER) AND 44 (HAM)" and turns in the same way
+var carsRegex = regexp.MustCompile(`CARS? ((?:\d+(?: \([A-Z]{3}\))?(?:,| AND| &)? ?)+)`)
+var turnsRegex = regexp.MustCompile(`TURNS? ((?:\d+(?:,| AND| &)? ?)+)`)
+var numberRegex = regexp.MustCompile(`\d+`)
+var lapRegex = regexp.MustCompile(`\bLAP (\d+)\b`)
+
+// The phrases that decide the category, checked in this order because a message can contain more than one. For
+// example a penalty can be for track limits and an incident is usually reported as being noted by the stewards.
+var categoryPhrases = []struct {
+	category Category
+	phrases  []string
+}{
+	{category: Penalties, phrases: []string{"PENALTY", "REPRIMAND", "DRIVE THROUGH", "STOP AND GO", "DISQUALIFIED"}},
+	{category: Investigations, phrases: []string{"NOTED", "UNDER INVESTIGATION", "WILL BE INVESTIGATED", "NO FURTHER"}},
+	{category: TrackLimits, phrases: []string{"TRACK LIMITS", "DELETED", "REINSTATED"}},
+	{category: DRS, phrases: []string{"DRS"}},
+	{category: SafetyCar, phrases: []string{"SAFETY CAR", "VSC"}},
+	{category: PitExit, phrases: []string{"PIT EXIT", "PIT ENTRY"}},
+	{category: Flags, phrases: []string{"FLAG", "TRACK CLEAR", "IN TRACK SECTOR"}},
+	{category: Weather, phrases: []string{"RAIN", "WET", "WEATHER"}},
+	{category: Incidents, phrases: []string{"INCIDENT", "STOPPED", "COLLISION", "CRASH", "SPUN", "DEBRIS", "RECOVERY VEHICLE", "MEDICAL CAR"}},
+}
+
+// Message is a race control message with the details that can be found in the text. Lap is zero if the message
+// doesn't mention one.
+type Message struct {
+	Messages.RaceControlMessage
+
+	Category Category
+	Cars     []int
+	Turns    []int
+	Lap      int
+}
+
+// Parse finds the category of the message and the cars, turns and lap it mentions
+func Parse(msg Messages.RaceControlMessage) Message {
+	result := Message{
+		RaceControlMessage: msg,
+		Category:           category(msg),
+		Cars:               numbers(carsRegex, msg.Msg),
+		Turns:              numbers(turnsRegex, msg.Msg),
+	}
+
+	if lap := lapRegex.FindStringSubmatch(msg.Msg); lap != nil {
+		result.Lap, _ = strconv.Atoi(lap[1])
+	}
+
+	return result
+}
+
+// MentionsCar returns true if the message refers to the car number
+func (m *Message) MentionsCar(carNumber int) bool {
+	for _, car := range m.Cars {
+		if car == carNumber {
+			return true
+		}
+	}
+	return false
+}
+
+// PenaltyServed returns true if the message says a penalty has been served rather than given
+func (m *Message) PenaltyServed() bool {
+	return m.Category == Penalties && strings.Contains(m.Msg, "PENALTY SERVED")
+}
+
+// ForTrackLimits returns true if the message gives track limits as the reason. This can be in any category, like a
+// penalty or a deleted lap, and not every deleted lap is for track limits.
+func (m *Message) ForTrackLimits() bool {
+	return strings.Contains(m.Msg, "TRACK LIMITS")
+}
+
+func category(msg Messages.RaceControlMessage) Category {
+	for _, category := range categoryPhrases {
+		for _, phrase := range category.phrases {
+			if strings.Contains(msg.Msg, phrase) {
+				return category.category
+			}
+		}
+	}
+
+	// Some flag messages only say where they are, like "CLEAR IN TRACK SECTOR 4"
+	if msg.Flag != Messages.NoFlag {
+		return Flags
+	}
+	return Other
+}
+
+// numbers returns every number in the lists matched by the regex, ignoring the driver abbreviations
+func numbers(regex *regexp.Regexp, msg string) []int {
+	result := []int{}
+	for _, list := range regex.FindAllStringSubmatch(msg, -1) {
+		for _, number := range numberRegex.FindAllString(list[1], -1) {
+			if value, err := strconv.Atoi(number); err == nil {
+				result = append(result, value)
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,89 @@
+package raceControl
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/f1gopher/f1gopherlib/Messages"
+)
+
+func TestParseCategory(t *testing.T) {
+	tests := []struct {
+		msg      string
+		flag     Messages.FlagState
+		expected Category
+	}{
+		{msg: "DOUBLE YELLOW IN TRACK SECTOR 5", flag: Messages.DoubleYellowFlag, expected: Flags},
+		{msg: "CLEAR IN TRACK SECTOR 5", flag: Messages.NoFlag, expected: Flags},
+		{msg: "BLUE FLAG FOR CAR 2 (SAR) TIMED AT 15:43:07", flag: Messages.BlueFlag, expected: Flags},
+		{msg: "CHEQUERED FLAG", flag: Messages.ChequeredFlag, expected: Flags},
+		{msg: "SAFETY CAR DEPLOYED", expected: SafetyCar},
+		{msg: "VSC ENDING", expected: SafetyCar},
+		{msg: "LAPPED CARS MAY NOW OVERTAKE THE SAFETY CAR", expected: SafetyCar},
+		{msg: "FIA STEWARDS: 5 SECOND TIME PENALTY FOR CAR 1 (VER) - TRACK LIMITS", expected: Penalties},
+		{msg: "FIA STEWARDS: DRIVE THROUGH PENALTY SERVED BY CAR 20 (MAG)", expected: Penalties},
+		{msg: "FIA STEWARDS: TURN 1 INCIDENT INVOLVING CARS 3 (RIC) AND 4 (NOR) NOTED - CAUSING A COLLISION", expected: Investigations},
+		{msg: "FIA STEWARDS: OVERTAKING UNDER SAFETY CAR BY CAR 16 (LEC) UNDER INVESTIGATION", expected: Investigations},
+		{msg: "FIA STEWARDS: TURN 1 INCIDENT INVOLVING CAR 18 (STR) REVIEWED NO FURTHER INVESTIGATION", expected: Investigations},
+		{msg: "CAR 4 (NOR) TIME 1:27.529 DELETED - TRACK LIMITS AT TURN 1 LAP 2 15:11:38", expected: TrackLimits},
+		{msg: "BLACK AND WHITE FLAG FOR CAR 11 (PER) - TRACK LIMITS", flag: Messages.BlackAndWhite, expected: TrackLimits},
+		{msg: "DRS ENABLED", expected: DRS},
+		{msg: "GREEN LIGHT - PIT EXIT OPEN", flag: Messages.GreenFlag, expected: PitExit},
+		{msg: "RISK OF RAIN FOR F1 RACE IS 40%", expected: Weather},
+		{msg: "CAR 14 (ALO) STOPPED AT TURN 4", expected: Incidents},
+		{msg: "AWNINGS MAY BE USED", expected: Other},
+	}
+
+	for _, test := range tests {
+		parsed := Parse(Messages.RaceControlMessage{Msg: test.msg, Flag: test.flag})
+		if parsed.Category != test.expected {
+			t.Errorf("%s: expected %s, got %s", test.msg, test.expected, parsed.Category)
+		}
+	}
+}
+
+func TestParseDetails(t *testing.T) {
+	tests := []struct {
+		msg   string
+		cars  []int
+		turns []int
+		lap   int
+	}{
+		{msg: "CAR 4 (NOR) TIME 1:27.529 DELETED - TRACK LIMITS AT TURN 1 LAP 2 15:11:38", cars: []int{4}, turns: []int{1}, lap: 2},
+		{msg: "FIA STEWARDS: TURN 1 INCIDENT INVOLVING CARS 3 (RIC) AND 4 (NOR) NOTED - CAUSING A COLLISION", cars: []int{3, 4}, turns: []int{1}},
+		{msg: "FIA STEWARDS: LAP 1 TURNS 1 AND 2 INCIDENT INVOLVING CARS 1 (VER), 44 (HAM) & 63 (RUS) NOTED", cars: []int{1, 44, 63}, turns: []int{1, 2}, lap: 1},
+		{msg: "SAFETY CAR DEPLOYED", cars: []int{}, turns: []int{}},
+	}
+
+	for _, test := range tests {
+		parsed := Parse(Messages.RaceControlMessage{Msg: test.msg})
+		if !slices.Equal(parsed.Cars, test.cars) {
+			t.Errorf("%s: expected cars %v, got %v", test.msg, test.cars, parsed.Cars)
+		}
+		if !slices.Equal(parsed.Turns, test.turns) {
+			t.Errorf("%s: expected turns %v, got %v", test.msg, test.turns, parsed.Turns)
+		}
+		if parsed.Lap != test.lap {
+			t.Errorf("%s: expected lap %d, got %d", test.msg, test.lap, parsed.Lap)
+		}
+	}
+
+	parsed := Parse(Messages.RaceControlMessage{Msg: "BLUE FLAG FOR CAR 2 (SAR) TIMED AT 15:43:07"})
+	if !parsed.MentionsCar(2) || parsed.MentionsCar(15) {
+		t.Errorf("only car 2 is mentioned, got %v", parsed.Cars)
+	}
+
+	if parsed = Parse(Messages.RaceControlMessage{Msg: "FIA STEWARDS: DRIVE THROUGH PENALTY SERVED BY CAR 20 (MAG)"}); !parsed.PenaltyServed() {
+		t.Errorf("expected the penalty to be served")
+	}
+	if parsed = Parse(Messages.RaceControlMessage{Msg: "FIA STEWARDS: 5 SECOND TIME PENALTY FOR CAR 1 (VER) - TRACK LIMITS"}); parsed.PenaltyServed() {
+		t.Errorf("expected a new penalty")
+	}
+
+	if parsed = Parse(Messages.RaceControlMessage{Msg: "CAR 4 (NOR) TIME 1:27.529 DELETED - TRACK LIMITS AT TURN 1 LAP 2 15:11:38"}); !parsed.ForTrackLimits() {
+		t.Errorf("expected the lap to be deleted for track limits")
+	}
+	if parsed = Parse(Messages.RaceControlMessage{Msg: "CAR 16 (LEC) LAP DELETED - FAILING TO SLOW FOR DOUBLE YELLOW FLAGS"}); parsed.ForTrackLimits() {
+		t.Errorf("expected the lap to be deleted for another reason")
+	}
+}
@@ -0,0 +1,90 @@
+// F1Gopher - Copyright (C) 2025 f1gopher
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package regulations
+
+import (
+	"time"
+
+	"github.com/f1gopher/f1gopherlib/Messages"
+)
+
+// OvertakeGap is how close a car needs to be to the car ahead to use the overtaking aid in a race
+const OvertakeGap = time.Second
+
+type OvertakeAid int
+
+const (
+	// DRS opens the rear wing for a car within a second of the car ahead
+	DRS OvertakeAid = iota
+	// From 2026 every car uses active aero on the straights and DRS is replaced by extra electrical energy (overtake
+	// mode) for a car within a second of the car ahead
+	OvertakeMode
+)
+
+func (o OvertakeAid) String() string {
+	return [...]string{"DRS", "Overtake"}[o]
+}
+
+// OvertakeAidForSeason returns the overtaking aid used in the season
+func OvertakeAidForSeason(season int) OvertakeAid {
+	if season >= 2026 {
+		return OvertakeMode
+	}
+	return DRS
+}
+
+// ReportsState returns true if the timing data says when a car is using the overtaking aid. The DRS flap state is
+// sent with the car data but the overtake mode and active aero state are not.
+func (o OvertakeAid) ReportsState() bool {
+	return o == DRS
+}
+
+// Enabled returns true if race control currently allows the overtaking aid to be used
+func (o OvertakeAid) Enabled(event Messages.Event) bool {
+	switch o {
+	case DRS:
+		return event.DRSEnabled != Messages.DRSDisabled
+	default:
+		// Only DRS has race control messages to enable it so the overtake mode is available unless the safety car is out
+		return event.SafetyCar == Messages.Clear
+	}
+}
+
+// EnabledState is the race control state for the overtaking aid to display
+func (o OvertakeAid) EnabledState(event Messages.Event) string {
+	if o == DRS {
+		return event.DRSEnabled.String()
+	}
+	if o.Enabled(event) {
+		return "Enabled"
+	}
+	return "Disabled"
+}
+
+// Eligible returns true if a car can use the overtaking aid with the gap to the car ahead. Outside of races DRS can be
+// used anywhere but the overtake mode is only for attacking another car during a race.
+func (o OvertakeAid) Eligible(event Messages.Event, gapToCarAhead time.Duration) bool {
+	if !o.Enabled(event) {
+		return false
+	}
+
+	isRace := event.Type == Messages.Race || event.Type == Messages.Sprint
+	if !isRace {
+		return o == DRS
+	}
+
+	return gapToCarAhead > 0 && gapToCarAhead < OvertakeGap
+}
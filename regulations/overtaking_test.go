@@ -0,0 +1,64 @@
+package regulations
+
+import (
+	"testing"
+	"time"
+
+	"github.com/f1gopher/f1gopherlib/Messages"
+)
+
+func TestOvertakeAidForSeason(t *testing.T) {
+	tests := []struct {
+		season   int
+		expected OvertakeAid
+	}{
+		{season: 2018, expected: DRS},
+		{season: 2025, expected: DRS},
+		{season: 2026, expected: OvertakeMode},
+		{season: 2027, expected: OvertakeMode},
+	}
+
+	for _, test := range tests {
+		if aid := OvertakeAidForSeason(test.season); aid != test.expected {
+			t.Errorf("%d: expected %s, got %s", test.season, test.expected, aid)
+		}
+	}
+
+	if !DRS.ReportsState() || OvertakeMode.ReportsState() {
+		t.Errorf("only DRS reports when it is being used")
+	}
+}
+
+func TestOvertakeEligible(t *testing.T) {
+	race := Messages.Event{Type: Messages.Race, DRSEnabled: Messages.DRSEnabled, SafetyCar: Messages.Clear}
+	raceDRSDisabled := Messages.Event{Type: Messages.Race, DRSEnabled: Messages.DRSDisabled, SafetyCar: Messages.Clear}
+	safetyCar := Messages.Event{Type: Messages.Race, DRSEnabled: Messages.DRSDisabled, SafetyCar: Messages.SafetyCar}
+	sprint := Messages.Event{Type: Messages.Sprint, DRSEnabled: Messages.DRSUnknown, SafetyCar: Messages.Clear}
+	practice := Messages.Event{Type: Messages.Practice1, DRSEnabled: Messages.DRSUnknown, SafetyCar: Messages.Clear}
+
+	tests := []struct {
+		name     string
+		aid      OvertakeAid
+		event    Messages.Event
+		gap      time.Duration
+		expected bool
+	}{
+		{name: "DRS within a second", aid: DRS, event: race, gap: 900 * time.Millisecond, expected: true},
+		{name: "DRS too far behind", aid: DRS, event: race, gap: 1200 * time.Millisecond, expected: false},
+		{name: "DRS leader", aid: DRS, event: race, gap: 0, expected: false},
+		{name: "DRS disabled", aid: DRS, event: raceDRSDisabled, gap: 500 * time.Millisecond, expected: false},
+		{name: "DRS in practice", aid: DRS, event: practice, gap: 0, expected: true},
+		{name: "Overtake within a second", aid: OvertakeMode, event: race, gap: 900 * time.Millisecond, expected: true},
+		{name: "Overtake after race control disabled DRS", aid: OvertakeMode, event: raceDRSDisabled, gap: 900 * time.Millisecond, expected: true},
+		{name: "Overtake in the sprint", aid: OvertakeMode, event: sprint, gap: 400 * time.Millisecond, expected: true},
+		{name: "Overtake too far behind", aid: OvertakeMode, event: race, gap: 1500 * time.Millisecond, expected: false},
+		{name: "Overtake behind the safety car", aid: OvertakeMode, event: safetyCar, gap: 300 * time.Millisecond, expected: false},
+		{name: "Overtake in practice", aid: OvertakeMode, event: practice, gap: 300 * time.Millisecond, expected: false},
+	}
+
+	for _, test := range tests {
+		if eligible := test.aid.Eligible(test.event, test.gap); eligible != test.expected {
+			t.Errorf("%s: expected %t, got %t", test.name, test.expected, eligible)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+// F1Gopher - Copyright (C) 2025 f1gopher
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package regulations
+
+import "github.com/f1gopher/f1gopherlib/Messages"
+
+// pointsSystem is the points awarded for a session type from a season onwards until replaced by a later season
+type pointsSystem struct {
+	fromSeason int
+	session    Messages.SessionType
+	// Points for each finishing position starting from first
+	points []int
+	// Extra points for the fastest lap if the driver finishes in the fastestLapPositions
+	fastestLap          int
+	fastestLapPositions int
+}
+
+// Ordered by season, the last matching entry wins
+var pointsTable = []pointsSystem{
+	{fromSeason: 2010, session: Messages.RaceSession, points: []int{25, 18, 15, 12, 10, 8, 6, 4, 2, 1}},
+	{fromSeason: 2019, session: Messages.RaceSession, points: []int{25, 18, 15, 12, 10, 8, 6, 4, 2, 1}, fastestLap: 1, fastestLapPositions: 10},
+	{fromSeason: 2025, session: Messages.RaceSession, points: []int{25, 18, 15, 12, 10, 8, 6, 4, 2, 1}},
+	{fromSeason: 2021, session: Messages.SprintSession, points: []int{3, 2, 1}},
+	{fromSeason: 2022, session: Messages.SprintSession, points: []int{8, 7, 6, 5, 4, 3, 2, 1}},
+}
+
+func findPointsSystem(season int, session Messages.SessionType) (pointsSystem, bool) {
+	var result pointsSystem
+	found := false
+	for _, entry := range pointsTable {
+		if entry.session == session && entry.fromSeason <= season {
+			result = entry
+			found = true
+		}
+	}
+	return result, found
+}
+
+// Points returns the championship points for finishing in the position (starting from 1) in a race or sprint session
+func Points(season int, session Messages.SessionType, position int) int {
+	system, found := findPointsSystem(season, session)
+	if !found || position < 1 || position > len(system.points) {
+		return 0
+	}
+	return system.points[position-1]
+}
+
+// FastestLapPoints returns the extra points for setting the fastest lap and finishing in the position
+func FastestLapPoints(season int, session Messages.SessionType, position int) int {
+	system, found := findPointsSystem(season, session)
+	if !found || position < 1 || position > system.fastestLapPositions {
+		return 0
+	}
+	return system.fastestLap
+}
+
+// ScoresPoints returns true if the session type awards championship points in the season
+func ScoresPoints(season int, session Messages.SessionType) bool {
+	_, found := findPointsSystem(season, session)
+	return found
+}
@@ -0,0 +1,63 @@
+package regulations
+
+import (
+	"testing"
+
+	"github.com/f1gopher/f1gopherlib/Messages"
+)
+
+func TestPoints(t *testing.T) {
+	tests := []struct {
+		name     string
+		season   int
+		session  Messages.SessionType
+		position int
+		expected int
+	}{
+		{name: "2018 race win", season: 2018, session: Messages.RaceSession, position: 1, expected: 25},
+		{name: "2018 race tenth", season: 2018, session: Messages.RaceSession, position: 10, expected: 1},
+		{name: "2018 race eleventh", season: 2018, session: Messages.RaceSession, position: 11, expected: 0},
+		{name: "2018 sprint", season: 2018, session: Messages.SprintSession, position: 1, expected: 0},
+		{name: "2021 sprint win", season: 2021, session: Messages.SprintSession, position: 1, expected: 3},
+		{name: "2021 sprint fourth", season: 2021, session: Messages.SprintSession, position: 4, expected: 0},
+		{name: "2022 sprint win", season: 2022, session: Messages.SprintSession, position: 1, expected: 8},
+		{name: "2022 sprint eighth", season: 2022, session: Messages.SprintSession, position: 8, expected: 1},
+		{name: "2026 race second", season: 2026, session: Messages.RaceSession, position: 2, expected: 18},
+		{name: "2026 sprint ninth", season: 2026, session: Messages.SprintSession, position: 9, expected: 0},
+		{name: "Qualifying", season: 2024, session: Messages.QualifyingSession, position: 1, expected: 0},
+		{name: "No position", season: 2024, session: Messages.RaceSession, position: 0, expected: 0},
+	}
+
+	for _, test := range tests {
+		if points := Points(test.season, test.session, test.position); points != test.expected {
+			t.Errorf("%s: expected %d points, got %d", test.name, test.expected, points)
+		}
+	}
+}
+
+func TestFastestLapPoints(t *testing.T) {
+	tests := []struct {
+		name     string
+		season   int
+		session  Messages.SessionType
+		position int
+		expected int
+	}{
+		{name: "2018 race", season: 2018, session: Messages.RaceSession, position: 1, expected: 0},
+		{name: "2019 race in the points", season: 2019, session: Messages.RaceSession, position: 10, expected: 1},
+		{name: "2019 race out of the points", season: 2019, session: Messages.RaceSession, position: 11, expected: 0},
+		{name: "2024 race", season: 2024, session: Messages.RaceSession, position: 3, expected: 1},
+		{name: "2024 sprint", season: 2024, session: Messages.SprintSession, position: 1, expected: 0},
+		{name: "2025 race", season: 2025, session: Messages.RaceSession, position: 1, expected: 0},
+	}
+
+	for _, test := range tests {
+		if points := FastestLapPoints(test.season, test.session, test.position); points != test.expected {
+			t.Errorf("%s: expected %d points, got %d", test.name, test.expected, points)
+		}
+	}
+
+	if ScoresPoints(2020, Messages.SprintSession) || !ScoresPoints(2021, Messages.SprintSession) {
+		t.Errorf("sprints only score points from 2021")
+	}
+}
@@ -0,0 +1,208 @@
+// F1Gopher - Copyright (C) 2025 f1gopher
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package regulations
+
+import (
+	"time"
+
+	"github.com/f1gopher/f1gopherlib"
+	"github.com/f1gopher/f1gopherlib/Messages"
+)
+
+type QualifyingType int
+
+const (
+	Qualifying QualifyingType = iota
+	SprintQualifying
+)
+
+func (q QualifyingType) String() string {
+	return [...]string{"Qualifying", "Sprint Qualifying"}[q]
+}
+
+// qualifyingRules is the qualifying format from a season onwards until replaced by a later season
+type qualifyingRules struct {
+	fromSeason int
+	session    QualifyingType
+	// Number of cars entered when the grid size isn't known yet
+	gridSize int
+	segments int
+	// Number of cars that take part in the final segment, the rest are eliminated evenly across the earlier segments
+	finalSegmentCars int
+	rule107          bool
+}
+
+// Ordered by season, the last matching entry wins
+var qualifyingTable = []qualifyingRules{
+	{fromSeason: 2018, session: Qualifying, gridSize: 20, segments: 3, finalSegmentCars: 10, rule107: true},
+	// 2021 and 2022 sprint qualifying was the Friday qualifying session, from 2023 it is a separate shootout
+	{fromSeason: 2018, session: SprintQualifying, gridSize: 20, segments: 3, finalSegmentCars: 10, rule107: true},
+	{fromSeason: 2023, session: SprintQualifying, gridSize: 20, segments: 3, finalSegmentCars: 10, rule107: false},
+	{fromSeason: 2026, session: Qualifying, gridSize: 22, segments: 3, finalSegmentCars: 10, rule107: true},
+	{fromSeason: 2026, session: SprintQualifying, gridSize: 22, segments: 3, finalSegmentCars: 10, rule107: false},
+}
+
+// First season the sprint grid was set by its own qualifying session instead of the Friday qualifying session
+const sprintShootoutSeason = 2023
+
+// QualifyingSessionType returns the type of the qualifying session that starts at sessionStart. The timing feed
+// reports sprint qualifying as a normal qualifying session so it is worked out from the weekend's schedule.
+func QualifyingSessionType(sessionStart time.Time) QualifyingType {
+	events := f1gopherlib.RaceHistory()
+	live, next, hasLive, hasNext := f1gopherlib.HappeningSessions()
+	if hasLive {
+		events = append(events, live)
+	}
+	if hasNext {
+		events = append(events, next)
+	}
+	return qualifyingSessionType(events, sessionStart)
+}
+
+// qualifyingSessionType finds the qualifying session starting at sessionStart in events. From 2023 the sprint
+// qualifying session is the last qualifying session before the sprint on the same weekend.
+func qualifyingSessionType(events []f1gopherlib.RaceEvent, sessionStart time.Time) QualifyingType {
+	if sessionStart.Year() < sprintShootoutSeason {
+		return Qualifying
+	}
+
+	var raceTime time.Time
+	for _, event := range events {
+		if event.Type == Messages.QualifyingSession && event.EventTime.Equal(sessionStart) {
+			raceTime = event.RaceTime
+			break
+		}
+	}
+	if raceTime.IsZero() {
+		return Qualifying
+	}
+
+	var sprintStart time.Time
+	for _, event := range events {
+		if event.Type == Messages.SprintSession && event.RaceTime.Equal(raceTime) {
+			sprintStart = event.EventTime
+			break
+		}
+	}
+	if sprintStart.IsZero() || !sessionStart.Before(sprintStart) {
+		return Qualifying
+	}
+
+	// Another qualifying session between this one and the sprint means this one sets the race grid
+	for _, event := range events {
+		if event.Type == Messages.QualifyingSession &&
+			event.RaceTime.Equal(raceTime) &&
+			event.EventTime.After(sessionStart) &&
+			event.EventTime.Before(sprintStart) {
+			return Qualifying
+		}
+	}
+
+	return SprintQualifying
+}
+
+// QualifyingFormat is how a qualifying session is run for a season and grid size
+type QualifyingFormat struct {
+	Season   int
+	Session  QualifyingType
+	GridSize int
+	// Number of segments (Q1, Q2, Q3...)
+	Segments int
+	// Number of cars eliminated at the end of each segment, the last segment eliminates nobody
+	Eliminated []int
+	// Cars slower than 107% of the fastest time in the first segment need permission to start the race
+	Rule107 bool
+}
+
+// QualifyingRules returns the qualifying format for the season. If the grid size isn't known (0) the usual grid size
+// for the season is used.
+func QualifyingRules(season int, session QualifyingType, gridSize int) QualifyingFormat {
+	rules := qualifyingTable[0]
+	for _, entry := range qualifyingTable {
+		if entry.session == session && entry.fromSeason <= season {
+			rules = entry
+		}
+	}
+
+	if gridSize <= 0 {
+		gridSize = rules.gridSize
+	}
+
+	format := QualifyingFormat{
+		Season:     season,
+		Session:    session,
+		GridSize:   gridSize,
+		Segments:   rules.segments,
+		Eliminated: make([]int, rules.segments),
+		Rule107:    rules.rule107,
+	}
+
+	// Split the eliminated cars evenly between the earlier segments, any extra cars drop out in the first segment
+	eliminated := max(gridSize-rules.finalSegmentCars, 0)
+	knockoutSegments := rules.segments - 1
+	for x := 0; x < knockoutSegments; x++ {
+		format.Eliminated[x] = eliminated / knockoutSegments
+	}
+	if knockoutSegments > 0 {
+		format.Eliminated[0] += eliminated % knockoutSegments
+	}
+
+	return format
+}
+
+// Segment returns the qualifying segment (starting from 1) for the event type or 0 if it isn't a qualifying segment
+func (q QualifyingFormat) Segment(eventType Messages.EventType) int {
+	switch eventType {
+	case Messages.Qualifying1:
+		return 1
+	case Messages.Qualifying2:
+		return 2
+	case Messages.Qualifying3:
+		return 3
+	}
+	return 0
+}
+
+// CarsInSegment returns how many cars take part in the segment (starting from 1)
+func (q QualifyingFormat) CarsInSegment(segment int) int {
+	cars := q.GridSize
+	for x := 0; x < segment-1 && x < len(q.Eliminated); x++ {
+		cars -= q.Eliminated[x]
+	}
+	return cars
+}
+
+// KnockedOut returns true if a car in this position was eliminated in an earlier segment
+func (q QualifyingFormat) KnockedOut(segment int, position int) bool {
+	return segment > 0 && position > q.CarsInSegment(segment)
+}
+
+// InDropZone returns true if a car in this position would be eliminated at the end of the segment
+func (q QualifyingFormat) InDropZone(segment int, position int) bool {
+	if segment <= 0 || segment >= q.Segments || q.KnockedOut(segment, position) {
+		return false
+	}
+	return position > q.CarsInSegment(segment+1)
+}
+
+// Cutoff107 returns the slowest time allowed to start the race for the fastest time of the first segment or 0 if the
+// 107% rule doesn't apply
+func (q QualifyingFormat) Cutoff107(fastest time.Duration) time.Duration {
+	if !q.Rule107 || fastest <= 0 {
+		return 0
+	}
+	return fastest * 107 / 100
+}
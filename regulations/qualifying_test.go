@@ -0,0 +1,218 @@
+package regulations
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/f1gopher/f1gopherlib"
+	"github.com/f1gopher/f1gopherlib/Messages"
+)
+
+func TestQualifyingRules(t *testing.T) {
+	tests := []struct {
+		season     int
+		session    QualifyingType
+		gridSize   int
+		expected   []int
+		q2Cars     int
+		q3Cars     int
+		expect107  bool
+		expectGrid int
+	}{
+		{season: 2018, session: Qualifying, gridSize: 20, expected: []int{5, 5, 0}, q2Cars: 15, q3Cars: 10, expect107: true, expectGrid: 20},
+		{season: 2024, session: Qualifying, gridSize: 0, expected: []int{5, 5, 0}, q2Cars: 15, q3Cars: 10, expect107: true, expectGrid: 20},
+		{season: 2021, session: SprintQualifying, gridSize: 20, expected: []int{5, 5, 0}, q2Cars: 15, q3Cars: 10, expect107: true, expectGrid: 20},
+		{season: 2023, session: SprintQualifying, gridSize: 20, expected: []int{5, 5, 0}, q2Cars: 15, q3Cars: 10, expect107: false, expectGrid: 20},
+		{season: 2026, session: Qualifying, gridSize: 22, expected: []int{6, 6, 0}, q2Cars: 16, q3Cars: 10, expect107: true, expectGrid: 22},
+		{season: 2026, session: Qualifying, gridSize: 0, expected: []int{6, 6, 0}, q2Cars: 16, q3Cars: 10, expect107: true, expectGrid: 22},
+		{season: 2026, session: SprintQualifying, gridSize: 22, expected: []int{6, 6, 0}, q2Cars: 16, q3Cars: 10, expect107: false, expectGrid: 22},
+		// Odd number of cars, the extra car is eliminated in Q1
+		{season: 2026, session: Qualifying, gridSize: 21, expected: []int{6, 5, 0}, q2Cars: 15, q3Cars: 10, expect107: true, expectGrid: 21},
+		// Seasons before the table use the earliest rules
+		{season: 2010, session: Qualifying, gridSize: 24, expected: []int{7, 7, 0}, q2Cars: 17, q3Cars: 10, expect107: true, expectGrid: 24},
+	}
+
+	for _, test := range tests {
+		format := QualifyingRules(test.season, test.session, test.gridSize)
+
+		if format.Segments != 3 {
+			t.Errorf("%d %s: expected 3 segments, got %d", test.season, test.session, format.Segments)
+		}
+		if format.GridSize != test.expectGrid {
+			t.Errorf("%d %s: expected grid size %d, got %d", test.season, test.session, test.expectGrid, format.GridSize)
+		}
+		if !slices.Equal(format.Eliminated, test.expected) {
+			t.Errorf("%d %s: expected eliminations %v, got %v", test.season, test.session, test.expected, format.Eliminated)
+		}
+		if format.Rule107 != test.expect107 {
+			t.Errorf("%d %s: expected 107%% rule %t, got %t", test.season, test.session, test.expect107, format.Rule107)
+		}
+		if cars := format.CarsInSegment(1); cars != test.expectGrid {
+			t.Errorf("%d %s: expected %d cars in Q1, got %d", test.season, test.session, test.expectGrid, cars)
+		}
+		if cars := format.CarsInSegment(2); cars != test.q2Cars {
+			t.Errorf("%d %s: expected %d cars in Q2, got %d", test.season, test.session, test.q2Cars, cars)
+		}
+		if cars := format.CarsInSegment(3); cars != test.q3Cars {
+			t.Errorf("%d %s: expected %d cars in Q3, got %d", test.season, test.session, test.q3Cars, cars)
+		}
+	}
+}
+
+func TestQualifyingPositions(t *testing.T) {
+	historical := QualifyingRules(2025, Qualifying, 20)
+	current := QualifyingRules(2026, Qualifying, 22)
+
+	tests := []struct {
+		name       string
+		format     QualifyingFormat
+		eventType  Messages.EventType
+		position   int
+		knockedOut bool
+		dropZone   bool
+	}{
+		{name: "2025 Q1 safe", format: historical, eventType: Messages.Qualifying1, position: 15},
+		{name: "2025 Q1 drop zone", format: historical, eventType: Messages.Qualifying1, position: 16, dropZone: true},
+		{name: "2025 Q2 out", format: historical, eventType: Messages.Qualifying2, position: 16, knockedOut: true},
+		{name: "2025 Q2 drop zone", format: historical, eventType: Messages.Qualifying2, position: 11, dropZone: true},
+		{name: "2025 Q2 safe", format: historical, eventType: Messages.Qualifying2, position: 10},
+		{name: "2025 Q3 out", format: historical, eventType: Messages.Qualifying3, position: 11, knockedOut: true},
+		{name: "2025 Q3 last", format: historical, eventType: Messages.Qualifying3, position: 10},
+		{name: "2026 Q1 safe", format: current, eventType: Messages.Qualifying1, position: 16},
+		{name: "2026 Q1 drop zone", format: current, eventType: Messages.Qualifying1, position: 17, dropZone: true},
+		{name: "2026 Q1 last", format: current, eventType: Messages.Qualifying1, position: 22, dropZone: true},
+		{name: "2026 Q2 out", format: current, eventType: Messages.Qualifying2, position: 17, knockedOut: true},
+		{name: "2026 Q2 drop zone", format: current, eventType: Messages.Qualifying2, position: 16, dropZone: true},
+		{name: "2026 Q2 safe", format: current, eventType: Messages.Qualifying2, position: 10},
+		{name: "2026 Q3 out", format: current, eventType: Messages.Qualifying3, position: 11, knockedOut: true},
+		{name: "Not qualifying", format: current, eventType: Messages.Race, position: 22},
+	}
+
+	for _, test := range tests {
+		segment := test.format.Segment(test.eventType)
+
+		if knockedOut := test.format.KnockedOut(segment, test.position); knockedOut != test.knockedOut {
+			t.Errorf("%s: expected knocked out %t, got %t", test.name, test.knockedOut, knockedOut)
+		}
+		if dropZone := test.format.InDropZone(segment, test.position); dropZone != test.dropZone {
+			t.Errorf("%s: expected drop zone %t, got %t", test.name, test.dropZone, dropZone)
+		}
+	}
+}
+
+func TestCutoff107(t *testing.T) {
+	fastest := 90 * time.Second
+
+	if cutoff := QualifyingRules(2026, Qualifying, 22).Cutoff107(fastest); cutoff != 96300*time.Millisecond {
+		t.Errorf("expected a cutoff of 1:36.300, got %s", cutoff)
+	}
+	if cutoff := QualifyingRules(2026, Qualifying, 22).Cutoff107(0); cutoff != 0 {
+		t.Errorf("expected no cutoff without a fastest time, got %s", cutoff)
+	}
+	if cutoff := QualifyingRules(2026, SprintQualifying, 22).Cutoff107(fastest); cutoff != 0 {
+		t.Errorf("expected no cutoff for sprint qualifying, got %s", cutoff)
+	}
+}
+
+func TestQualifyingSessionType(t *testing.T) {
+	weekend := func(raceTime time.Time, sessions map[time.Time]Messages.SessionType) []f1gopherlib.RaceEvent {
+		events := make([]f1gopherlib.RaceEvent, 0)
+		for start, session := range sessions {
+			events = append(events, f1gopherlib.RaceEvent{RaceTime: raceTime, EventTime: start, Type: session})
+		}
+		return events
+	}
+
+	// 2023 qualifying on Friday sets the race grid and the shootout on Saturday morning the sprint grid
+	austria2023 := time.Date(2023, 7, 2, 13, 0, 0, 0, time.UTC)
+	friday2023 := time.Date(2023, 6, 30, 15, 0, 0, 0, time.UTC)
+	shootout2023 := time.Date(2023, 7, 1, 10, 0, 0, 0, time.UTC)
+	events := weekend(austria2023, map[time.Time]Messages.SessionType{
+		time.Date(2023, 6, 30, 11, 30, 0, 0, time.UTC): Messages.Practice1Session,
+		friday2023:   Messages.QualifyingSession,
+		shootout2023: Messages.QualifyingSession,
+		time.Date(2023, 7, 1, 14, 30, 0, 0, time.UTC): Messages.SprintSession,
+		austria2023: Messages.RaceSession,
+	})
+
+	// From 2024 sprint qualifying is on Friday and qualifying after the sprint on Saturday
+	usa2024 := time.Date(2024, 10, 20, 19, 0, 0, 0, time.UTC)
+	sprintQualifying2024 := time.Date(2024, 10, 18, 21, 30, 0, 0, time.UTC)
+	saturday2024 := time.Date(2024, 10, 19, 22, 0, 0, 0, time.UTC)
+	events = append(events, weekend(usa2024, map[time.Time]Messages.SessionType{
+		time.Date(2024, 10, 18, 17, 30, 0, 0, time.UTC): Messages.Practice1Session,
+		sprintQualifying2024:                            Messages.QualifyingSession,
+		time.Date(2024, 10, 19, 18, 0, 0, 0, time.UTC):  Messages.SprintSession,
+		saturday2024: Messages.QualifyingSession,
+		usa2024:      Messages.RaceSession,
+	})...)
+
+	// No sprint on the weekend
+	singapore2024 := time.Date(2024, 9, 21, 13, 0, 0, 0, time.UTC)
+	events = append(events, weekend(time.Date(2024, 9, 22, 12, 0, 0, 0, time.UTC), map[time.Time]Messages.SessionType{
+		singapore2024: Messages.QualifyingSession,
+	})...)
+
+	// 2022 sprint weekends had no separate sprint qualifying session
+	imola2022 := time.Date(2022, 4, 22, 15, 0, 0, 0, time.UTC)
+	events = append(events, weekend(time.Date(2022, 4, 24, 13, 0, 0, 0, time.UTC), map[time.Time]Messages.SessionType{
+		imola2022: Messages.QualifyingSession,
+		time.Date(2022, 4, 23, 14, 30, 0, 0, time.UTC): Messages.SprintSession,
+	})...)
+
+	tests := []struct {
+		name     string
+		start    time.Time
+		expected QualifyingType
+	}{
+		{name: "2023 Friday qualifying", start: friday2023, expected: Qualifying},
+		{name: "2023 sprint shootout", start: shootout2023, expected: SprintQualifying},
+		{name: "2024 sprint qualifying", start: sprintQualifying2024, expected: SprintQualifying},
+		{name: "2024 Saturday qualifying", start: saturday2024, expected: Qualifying},
+		{name: "no sprint", start: singapore2024, expected: Qualifying},
+		{name: "2022 sprint weekend", start: imola2022, expected: Qualifying},
+		{name: "unknown session", start: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), expected: Qualifying},
+	}
+
+	for _, test := range tests {
+		if session := qualifyingSessionType(events, test.start); session != test.expected {
+			t.Errorf("%s: expected %s, got %s", test.name, test.expected, session)
+		}
+	}
+}
+
+func TestSprintShootoutElimination(t *testing.T) {
+	format := QualifyingRules(2023, SprintQualifying, 20)
+
+	// SQ1 knocks out 16th to 20th and SQ2 11th to 15th
+	tests := []struct {
+		segment    int
+		position   int
+		inDropZone bool
+		knockedOut bool
+	}{
+		{segment: 1, position: 15, inDropZone: false, knockedOut: false},
+		{segment: 1, position: 16, inDropZone: true, knockedOut: false},
+		{segment: 1, position: 20, inDropZone: true, knockedOut: false},
+		{segment: 2, position: 10, inDropZone: false, knockedOut: false},
+		{segment: 2, position: 11, inDropZone: true, knockedOut: false},
+		{segment: 2, position: 16, inDropZone: false, knockedOut: true},
+		{segment: 3, position: 10, inDropZone: false, knockedOut: false},
+		{segment: 3, position: 11, inDropZone: false, knockedOut: true},
+	}
+
+	for _, test := range tests {
+		if inDropZone := format.InDropZone(test.segment, test.position); inDropZone != test.inDropZone {
+			t.Errorf("SQ%d P%d: expected drop zone %t, got %t", test.segment, test.position, test.inDropZone, inDropZone)
+		}
+		if knockedOut := format.KnockedOut(test.segment, test.position); knockedOut != test.knockedOut {
+			t.Errorf("SQ%d P%d: expected knocked out %t, got %t", test.segment, test.position, test.knockedOut, knockedOut)
+		}
+	}
+
+	// The shootout has no 107% rule so nobody in SQ1 is outside the cut-off
+	if cutoff := format.Cutoff107(90 * time.Second); cutoff != 0 {
+		t.Errorf("expected no cutoff for the sprint shootout, got %s", cutoff)
+	}
+}
@@ -0,0 +1,28 @@
+// F1Gopher - Copyright (C) 2025 f1gopher
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package regulations
+
+import "github.com/f1gopher/f1gopherlib/Messages"
+
+// TrackLimitsPenaltyAfter returns how many laps a driver can have deleted for track limits before the next one is a
+// penalty, or zero if the session doesn't give penalties for track limits. In races the black and white flag is shown
+// with the third deleted lap and every one after that is a time penalty. Before 2022 each event had its own rules.
+func TrackLimitsPenaltyAfter(season int, session Messages.SessionType) int {
+	if season < 2022 || (session != Messages.RaceSession && session != Messages.SprintSession) {
+		return 0
+	}
+	return 3
+}
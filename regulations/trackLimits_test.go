@@ -0,0 +1,28 @@
+package regulations
+
+import (
+	"testing"
+
+	"github.com/f1gopher/f1gopherlib/Messages"
+)
+
+func TestTrackLimitsPenaltyAfter(t *testing.T) {
+	tests := []struct {
+		name     string
+		season   int
+		session  Messages.SessionType
+		expected int
+	}{
+		{name: "2024 race", season: 2024, session: Messages.RaceSession, expected: 3},
+		{name: "2024 sprint", season: 2024, session: Messages.SprintSession, expected: 3},
+		{name: "2024 qualifying", season: 2024, session: Messages.QualifyingSession, expected: 0},
+		{name: "2024 practice", season: 2024, session: Messages.Practice2Session, expected: 0},
+		{name: "2021 race", season: 2021, session: Messages.RaceSession, expected: 0},
+	}
+
+	for _, test := range tests {
+		if after := TrackLimitsPenaltyAfter(test.season, test.session); after != test.expected {
+			t.Errorf("%s: expected %d, got %d", test.name, test.expected, after)
+		}
+	}
+}
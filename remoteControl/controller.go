@@ -0,0 +1,95 @@
+// F1Gopher - Copyright (C) 2025 f1gopher
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package remoteControl
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+)
+
+var ErrNoSession = errors.New("no session is being shown")
+var ErrUnknownPanel = errors.New("unknown panel")
+var ErrUnknownReplay = errors.New("unknown replay")
+var ErrReplayUnavailable = errors.New("replay isn't available")
+
+// Controller runs the remote control actions in the app. Actions that need a session return ErrNoSession when there
+// isn't one.
+type Controller interface {
+	State() State
+	SetPaused(paused bool) error
+	TogglePause() error
+	Skip(duration time.Duration) error
+	SkipToStart() error
+	// Back closes the session and returns to the main menu
+	Back() error
+	TogglePanel(name string) error
+	// FocusDriver follows the driver, NoDriver clears the focus
+	FocusDriver(driverNumber int) error
+	ToggleTelemetry() error
+	ToggleCircleMap() error
+	SetRadioMuted(muted bool) error
+	ToggleRadio() error
+	Replays() []Replay
+	OpenReplay(index int) error
+}
+
+// Same as the panels
+const NoDriver = 0
+
+type State struct {
+	View           string  `json:"view"`
+	Session        string  `json:"session"`
+	HasSession     bool    `json:"hasSession"`
+	Paused         bool    `json:"paused"`
+	CanSkipToStart bool    `json:"canSkipToStart"`
+	CircleMap      bool    `json:"circleMap"`
+	RadioMuted     bool    `json:"radioMuted"`
+	Focused        int     `json:"focused"`
+	Panels         []Panel `json:"panels"`
+	// Filled in by the handler from the timing
+	Drivers []Driver `json:"drivers"`
+}
+
+// Panel is one of the panels that can be opened in its own window
+type Panel struct {
+	Name string `json:"name"`
+	Open bool   `json:"open"`
+}
+
+type Driver struct {
+	Number   int    `json:"number"`
+	Name     string `json:"name"`
+	Color    string `json:"color"`
+	Position int    `json:"position"`
+}
+
+// Replay is a session from the history list, opened by its index in the list
+type Replay struct {
+	Index     int    `json:"index"`
+	Name      string `json:"name"`
+	Session   string `json:"session"`
+	Year      int    `json:"year"`
+	Available bool   `json:"available"`
+}
+
+// NewToken returns a random token to authenticate the remote control requests with
+func NewToken() string {
+	token := make([]byte, 16)
+	rand.Read(token)
+	return hex.EncodeToString(token)
+}
@@ -0,0 +1,216 @@
+// F1Gopher - Copyright (C) 2025 f1gopher
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package remoteControl
+
+import (
+	"crypto/subtle"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+//go:embed remote.html
+var remotePage []byte
+
+// Longest skip that can be requested
+const maxSkip = time.Hour
+
+// Handler serves the remote control page at /remote and the API under /api/. Every API request needs the token, either
+// as a bearer token in the Authorization header or the token query parameter. The API is disabled without a token.
+type Handler struct {
+	controller Controller
+	drivers    func() []Driver
+	token      string
+	lock       sync.Mutex
+	mux        *http.ServeMux
+}
+
+func NewHandler(controller Controller, drivers func() []Driver) *Handler {
+	h := &Handler{
+		controller: controller,
+		drivers:    drivers,
+		mux:        http.NewServeMux(),
+	}
+
+	h.mux.HandleFunc("GET /remote", func(writer http.ResponseWriter, r *http.Request) {
+		writer.Header().Set("Content-Type", "text/html; charset=utf-8")
+		writer.Write(remotePage)
+	})
+
+	h.handle("GET /api/state", func(r *http.Request) (any, error) {
+		return h.state(), nil
+	})
+	h.handle("POST /api/pause", func(r *http.Request) (any, error) {
+		return h.stateAfter(h.controller.SetPaused(true))
+	})
+	h.handle("POST /api/resume", func(r *http.Request) (any, error) {
+		return h.stateAfter(h.controller.SetPaused(false))
+	})
+	h.handle("POST /api/pause/toggle", func(r *http.Request) (any, error) {
+		return h.stateAfter(h.controller.TogglePause())
+	})
+	h.handle("POST /api/skip", func(r *http.Request) (any, error) {
+		seconds := 5
+		if value := r.URL.Query().Get("seconds"); value != "" {
+			var err error
+			if seconds, err = strconv.Atoi(value); err != nil {
+				return nil, badRequest("invalid seconds: " + value)
+			}
+		}
+		duration := time.Duration(seconds) * time.Second
+		if duration <= 0 || duration > maxSkip {
+			return nil, badRequest("seconds must be between 1 and 3600")
+		}
+		return h.stateAfter(h.controller.Skip(duration))
+	})
+	h.handle("POST /api/skip/start", func(r *http.Request) (any, error) {
+		return h.stateAfter(h.controller.SkipToStart())
+	})
+	h.handle("POST /api/back", func(r *http.Request) (any, error) {
+		return h.stateAfter(h.controller.Back())
+	})
+	h.handle("POST /api/panels/{name}/toggle", func(r *http.Request) (any, error) {
+		return h.stateAfter(h.controller.TogglePanel(r.PathValue("name")))
+	})
+	h.handle("POST /api/focus", func(r *http.Request) (any, error) {
+		driverNumber, err := strconv.Atoi(r.URL.Query().Get("driver"))
+		if err != nil || driverNumber < 0 {
+			return nil, badRequest("invalid driver: " + r.URL.Query().Get("driver"))
+		}
+		return h.stateAfter(h.controller.FocusDriver(driverNumber))
+	})
+	h.handle("POST /api/telemetry/toggle", func(r *http.Request) (any, error) {
+		return h.stateAfter(h.controller.ToggleTelemetry())
+	})
+	h.handle("POST /api/circlemap/toggle", func(r *http.Request) (any, error) {
+		return h.stateAfter(h.controller.ToggleCircleMap())
+	})
+	h.handle("POST /api/radio/mute", func(r *http.Request) (any, error) {
+		return h.stateAfter(h.controller.SetRadioMuted(true))
+	})
+	h.handle("POST /api/radio/unmute", func(r *http.Request) (any, error) {
+		return h.stateAfter(h.controller.SetRadioMuted(false))
+	})
+	h.handle("POST /api/radio/toggle", func(r *http.Request) (any, error) {
+		return h.stateAfter(h.controller.ToggleRadio())
+	})
+	h.handle("GET /api/replays", func(r *http.Request) (any, error) {
+		return h.controller.Replays(), nil
+	})
+	h.handle("POST /api/replays/{index}/open", func(r *http.Request) (any, error) {
+		index, err := strconv.Atoi(r.PathValue("index"))
+		if err != nil {
+			return nil, badRequest("invalid replay: " + r.PathValue("index"))
+		}
+		return h.stateAfter(h.controller.OpenReplay(index))
+	})
+
+	return h
+}
+
+// SetToken changes the token the requests need, an empty token disables the API
+func (h *Handler) SetToken(token string) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.token = token
+}
+
+func (h *Handler) ServeHTTP(writer http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(writer, r)
+}
+
+type badRequest string
+
+func (b badRequest) Error() string { return string(b) }
+
+func (h *Handler) handle(pattern string, action func(r *http.Request) (any, error)) {
+	h.mux.HandleFunc(pattern, func(writer http.ResponseWriter, r *http.Request) {
+		if status, err := h.authenticate(r); err != nil {
+			writeJSON(writer, status, map[string]string{"error": err.Error()})
+			return
+		}
+
+		result, err := action(r)
+		if err != nil {
+			writeJSON(writer, errorStatus(err), map[string]string{"error": err.Error()})
+			return
+		}
+		writeJSON(writer, http.StatusOK, result)
+	})
+}
+
+func (h *Handler) authenticate(r *http.Request) (int, error) {
+	h.lock.Lock()
+	token := h.token
+	h.lock.Unlock()
+
+	if token == "" {
+		return http.StatusForbidden, errors.New("remote control is disabled, set a token in the options")
+	}
+
+	provided := r.URL.Query().Get("token")
+	if bearer, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); found {
+		provided = bearer
+	}
+	if subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+		return http.StatusUnauthorized, errors.New("invalid token")
+	}
+	return http.StatusOK, nil
+}
+
+func (h *Handler) state() State {
+	state := h.controller.State()
+	state.Drivers = []Driver{}
+	if state.HasSession && h.drivers != nil {
+		state.Drivers = h.drivers()
+	}
+	return state
+}
+
+// stateAfter returns the state once an action has been run so the caller can update without another request
+func (h *Handler) stateAfter(err error) (any, error) {
+	if err != nil {
+		return nil, err
+	}
+	return h.state(), nil
+}
+
+func errorStatus(err error) int {
+	var invalid badRequest
+	switch {
+	case errors.As(err, &invalid):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrUnknownPanel), errors.Is(err, ErrUnknownReplay):
+		return http.StatusNotFound
+	case errors.Is(err, ErrNoSession), errors.Is(err, ErrReplayUnavailable):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func writeJSON(writer http.ResponseWriter, status int, value any) {
+	writer.Header().Set("Content-Type", "application/json")
+	writer.Header().Set("Cache-Control", "no-store")
+	writer.WriteHeader(status)
+	json.NewEncoder(writer).Encode(value)
+}
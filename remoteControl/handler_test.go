@@ -0,0 +1,222 @@
+package remoteControl
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type fakeController struct {
+	state   State
+	skipped time.Duration
+	opened  int
+}
+
+func (f *fakeController) State() State { return f.state }
+
+func (f *fakeController) SetPaused(paused bool) error {
+	if !f.state.HasSession {
+		return ErrNoSession
+	}
+	f.state.Paused = paused
+	return nil
+}
+
+func (f *fakeController) TogglePause() error { return f.SetPaused(!f.state.Paused) }
+
+func (f *fakeController) Skip(duration time.Duration) error {
+	f.skipped += duration
+	return nil
+}
+
+func (f *fakeController) SkipToStart() error { return nil }
+func (f *fakeController) Back() error        { return nil }
+
+func (f *fakeController) TogglePanel(name string) error {
+	for x := range f.state.Panels {
+		if f.state.Panels[x].Name == name {
+			f.state.Panels[x].Open = !f.state.Panels[x].Open
+			return nil
+		}
+	}
+	return ErrUnknownPanel
+}
+
+func (f *fakeController) FocusDriver(driverNumber int) error {
+	f.state.Focused = driverNumber
+	return nil
+}
+
+func (f *fakeController) ToggleTelemetry() error { return f.TogglePanel("Telemetry") }
+
+func (f *fakeController) ToggleCircleMap() error {
+	f.state.CircleMap = !f.state.CircleMap
+	return nil
+}
+
+func (f *fakeController) SetRadioMuted(muted bool) error {
+	f.state.RadioMuted = muted
+	return nil
+}
+
+func (f *fakeController) ToggleRadio() error { return f.SetRadioMuted(!f.state.RadioMuted) }
+
+func (f *fakeController) Replays() []Replay {
+	return []Replay{
+		{Index: 0, Name: "Abu Dhabi Grand Prix", Session: "Race", Year: 2024, Available: true},
+		{Index: 1, Name: "Pre-Season Test", Session: "Pre-Season", Year: 2024, Available: false},
+	}
+}
+
+func (f *fakeController) OpenReplay(index int) error {
+	replays := f.Replays()
+	if index < 0 || index >= len(replays) {
+		return ErrUnknownReplay
+	}
+	if !replays[index].Available {
+		return ErrReplayUnavailable
+	}
+	f.opened = index
+	f.state.HasSession = true
+	return nil
+}
+
+func newTestHandler() (*Handler, *fakeController) {
+	controller := &fakeController{
+		opened: -1,
+		state: State{
+			View:       "Replay",
+			HasSession: true,
+			Panels:     []Panel{{Name: "Telemetry"}, {Name: "Battles"}},
+		},
+	}
+	handler := NewHandler(controller, func() []Driver {
+		return []Driver{{Number: 44, Name: "HAM", Position: 1}}
+	})
+	handler.SetToken("secret")
+	return handler, controller
+}
+
+func request(t *testing.T, handler http.Handler, method string, path string, token string, result any) int {
+	r := httptest.NewRequest(method, path, nil)
+	if token != "" {
+		r.Header.Set("Authorization", "Bearer "+token)
+	}
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, r)
+
+	if result != nil && recorder.Code == http.StatusOK {
+		if err := json.Unmarshal(recorder.Body.Bytes(), result); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return recorder.Code
+}
+
+func TestAuthentication(t *testing.T) {
+	handler, _ := newTestHandler()
+
+	if code := request(t, handler, "GET", "/api/state", "", nil); code != http.StatusUnauthorized {
+		t.Errorf("expected unauthorized without a token, got %d", code)
+	}
+	if code := request(t, handler, "GET", "/api/state", "wrong", nil); code != http.StatusUnauthorized {
+		t.Errorf("expected unauthorized with the wrong token, got %d", code)
+	}
+	if code := request(t, handler, "GET", "/api/state", "secret", nil); code != http.StatusOK {
+		t.Errorf("expected the bearer token to be accepted, got %d", code)
+	}
+	if code := request(t, handler, "GET", "/api/state?token=secret", "", nil); code != http.StatusOK {
+		t.Errorf("expected the query token to be accepted, got %d", code)
+	}
+
+	// The page doesn't need the token, it asks for it
+	if code := request(t, handler, "GET", "/remote", "", nil); code != http.StatusOK {
+		t.Errorf("expected the remote page to be served, got %d", code)
+	}
+
+	handler.SetToken("")
+	if code := request(t, handler, "GET", "/api/state", "", nil); code != http.StatusForbidden {
+		t.Errorf("expected forbidden when disabled, got %d", code)
+	}
+}
+
+func TestActions(t *testing.T) {
+	handler, controller := newTestHandler()
+
+	var state State
+	if code := request(t, handler, "POST", "/api/pause/toggle", "secret", &state); code != http.StatusOK || !state.Paused {
+		t.Errorf("expected to be paused, got %d %v", code, state)
+	}
+	if code := request(t, handler, "POST", "/api/resume", "secret", &state); code != http.StatusOK || state.Paused {
+		t.Errorf("expected to be resumed, got %d %v", code, state)
+	}
+	if len(state.Drivers) != 1 || state.Drivers[0].Name != "HAM" {
+		t.Errorf("expected the drivers in the state, got %v", state.Drivers)
+	}
+
+	request(t, handler, "POST", "/api/skip", "secret", nil)
+	request(t, handler, "POST", "/api/skip?seconds=60", "secret", nil)
+	if controller.skipped != 65*time.Second {
+		t.Errorf("expected to skip 65 seconds, got %s", controller.skipped)
+	}
+	if code := request(t, handler, "POST", "/api/skip?seconds=0", "secret", nil); code != http.StatusBadRequest {
+		t.Errorf("expected a bad request for 0 seconds, got %d", code)
+	}
+
+	if code := request(t, handler, "POST", "/api/panels/Battles/toggle", "secret", &state); code != http.StatusOK || !state.Panels[1].Open {
+		t.Errorf("expected the battles panel to be open, got %d %v", code, state.Panels)
+	}
+	if code := request(t, handler, "POST", "/api/panels/Unknown/toggle", "secret", nil); code != http.StatusNotFound {
+		t.Errorf("expected not found for an unknown panel, got %d", code)
+	}
+
+	if code := request(t, handler, "POST", "/api/focus?driver=44", "secret", &state); code != http.StatusOK || state.Focused != 44 {
+		t.Errorf("expected driver 44 to be focused, got %d %d", code, state.Focused)
+	}
+	if code := request(t, handler, "POST", "/api/focus?driver=abc", "secret", nil); code != http.StatusBadRequest {
+		t.Errorf("expected a bad request for an invalid driver, got %d", code)
+	}
+
+	if code := request(t, handler, "POST", "/api/radio/toggle", "secret", &state); code != http.StatusOK || !state.RadioMuted {
+		t.Errorf("expected the radio to be muted, got %d %v", code, state)
+	}
+
+	// Actions can't be triggered with a GET
+	if code := request(t, handler, "GET", "/api/pause", "secret", nil); code != http.StatusMethodNotAllowed {
+		t.Errorf("expected method not allowed, got %d", code)
+	}
+}
+
+func TestNoSession(t *testing.T) {
+	handler, controller := newTestHandler()
+	controller.state.HasSession = false
+
+	var state State
+	if code := request(t, handler, "GET", "/api/state", "secret", &state); code != http.StatusOK || len(state.Drivers) != 0 {
+		t.Errorf("expected no drivers without a session, got %d %v", code, state.Drivers)
+	}
+	if code := request(t, handler, "POST", "/api/pause", "secret", nil); code != http.StatusConflict {
+		t.Errorf("expected a conflict without a session, got %d", code)
+	}
+}
+
+func TestReplays(t *testing.T) {
+	handler, controller := newTestHandler()
+
+	var replays []Replay
+	if code := request(t, handler, "GET", "/api/replays", "secret", &replays); code != http.StatusOK || len(replays) != 2 {
+		t.Fatalf("expected two replays, got %d %v", code, replays)
+	}
+
+	if code := request(t, handler, "POST", "/api/replays/1/open", "secret", nil); code != http.StatusConflict {
+		t.Errorf("expected a conflict for an unavailable replay, got %d", code)
+	}
+	if code := request(t, handler, "POST", "/api/replays/5/open", "secret", nil); code != http.StatusNotFound {
+		t.Errorf("expected not found for an unknown replay, got %d", code)
+	}
+	if code := request(t, handler, "POST", "/api/replays/0/open", "secret", nil); code != http.StatusOK || controller.opened != 0 {
+		t.Errorf("expected the first replay to be opened, got %d %d", code, controller.opened)
+	}
+}
@@ -16,11 +16,26 @@
 package ui
 
 import (
+	"encoding/json"
+	"errors"
+	"f1gopher/alerts"
+	"f1gopher/mqttPublisher"
+	"f1gopher/oscOutput"
+	"f1gopher/webhooks"
 	"fmt"
 	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"time"
+
+	"github.com/f1gopher/f1gopherlib"
 )
 
+// The options and alert rules are saved here so they are kept between runs
+const configFile = "./config.json"
+
 type config struct {
 	autoplayLive          bool
 	liveDelay             int32
@@ -31,6 +46,62 @@ type config struct {
 	webTimingPort         int32
 	showDebugReplay       bool
 	predictionPitstopTime time.Duration
+	standingsFile         string
+	mqttEnabled           bool
+	mqttBroker            string
+	mqttTopicPrefix       string
+	mqttQoS               int32
+	mqttUsername          string
+	mqttPassword          string
+	oscEnabled            bool
+	oscTargets            string
+	oscRate               int32
+	oscSnapshot           bool
+	oscListenAddress      string
+	remoteControlToken    string
+
+	// Shared by every copy of the config so rules edited during a session are used by the next one
+	alertRules *alertRules
+	webhooks   *webhookList
+
+	// Folder the current session's data is cached in, empty if it isn't being cached. Not saved.
+	sessionFolder string
+}
+
+type alertRules struct {
+	rules []alerts.Rule
+	lock  sync.Mutex
+}
+
+type webhookList struct {
+	hooks []webhooks.Hook
+	lock  sync.Mutex
+}
+
+// savedConfig is the part of the config that is written to the config file
+type savedConfig struct {
+	AutoplayLive         bool            `json:"autoplayLive"`
+	LiveDelay            int32           `json:"liveDelay"`
+	UseCache             bool            `json:"useCache"`
+	CacheFolder          string          `json:"cacheFolder"`
+	WebTimingViewEnabled bool            `json:"webTimingViewEnabled"`
+	WebTimingPort        int32           `json:"webTimingPort"`
+	ShowDebugReplay      bool            `json:"showDebugReplay"`
+	StandingsFile        string          `json:"standingsFile"`
+	MQTTEnabled          bool            `json:"mqttEnabled"`
+	MQTTBroker           string          `json:"mqttBroker"`
+	MQTTTopicPrefix      string          `json:"mqttTopicPrefix"`
+	MQTTQoS              int32           `json:"mqttQoS"`
+	MQTTUsername         string          `json:"mqttUsername"`
+	MQTTPassword         string          `json:"mqttPassword"`
+	OSCEnabled           bool            `json:"oscEnabled"`
+	OSCTargets           string          `json:"oscTargets"`
+	OSCRate              int32           `json:"oscRate"`
+	OSCSnapshot          bool            `json:"oscSnapshot"`
+	OSCListenAddress     string          `json:"oscListenAddress"`
+	RemoteControlToken   string          `json:"remoteControlToken"`
+	AlertRules           []alerts.Rule   `json:"alertRules"`
+	Webhooks             []webhooks.Hook `json:"webhooks"`
 }
 
 func NewConfig() config {
@@ -44,6 +115,18 @@ func NewConfig() config {
 		webTimingPort:         8000,
 		showDebugReplay:       false,
 		predictionPitstopTime: time.Second * 10,
+		standingsFile:         "./standings.json",
+		mqttEnabled:           false,
+		mqttBroker:            "tcp://localhost:1883",
+		mqttTopicPrefix:       "f1gopher",
+		mqttQoS:               0,
+		oscEnabled:            false,
+		oscTargets:            "127.0.0.1:9000",
+		oscRate:               10,
+		oscSnapshot:           true,
+		oscListenAddress:      "127.0.0.1:9001",
+		alertRules:            &alertRules{},
+		webhooks:              &webhookList{},
 	}
 
 	for _, address := range c.getLocalIP() {
@@ -53,6 +136,96 @@ func NewConfig() config {
 	return c
 }
 
+// LoadConfig returns the default config with any settings saved by a previous run. If the config file can't be read
+// the defaults are returned with the error.
+func LoadConfig() (config, error) {
+	c := NewConfig()
+
+	data, err := os.ReadFile(configFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	} else if err != nil {
+		return c, err
+	}
+
+	// Anything missing from the file keeps the default value
+	saved := c.saved()
+	if err = json.Unmarshal(data, &saved); err != nil {
+		return c, err
+	}
+
+	c.autoplayLive = saved.AutoplayLive
+	c.liveDelay = saved.LiveDelay
+	c.useCache = saved.UseCache
+	c.cacheFolder = saved.CacheFolder
+	c.webTimingViewEnabled = saved.WebTimingViewEnabled
+	c.webTimingPort = saved.WebTimingPort
+	c.showDebugReplay = saved.ShowDebugReplay
+	c.standingsFile = saved.StandingsFile
+	c.mqttEnabled = saved.MQTTEnabled
+	c.mqttBroker = saved.MQTTBroker
+	c.mqttTopicPrefix = saved.MQTTTopicPrefix
+	c.mqttQoS = saved.MQTTQoS
+	c.mqttUsername = saved.MQTTUsername
+	c.mqttPassword = saved.MQTTPassword
+	c.oscEnabled = saved.OSCEnabled
+	c.oscTargets = saved.OSCTargets
+	c.oscRate = saved.OSCRate
+	c.oscSnapshot = saved.OSCSnapshot
+	c.oscListenAddress = saved.OSCListenAddress
+	c.remoteControlToken = saved.RemoteControlToken
+	c.alertRules.rules = saved.AlertRules
+	c.webhooks.hooks = saved.Webhooks
+
+	c.webTimingAddresses = nil
+	for _, address := range c.getLocalIP() {
+		c.webTimingAddresses = append(c.webTimingAddresses, fmt.Sprintf("%s:%d", address, c.webTimingPort))
+	}
+
+	return c, nil
+}
+
+func (c *config) saved() savedConfig {
+	return savedConfig{
+		AutoplayLive:         c.autoplayLive,
+		LiveDelay:            c.liveDelay,
+		UseCache:             c.useCache,
+		CacheFolder:          c.cacheFolder,
+		WebTimingViewEnabled: c.webTimingViewEnabled,
+		WebTimingPort:        c.webTimingPort,
+		ShowDebugReplay:      c.showDebugReplay,
+		StandingsFile:        c.standingsFile,
+		MQTTEnabled:          c.mqttEnabled,
+		MQTTBroker:           c.mqttBroker,
+		MQTTTopicPrefix:      c.mqttTopicPrefix,
+		MQTTQoS:              c.mqttQoS,
+		MQTTUsername:         c.mqttUsername,
+		MQTTPassword:         c.mqttPassword,
+		OSCEnabled:           c.oscEnabled,
+		OSCTargets:           c.oscTargets,
+		OSCRate:              c.oscRate,
+		OSCSnapshot:          c.oscSnapshot,
+		OSCListenAddress:     c.oscListenAddress,
+		RemoteControlToken:   c.remoteControlToken,
+		AlertRules:           c.AlertRules(),
+		Webhooks:             c.Webhooks(),
+	}
+}
+
+func (c *config) save() error {
+	data, err := json.MarshalIndent(c.saved(), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// The file has the MQTT password, remote control token and webhook secrets in it. WriteFile only sets the mode
+	// when it creates the file so a file saved by an older version is fixed before it is written.
+	if err = os.Chmod(configFile, 0600); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return os.WriteFile(configFile, data, 0600)
+}
+
 func (c *config) sessionCache() string {
 	if !c.useCache {
 		return ""
@@ -61,6 +234,22 @@ func (c *config) sessionCache() string {
 	return c.cacheFolder
 }
 
+// setSession sets the folder the session's data is cached in. This matches the folder f1gopherlib uses so anything
+// exported for the session is kept with the data.
+func (c *config) setSession(event *f1gopherlib.RaceEvent) {
+	cache := c.sessionCache()
+	if event == nil || cache == "" {
+		c.sessionFolder = ""
+		return
+	}
+
+	c.sessionFolder = filepath.Join(
+		cache,
+		fmt.Sprintf("%d", event.RaceTime.Year()),
+		fmt.Sprintf("%s_%s", event.RaceTime.Format("2006-01-02"), event.Name),
+		event.Type.String())
+}
+
 func (c *config) getLocalIP() []string {
 	ips := []string{"localhost"}
 
@@ -87,3 +276,72 @@ func (c *config) PredictedPitstopTime() time.Duration {
 func (c *config) SetPredictedPitstopTime(value time.Duration) {
 	c.predictionPitstopTime = value
 }
+
+func (c *config) StandingsFile() string {
+	return c.standingsFile
+}
+
+func (c *config) SessionDataFolder() string {
+	return c.sessionFolder
+}
+
+// MQTTSettings returns the broker settings and if publishing is enabled
+func (c *config) MQTTSettings() (mqttPublisher.Settings, bool) {
+	settings := mqttPublisher.Settings{
+		Broker:      c.mqttBroker,
+		TopicPrefix: c.mqttTopicPrefix,
+		QoS:         byte(c.mqttQoS),
+		Username:    c.mqttUsername,
+		Password:    c.mqttPassword,
+	}
+	return settings, c.mqttEnabled
+}
+
+// OSCSettings returns the OSC output settings and if it is enabled
+func (c *config) OSCSettings() (oscOutput.Settings, bool) {
+	settings := oscOutput.Settings{
+		Targets:           strings.Split(c.oscTargets, ","),
+		Rate:              int(c.oscRate),
+		SnapshotOnConnect: c.oscSnapshot,
+		ListenAddress:     c.oscListenAddress,
+	}
+	return settings, c.oscEnabled
+}
+
+func (c *config) AlertRules() []alerts.Rule {
+	c.alertRules.lock.Lock()
+	defer c.alertRules.lock.Unlock()
+
+	rules := make([]alerts.Rule, len(c.alertRules.rules))
+	copy(rules, c.alertRules.rules)
+	return rules
+}
+
+// SetAlertRules replaces the rules and saves the config
+func (c *config) SetAlertRules(rules []alerts.Rule) error {
+	c.alertRules.lock.Lock()
+	c.alertRules.rules = make([]alerts.Rule, len(rules))
+	copy(c.alertRules.rules, rules)
+	c.alertRules.lock.Unlock()
+
+	return c.save()
+}
+
+func (c *config) Webhooks() []webhooks.Hook {
+	c.webhooks.lock.Lock()
+	defer c.webhooks.lock.Unlock()
+
+	hooks := make([]webhooks.Hook, len(c.webhooks.hooks))
+	copy(hooks, c.webhooks.hooks)
+	return hooks
+}
+
+// SetWebhooks replaces the hooks and saves the config
+func (c *config) SetWebhooks(hooks []webhooks.Hook) error {
+	c.webhooks.lock.Lock()
+	c.webhooks.hooks = make([]webhooks.Hook, len(hooks))
+	copy(c.webhooks.hooks, hooks)
+	c.webhooks.lock.Unlock()
+
+	return c.save()
+}
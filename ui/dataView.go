@@ -17,12 +17,16 @@ package ui
 
 import (
 	"context"
+	"f1gopher/remoteControl"
 	"f1gopher/ui/panel"
+	"f1gopher/ui/webTimingView"
+	"fmt"
 	"sync"
 
 	"github.com/AllenDang/giu"
 	"github.com/f1gopher/f1gopherlib"
 	"github.com/f1gopher/f1gopherlib/Messages"
+	"go.uber.org/zap"
 )
 
 type dataView struct {
@@ -36,18 +40,38 @@ type dataView struct {
 
 	panels map[panel.Type]panel.Panel
 
+	// Driver followed by all of the panels
+	focus *panel.DriverFocus
+
+	// Drivers the panels want telemetry for
+	telemetrySources *panel.TelemetrySources
+
+	// Shares the battles with the web timing overlays
+	webView *webTimingView.WebTiming
+	battles webTimingView.BattleSource
+
+	// Messages shown on top of the panels
+	notifications *panel.Notifications
+
 	event     Messages.Event
 	eventLock sync.Mutex
 	closeWg   sync.WaitGroup
 
-	showTelemetry bool
-
 	layoutFunc func(width int, height int)
 
 	showCircleMap bool
+
+	// Panels that aren't part of the fixed layout and can be opened in their own window
+	optionalPanels []*optionalPanel
+	panelSelect    *panelDisplaySelectWidget
+}
+
+type optionalPanel struct {
+	panelType panel.Type
+	open      bool
 }
 
-func createDataView(webView panel.Panel, changeView func(newView screen, info any), isLiveSession bool) dataScreen {
+func createDataView(webView *webTimingView.WebTiming, changeView func(newView screen, info any), isLiveSession bool, logger *zap.SugaredLogger) dataScreen {
 	view := dataView{
 		changeView:    changeView,
 		panels:        map[panel.Type]panel.Panel{},
@@ -56,40 +80,160 @@ func createDataView(webView panel.Panel, changeView func(newView screen, info an
 
 	view.layoutFunc = view.newLayout
 
+	focus := panel.CreateDriverFocus()
+	view.focus = focus
+	telemetrySources := panel.CreateTelemetrySources()
+	view.telemetrySources = telemetrySources
+
+	notifications := panel.CreateNotifications()
+	view.notifications = notifications
+
 	view.addPanel(panel.CreateInformation(func() { changeView(MainMenu, nil) }, isLiveSession))
-	view.addPanel(panel.CreateTiming())
+	battles := panel.CreateBattleDetector()
+	view.webView = webView
+	view.battles = battles
+	lapping := panel.CreateLappingPredictor()
+	trackLimits := panel.CreateTrackLimitsTracker()
+
+	view.addPanel(panel.CreateTiming(focus, battles, lapping, trackLimits))
 	view.addPanel(panel.CreateRaceControlMessages())
 	view.addPanel(panel.CreateWeather())
-	view.addPanel(panel.CreateTeamRadio())
+	audio := panel.CreateAudioOutput()
+	teamRadio := panel.CreateTeamRadio(audio)
+	view.addPanel(teamRadio)
 
 	trackMaps := panel.CreateTrackMapStore()
 
-	view.addPanel(panel.CreateTrackMap(trackMaps))
-	view.addPanel(panel.CreateTelemetry())
+	overtakes := panel.CreateOvertakeDetector()
+
+	view.addPanel(panel.CreateTrackMap(trackMaps, focus, overtakes, lapping))
+	view.addPanel(panel.CreateTelemetry(focus, telemetrySources))
 
 	// TODO - only create these for race session so that we don't have them processing data even when not displayed
-	view.addPanel(panel.CreateRacePosition())
-	view.addPanel(panel.CreateGapperPlot())
-	view.addPanel(panel.CreateCatching())
+	view.addPanel(panel.CreateRacePosition(focus))
+	view.addPanel(panel.CreateGapperPlot(focus))
+	view.addPanel(panel.CreateCatching(focus))
 
 	// Quali only
-	view.addPanel(panel.CreateImproving(trackMaps))
+	view.addPanel(panel.CreateImproving(trackMaps, trackLimits))
 
 	view.addPanel(panel.CreateCircleMap())
+	view.addPanel(panel.CreateLapTimeScatter(focus))
+	view.addPanel(panel.CreateDriverDashboard(trackMaps, focus, telemetrySources, teamRadio.(panel.RadioControls)))
+	view.addPanel(panel.CreateChampionship())
+	view.addPanel(panel.CreatePitStopLog())
+	view.addPanel(panel.CreateOvertakes(overtakes, focus))
+	view.addPanel(panel.CreateBattles(battles, focus))
+	view.addPanel(panel.CreateLongRuns(focus))
+	view.addPanel(panel.CreateLapAnalysis(trackLimits))
+	view.addPanel(panel.CreateTrackLimits(trackLimits, focus))
+	view.addPanel(panel.CreateStewards(focus, notifications))
+	view.addPanel(panel.CreateAlertRules(notifications, audio, logger))
+	view.addPanel(panel.CreateWebhooks())
+	view.addPanel(panel.CreateMQTT())
+	view.addPanel(panel.CreateOSC(battles))
 
 	view.addPanel(webView)
 
+	view.optionalPanels = []*optionalPanel{
+		{panelType: panel.Telemetry},
+		{panelType: panel.RacePosition},
+		{panelType: panel.GapperPlot},
+		{panelType: panel.LapTimeScatter},
+		{panelType: panel.DriverDashboard},
+		{panelType: panel.Championship},
+		{panelType: panel.PitStopLog},
+		{panelType: panel.Overtakes},
+		{panelType: panel.Battles},
+		{panelType: panel.LongRuns},
+		{panelType: panel.LapAnalysis},
+		{panelType: panel.TrackLimits},
+		{panelType: panel.Stewards},
+		{panelType: panel.AlertRules},
+		{panelType: panel.Webhooks},
+		{panelType: panel.MQTT},
+		{panelType: panel.OSC},
+	}
+	view.panelSelect = &panelDisplaySelectWidget{panels: view.optionalPanels}
+
 	return &view
 }
 
 func (d *dataView) toggleTelemetryView() {
-	d.showTelemetry = !d.showTelemetry
+	for _, optional := range d.optionalPanels {
+		if optional.panelType == panel.Telemetry {
+			optional.open = !optional.open
+		}
+	}
 }
 
 func (d *dataView) toggleCircleMap() {
 	d.showCircleMap = !d.showCircleMap
 }
 
+func (d *dataView) circleMapShown() bool {
+	return d.showCircleMap
+}
+
+func (d *dataView) sessionName() string {
+	// Cleared while the session is closing
+	if d.dataSrc == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s - %s", d.dataSrc.Name(), d.dataSrc.Session().String())
+}
+
+func (d *dataView) sessionControls() panel.SessionControls {
+	return d.panels[panel.Info].(panel.SessionControls)
+}
+
+func (d *dataView) radioControls() panel.RadioControls {
+	return d.panels[panel.TeamRadio].(panel.RadioControls)
+}
+
+func (d *dataView) focusedDriver() int {
+	return d.focus.Focused()
+}
+
+// focusDriver follows the driver, unlike clicking a driver it doesn't clear the focus if they are already followed
+func (d *dataView) focusDriver(driverNumber int) {
+	if driverNumber == panel.NoDriver {
+		d.focus.Clear()
+		return
+	}
+	d.focus.Follow(driverNumber)
+}
+
+// togglePanel opens or closes the optional panel, returns false if there isn't a panel with the name
+func (d *dataView) togglePanel(name string) bool {
+	for _, optional := range d.optionalPanels {
+		if optional.panelType.String() == name {
+			optional.open = !optional.open
+			return true
+		}
+	}
+	return false
+}
+
+func (d *dataView) panelStates() []remoteControl.Panel {
+	panels := make([]remoteControl.Panel, len(d.optionalPanels))
+	for x, optional := range d.optionalPanels {
+		panels[x] = remoteControl.Panel{Name: optional.panelType.String(), Open: optional.open}
+	}
+	return panels
+}
+
+// chart returns the panel with the name if it draws a chart
+func (d *dataView) chart(name string) (panel.ChartRenderer, bool) {
+	for panelType, current := range d.panels {
+		if panelType.String() == name {
+			renderer, isChart := current.(panel.ChartRenderer)
+			return renderer, isChart
+		}
+	}
+	return nil, false
+}
+
 func (d *dataView) addPanel(panel panel.Panel) {
 	d.panels[panel.Type()] = panel
 }
@@ -101,6 +245,12 @@ func (d *dataView) init(dataSrc f1gopherlib.F1GopherLib, config config) {
 
 	// Reset the global pitstop loss time to the currently selected track default
 	config.SetPredictedPitstopTime(dataSrc.TimeLostInPitlane())
+	d.focus.Reset()
+	d.telemetrySources.Reset(dataSrc)
+	d.notifications.Reset()
+	if d.webView != nil {
+		d.webView.SetBattles(d.battles)
+	}
 
 	for x := range d.panels {
 		d.panels[x].Init(dataSrc, &config)
@@ -167,7 +317,7 @@ func (d *dataView) newLayout(width int, height int) {
 	w := giu.Window(panel.Info.String()).
 		Flags(giu.WindowFlagsNoDecoration|giu.WindowFlagsNoMove).
 		Pos(0, 0)
-	w.Layout(d.panels[panel.Info].Draw(0, 0)...)
+	w.Layout(giu.Row(append(d.panels[panel.Info].Draw(0, 0), d.panelSelect)...))
 
 	infoWidth, panelHeight := w.CurrentSize()
 
@@ -235,6 +385,42 @@ func (d *dataView) newLayout(width int, height int) {
 			Size(rcmWidth, row2Height)
 		w.Layout(d.panels[panel.RaceControlMessages].Draw(int(telemetryWidth), int(row2Height))...)
 	}
+
+	d.drawOptionalPanels(width, height)
+	d.drawNotifications(width, panelHeight+gap)
+}
+
+func (d *dataView) drawNotifications(width int, top float32) {
+	widgets := d.notifications.Widgets()
+	if len(widgets) == 0 {
+		return
+	}
+
+	const notificationWidth float32 = 450
+	giu.Window("Notifications").
+		Flags(giu.WindowFlagsNoDecoration|giu.WindowFlagsNoMove|giu.WindowFlagsNoFocusOnAppearing|giu.WindowFlagsNoInputs).
+		Pos(float32(width)-notificationWidth, top).
+		Size(notificationWidth, float32(len(widgets)*22+16)).
+		Layout(widgets...)
+}
+
+func (d *dataView) drawOptionalPanels(width int, height int) {
+	for x, optional := range d.optionalPanels {
+		if !optional.open {
+			continue
+		}
+
+		// Cascade the windows so they don't all open on top of each other
+		offset := float32(x * 30)
+		w := giu.Window(optional.panelType.String()).
+			IsOpen(&optional.open).
+			Pos(float32(width)/4+offset, float32(height)/4+offset).
+			Size(float32(width)/2, float32(height)/2)
+
+		// Use the size from the previous frame so the panel fills the window
+		panelWidth, panelHeight := w.CurrentSize()
+		w.Layout(d.panels[optional.panelType].Draw(int(panelWidth), int(panelHeight)-20)...)
+	}
 }
 
 func (d *dataView) processData() {
@@ -16,8 +16,10 @@
 package ui
 
 import (
-	"github.com/AllenDang/giu"
+	"f1gopher/remoteControl"
 	"strings"
+
+	"github.com/AllenDang/giu"
 )
 
 type optionsMenu struct {
@@ -27,7 +29,7 @@ type optionsMenu struct {
 
 func (o *optionsMenu) draw(width int, height int) {
 	menuWidth := float32(600.0)
-	menuHeight := float32(380.0)
+	menuHeight := float32(690.0)
 	posX := (float32(width) - menuWidth) / 2
 	posY := (float32(height) - menuHeight) / 2
 
@@ -49,9 +51,31 @@ func (o *optionsMenu) draw(width int, height int) {
 			// Indent the addresses
 			giu.Label("      "+strings.Join(o.config.webTimingAddresses, ", ")),
 			giu.InputInt(&o.config.webTimingPort).Size(40).Label("Web Timing View Port"),
+			giu.Row(
+				giu.InputText(&o.config.remoteControlToken).Label("Remote Control Token (empty disables)").
+					Flags(giu.InputTextFlagsPassword),
+				giu.Button("Generate").OnClick(func() {
+					o.config.remoteControlToken = remoteControl.NewToken()
+				}),
+			),
+			giu.Label("      Remote control page: http://<web timing address>/remote"),
 			giu.Dummy(1, 20),
 			giu.Checkbox("Show Debug Replay", &o.config.showDebugReplay),
 			giu.Dummy(1, 20),
+			giu.InputText(&o.config.standingsFile).Label("Championship Standings File"),
+			giu.Dummy(1, 20),
+			giu.Checkbox("MQTT Publisher Enabled", &o.config.mqttEnabled),
+			giu.InputText(&o.config.mqttBroker).Label("MQTT Broker (tcp://host:port)"),
+			giu.InputText(&o.config.mqttTopicPrefix).Label("MQTT Topic Prefix"),
+			giu.SliderInt(&o.config.mqttQoS, 0, 2).Label("MQTT QoS"),
+			giu.InputText(&o.config.mqttUsername).Label("MQTT Username"),
+			giu.InputText(&o.config.mqttPassword).Label("MQTT Password").Flags(giu.InputTextFlagsPassword),
+			giu.Dummy(1, 20),
+			giu.Checkbox("OSC Output Enabled", &o.config.oscEnabled),
+			giu.InputText(&o.config.oscTargets).Label("OSC Targets (host:port, comma separated)"),
+			giu.SliderInt(&o.config.oscRate, 1, 60).Label("OSC Updates per Second"),
+			giu.Checkbox("OSC Snapshot on Connect", &o.config.oscSnapshot),
+			giu.InputText(&o.config.oscListenAddress).Label("OSC Listen Address"),
 			giu.Dummy(1, 20),
 			giu.Button("Back").OnClick(func() {
 				o.changeView(MainMenu, nil)
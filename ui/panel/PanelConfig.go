@@ -1,10 +1,23 @@
 package panel
 
 import (
+	"f1gopher/alerts"
+	"f1gopher/mqttPublisher"
+	"f1gopher/oscOutput"
+	"f1gopher/webhooks"
 	"time"
 )
 
 type PanelConfig interface {
 	PredictedPitstopTime() time.Duration
 	SetPredictedPitstopTime(value time.Duration)
+	StandingsFile() string
+	// SessionDataFolder is where the current session's data is saved, empty if it isn't being saved
+	SessionDataFolder() string
+	AlertRules() []alerts.Rule
+	SetAlertRules(rules []alerts.Rule) error
+	Webhooks() []webhooks.Hook
+	SetWebhooks(hooks []webhooks.Hook) error
+	MQTTSettings() (settings mqttPublisher.Settings, enabled bool)
+	OSCSettings() (settings oscOutput.Settings, enabled bool)
 }
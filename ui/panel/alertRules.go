@@ -0,0 +1,352 @@
+// F1Gopher - Copyright (C) 2025 f1gopher
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package panel
+
+import (
+	"f1gopher/alerts"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/AllenDang/cimgui-go/imgui"
+	"github.com/AllenDang/giu"
+	"github.com/f1gopher/f1gopherlib"
+	"github.com/f1gopher/f1gopherlib/Messages"
+	"go.uber.org/zap"
+	"golang.org/x/image/colornames"
+)
+
+// Number of triggered alerts kept for the history table
+const alertHistorySize = 50
+
+// The tone played for the sound action
+const alertToneFrequency = 880
+const alertToneDuration = 300 * time.Millisecond
+
+type alertRules struct {
+	notifications *Notifications
+	audio         *audioOutput
+	logger        *zap.SugaredLogger
+	config        PanelConfig
+
+	// The engine and history are used by the data and UI threads
+	engine      *alerts.Engine
+	history     []alerts.Alert
+	driverNames []string
+	driverNums  []int
+	timezone    *time.Location
+	lock        sync.Mutex
+
+	// Only used by the UI thread
+	rules       []alerts.Rule
+	deleteIndex int
+	status      string
+}
+
+func CreateAlertRules(notifications *Notifications, audio *audioOutput, logger *zap.SugaredLogger) Panel {
+	return &alertRules{
+		notifications: notifications,
+		audio:         audio,
+		logger:        logger,
+		engine:        alerts.NewEngine(),
+		driverNames:   []string{"Any"},
+		driverNums:    []int{alerts.AnyDriver},
+		deleteIndex:   -1,
+	}
+}
+
+func (a *alertRules) ProcessRadio(data Messages.Radio)         {}
+func (a *alertRules) ProcessLocation(data Messages.Location)   {}
+func (a *alertRules) ProcessTelemetry(data Messages.Telemetry) {}
+func (a *alertRules) ProcessEventTime(data Messages.EventTime) {}
+func (a *alertRules) Close()                                   {}
+
+func (a *alertRules) Type() Type { return AlertRules }
+
+func (a *alertRules) Init(dataSrc f1gopherlib.F1GopherLib, config PanelConfig) {
+	a.config = config
+	a.rules = config.AlertRules()
+	a.deleteIndex = -1
+	a.status = ""
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	a.engine.Reset()
+	a.engine.SetRules(a.rules)
+	a.history = nil
+	a.driverNames = []string{"Any"}
+	a.driverNums = []int{alerts.AnyDriver}
+	a.timezone = dataSrc.CircuitTimezone()
+}
+
+func (a *alertRules) ProcessDrivers(data Messages.Drivers) {
+	drivers := make([]Messages.DriverInfo, len(data.Drivers))
+	copy(drivers, data.Drivers)
+	sort.Slice(drivers, func(i, j int) bool { return drivers[i].Number < drivers[j].Number })
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	a.engine.ProcessDrivers(data)
+	a.driverNames = []string{"Any"}
+	a.driverNums = []int{alerts.AnyDriver}
+	for _, driver := range drivers {
+		a.driverNames = append(a.driverNames, fmt.Sprintf("%s (%d)", driver.ShortName, driver.Number))
+		a.driverNums = append(a.driverNums, driver.Number)
+	}
+}
+
+func (a *alertRules) ProcessTiming(data Messages.Timing) {
+	a.lock.Lock()
+	triggered := a.engine.ProcessTiming(data)
+	a.lock.Unlock()
+	a.trigger(triggered)
+}
+
+func (a *alertRules) ProcessEvent(data Messages.Event) {
+	a.lock.Lock()
+	triggered := a.engine.ProcessEvent(data)
+	a.lock.Unlock()
+	a.trigger(triggered)
+}
+
+func (a *alertRules) ProcessRaceControlMessages(data Messages.RaceControlMessage) {
+	a.lock.Lock()
+	triggered := a.engine.ProcessRaceControlMessage(data)
+	a.lock.Unlock()
+	a.trigger(triggered)
+}
+
+func (a *alertRules) ProcessWeather(data Messages.Weather) {
+	a.lock.Lock()
+	triggered := a.engine.ProcessWeather(data)
+	a.lock.Unlock()
+	a.trigger(triggered)
+}
+
+// trigger performs the action for each alert and adds them to the history
+func (a *alertRules) trigger(triggered []alerts.Alert) {
+	if len(triggered) == 0 {
+		return
+	}
+
+	for _, alert := range triggered {
+		switch alert.Rule.Action {
+		case alerts.Toast:
+			a.notifications.Notify(fmt.Sprintf("%s: %s", alert.Rule.Name, alert.Text), colornames.Yellow)
+		case alerts.Sound:
+			a.audio.playTone(alertToneFrequency, alertToneDuration)
+		case alerts.Log:
+			a.logger.Infof("Alert %s: %s", alert.Rule.Name, alert.Text)
+		}
+	}
+
+	a.lock.Lock()
+	a.history = append(a.history, triggered...)
+	if len(a.history) > alertHistorySize {
+		a.history = a.history[len(a.history)-alertHistorySize:]
+	}
+	a.lock.Unlock()
+}
+
+// updateRules gives the edited rules to the engine without saving them
+func (a *alertRules) updateRules() {
+	a.lock.Lock()
+	a.engine.SetRules(a.rules)
+	a.lock.Unlock()
+}
+
+// saveRules saves the edited rules with the config
+func (a *alertRules) saveRules() {
+	a.status = ""
+	if err := a.config.SetAlertRules(a.rules); err != nil {
+		a.status = fmt.Sprintf("Failed to save the rules: %s", err)
+	}
+}
+
+// applyRules gives the edited rules to the engine and saves them with the config
+func (a *alertRules) applyRules() {
+	a.updateRules()
+	a.saveRules()
+}
+
+// saveAfterEdit saves the rules once the previous widget has finished being edited, so text boxes and sliders don't
+// save the config for every key press or frame the slider is dragged
+func (a *alertRules) saveAfterEdit() giu.Widget {
+	return giu.Custom(func() {
+		if imgui.IsItemDeactivatedAfterEdit() {
+			a.saveRules()
+		}
+	})
+}
+
+func (a *alertRules) Draw(width int, height int) []giu.Widget {
+	if a.config == nil {
+		return []giu.Widget{}
+	}
+
+	// Remove rules after the UI has been built so the widgets don't point at moved rules
+	if a.deleteIndex >= 0 && a.deleteIndex < len(a.rules) {
+		a.rules = append(a.rules[:a.deleteIndex], a.rules[a.deleteIndex+1:]...)
+		a.deleteIndex = -1
+		a.applyRules()
+	}
+
+	a.lock.Lock()
+	driverNames := a.driverNames
+	driverNums := a.driverNums
+	history := make([]alerts.Alert, len(a.history))
+	copy(history, a.history)
+	timezone := a.timezone
+	a.lock.Unlock()
+
+	conditionNames := []string{}
+	for _, condition := range alerts.Conditions() {
+		conditionNames = append(conditionNames, condition.String())
+	}
+	actionNames := []string{}
+	for _, action := range alerts.Actions() {
+		actionNames = append(actionNames, action.String())
+	}
+	sectorNames := []string{"Any", "1", "2", "3"}
+
+	driverCombo := func(id string, driverNumber *int) giu.Widget {
+		selected := int32(0)
+		for x, number := range driverNums {
+			if number == *driverNumber {
+				selected = int32(x)
+			}
+		}
+		return giu.Combo(id, driverNames[selected], driverNames, &selected).Size(100).OnChange(func() {
+			*driverNumber = driverNums[selected]
+			a.applyRules()
+		})
+	}
+
+	ruleRows := make([]*giu.TableRowWidget, 0, len(a.rules))
+	for x := range a.rules {
+		rule := &a.rules[x]
+
+		condition := int32(rule.Condition)
+		action := int32(rule.Action)
+		sector := int32(rule.Sector)
+		gap := float32(rule.GapSeconds)
+
+		widgets := []giu.Widget{
+			giu.Checkbox(fmt.Sprintf("##enabled%d", x), &rule.Enabled).OnChange(a.applyRules),
+			giu.Layout{
+				giu.InputText(&rule.Name).Label(fmt.Sprintf("##name%d", x)).Size(150).OnChange(a.updateRules),
+				a.saveAfterEdit(),
+			},
+			giu.Combo(fmt.Sprintf("##condition%d", x), rule.Condition.String(), conditionNames, &condition).Size(150).
+				OnChange(func() {
+					// Keep custom names but follow the condition for the default name
+					if rule.Name == rule.Condition.String() {
+						rule.Name = alerts.Condition(condition).String()
+					}
+					rule.Condition = alerts.Condition(condition)
+					if rule.Condition == alerts.GapBelow && rule.GapSeconds == 0 {
+						rule.GapSeconds = alerts.NewRule(alerts.GapBelow).GapSeconds
+					}
+					a.applyRules()
+				}),
+		}
+
+		switch rule.Condition {
+		case alerts.DriverPits, alerts.Investigation:
+			widgets = append(widgets, driverCombo(fmt.Sprintf("##driver%d", x), &rule.Driver), giu.Label(""))
+		case alerts.SectorPersonalBest:
+			widgets = append(widgets,
+				driverCombo(fmt.Sprintf("##driver%d", x), &rule.Driver),
+				giu.Combo(fmt.Sprintf("##sector%d", x), sectorNames[sector], sectorNames, &sector).Size(60).
+					OnChange(func() {
+						rule.Sector = int(sector)
+						a.applyRules()
+					}))
+		case alerts.GapBelow:
+			widgets = append(widgets,
+				giu.Row(
+					driverCombo(fmt.Sprintf("##driver%d", x), &rule.Driver),
+					driverCombo(fmt.Sprintf("##other%d", x), &rule.OtherDriver)),
+				giu.Layout{
+					giu.SliderFloat(&gap, 0.1, 5.0).Label(fmt.Sprintf("##gap%d", x)).Format("%.1fs").Size(100).
+						OnChange(func() {
+							rule.GapSeconds = float64(gap)
+							a.updateRules()
+						}),
+					a.saveAfterEdit(),
+				})
+		default:
+			widgets = append(widgets, giu.Label(""), giu.Label(""))
+		}
+
+		widgets = append(widgets,
+			giu.Combo(fmt.Sprintf("##action%d", x), rule.Action.String(), actionNames, &action).Size(80).
+				OnChange(func() {
+					rule.Action = alerts.Action(action)
+					a.applyRules()
+				}),
+			giu.Button(fmt.Sprintf("Delete##%d", x)).OnClick(func() { a.deleteIndex = x }),
+		)
+
+		ruleRows = append(ruleRows, giu.TableRow(widgets...))
+	}
+
+	// Newest first
+	historyRows := make([]*giu.TableRowWidget, 0, len(history))
+	for x := len(history) - 1; x >= 0; x-- {
+		historyRows = append(historyRows, giu.TableRow(
+			giu.Label(history[x].Timestamp.In(timezone).Format("15:04:05")),
+			giu.Label(history[x].Rule.Name),
+			giu.Label(history[x].Text).Wrapped(true),
+		))
+	}
+
+	tableHeight := float32(height-70) / 2
+
+	return []giu.Widget{
+		giu.Row(
+			giu.Button("Add Rule").OnClick(func() {
+				a.rules = append(a.rules, alerts.NewRule(alerts.DriverPits))
+				a.applyRules()
+			}),
+			giu.Style().SetColor(giu.StyleColorText, colornames.Red).To(giu.Label(a.status)),
+		),
+		giu.Table().Flags(giu.TableFlagsResizable|giu.TableFlagsSizingFixedSame|giu.TableFlagsScrollY).
+			Size(float32(width-16), tableHeight).
+			Columns(
+				giu.TableColumn("On").InnerWidthOrWeight(25),
+				giu.TableColumn("Name").InnerWidthOrWeight(155),
+				giu.TableColumn("Condition").InnerWidthOrWeight(155),
+				giu.TableColumn("Drivers").InnerWidthOrWeight(215),
+				giu.TableColumn("Gap/Sector").InnerWidthOrWeight(105),
+				giu.TableColumn("Action").InnerWidthOrWeight(85),
+				giu.TableColumn("").InnerWidthOrWeight(60),
+			).
+			Rows(ruleRows...),
+		giu.Label("Triggered"),
+		giu.Table().FastMode(true).Flags(giu.TableFlagsResizable|giu.TableFlagsSizingFixedSame|giu.TableFlagsScrollY).
+			Size(float32(width-16), tableHeight).
+			Columns(
+				giu.TableColumn("Time").InnerWidthOrWeight(60),
+				giu.TableColumn("Rule").InnerWidthOrWeight(150),
+				giu.TableColumn("Alert").InnerWidthOrWeight(500),
+			).
+			Rows(historyRows...),
+	}
+}
@@ -0,0 +1,81 @@
+// F1Gopher - Copyright (C) 2025 f1gopher
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package panel
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/ebitengine/oto/v3"
+)
+
+// Must match the options the team radio creates the audio context with
+const audioSampleRate = 48000
+const audioChannels = 2
+
+// audioOutput shares the audio context created by the team radio so other panels can play sounds
+type audioOutput struct {
+	context *oto.Context
+	lock    sync.Mutex
+}
+
+func CreateAudioOutput() *audioOutput {
+	return &audioOutput{}
+}
+
+func (a *audioOutput) set(context *oto.Context) {
+	a.lock.Lock()
+	a.context = context
+	a.lock.Unlock()
+}
+
+// playTone plays a sine wave in the background, does nothing if there is no audio context
+func (a *audioOutput) playTone(frequency float64, duration time.Duration) {
+	a.lock.Lock()
+	context := a.context
+	a.lock.Unlock()
+
+	if context == nil {
+		return
+	}
+
+	samples := int(duration.Seconds() * audioSampleRate)
+	// Fade in and out to avoid clicks
+	fade := audioSampleRate / 100
+
+	// The context uses the default format of little endian float32 samples
+	data := make([]byte, samples*audioChannels*4)
+	for x := 0; x < samples; x++ {
+		volume := 0.3 * math.Min(1, float64(min(x, samples-x))/float64(fade))
+		value := float32(volume * math.Sin(2*math.Pi*frequency*float64(x)/audioSampleRate))
+		for channel := 0; channel < audioChannels; channel++ {
+			binary.LittleEndian.PutUint32(data[(x*audioChannels+channel)*4:], math.Float32bits(value))
+		}
+	}
+
+	go func() {
+		player := context.NewPlayer(bytes.NewReader(data))
+		defer player.Close()
+		player.Play()
+
+		for player.IsPlaying() {
+			time.Sleep(time.Millisecond * 50)
+		}
+	}()
+}
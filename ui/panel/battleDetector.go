@@ -0,0 +1,328 @@
+// F1Gopher - Copyright (C) 2025 f1gopher
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package panel
+
+import (
+	"image/color"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/f1gopher/f1gopherlib/Messages"
+)
+
+// How long a battle is flagged as new after it forms
+const newBattleDuration = 30 * time.Second
+
+// Colors to tell the trains apart beside the timing table
+var battleColors = []color.RGBA{
+	{R: 255, G: 140, B: 0, A: 255},
+	{R: 0, G: 200, B: 255, A: 255},
+	{R: 255, G: 80, B: 200, A: 255},
+	{R: 150, G: 255, B: 80, A: 255},
+	{R: 255, G: 230, B: 60, A: 255},
+}
+
+type battleTrend int
+
+const (
+	battleSteady battleTrend = iota
+	battleClosing
+	battleSpreading
+)
+
+func (b battleTrend) String() string {
+	return [...]string{"Steady", "Closing", "Spreading"}[b]
+}
+
+// battle is a group of consecutive cars that are all within the threshold of the car ahead of them
+type battle struct {
+	id int
+	// In position order
+	drivers  []int
+	started  time.Time
+	startLap int
+	// Gap from the first to the last car in the train at the end of each lap
+	spreadByLap []time.Duration
+	lastLap     int
+	spread      time.Duration
+}
+
+func (b *battle) trend() battleTrend {
+	if len(b.spreadByLap) < 2 {
+		return battleSteady
+	}
+
+	change := b.spreadByLap[len(b.spreadByLap)-1] - b.spreadByLap[len(b.spreadByLap)-2]
+	switch {
+	case change < -100*time.Millisecond:
+		return battleClosing
+	case change > 100*time.Millisecond:
+		return battleSpreading
+	}
+	return battleSteady
+}
+
+func (b *battle) color() color.RGBA {
+	return battleColors[b.id%len(battleColors)]
+}
+
+// battleDetector groups the cars in a race into trains. It is shared between the battles panel that feeds it and the
+// timing table that marks the trains.
+type battleDetector struct {
+	threshold time.Duration
+	event     Messages.Event
+	timing    map[int]Messages.Timing
+	battles   []*battle
+	nextId    int
+	now       time.Time
+	lock      sync.Mutex
+}
+
+func CreateBattleDetector() *battleDetector {
+	detector := &battleDetector{threshold: time.Second}
+	detector.Reset()
+	return detector
+}
+
+func (b *battleDetector) Reset() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.event = Messages.Event{}
+	b.timing = map[int]Messages.Timing{}
+	b.battles = nil
+	b.nextId = 0
+	b.now = time.Time{}
+}
+
+func (b *battleDetector) Threshold() time.Duration {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.threshold
+}
+
+func (b *battleDetector) SetThreshold(threshold time.Duration) {
+	b.lock.Lock()
+	b.threshold = threshold
+	b.lock.Unlock()
+}
+
+func (b *battleDetector) ProcessEvent(data Messages.Event) {
+	b.lock.Lock()
+	b.event = data
+	b.lock.Unlock()
+}
+
+func (b *battleDetector) ProcessEventTime(data Messages.EventTime) {
+	b.lock.Lock()
+	b.now = data.Timestamp
+	b.lock.Unlock()
+}
+
+func (b *battleDetector) ProcessTiming(data Messages.Timing) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.timing[data.Number] = data
+
+	if b.event.Type != Messages.Race && b.event.Type != Messages.Sprint {
+		return
+	}
+
+	b.update(data.Timestamp)
+}
+
+// update regroups the cars into trains, keeping the history of trains that still share at least two cars
+func (b *battleDetector) update(timestamp time.Time) {
+	drivers := make([]Messages.Timing, 0, len(b.timing))
+	for _, driver := range b.timing {
+		drivers = append(drivers, driver)
+	}
+	sort.Slice(drivers, func(i, j int) bool { return drivers[i].Position < drivers[j].Position })
+
+	inBattle := func(driver Messages.Timing) bool {
+		return driver.Location != Messages.Stopped && driver.Location != Messages.Pitlane && driver.Location != Messages.PitOut
+	}
+
+	groups := [][]Messages.Timing{}
+	var current []Messages.Timing
+	for x, driver := range drivers {
+		following := x > 0 && inBattle(driver) && inBattle(drivers[x-1]) &&
+			driver.TimeDiffToPositionAhead > 0 && driver.TimeDiffToPositionAhead < b.threshold
+
+		if following {
+			if len(current) == 0 {
+				current = []Messages.Timing{drivers[x-1]}
+			}
+			current = append(current, driver)
+			continue
+		}
+
+		if len(current) > 1 {
+			groups = append(groups, current)
+		}
+		current = nil
+	}
+	if len(current) > 1 {
+		groups = append(groups, current)
+	}
+
+	battles := make([]*battle, 0, len(groups))
+	for _, group := range groups {
+		numbers := make([]int, len(group))
+		var spread time.Duration
+		for x := range group {
+			numbers[x] = group[x].Number
+			if x > 0 {
+				spread += group[x].TimeDiffToPositionAhead
+			}
+		}
+
+		// Continue the existing train with the most cars in common
+		var existing *battle
+		mostShared := 1
+		for _, previous := range b.battles {
+			if shared := sharedDrivers(previous.drivers, numbers); shared > mostShared {
+				existing = previous
+				mostShared = shared
+			}
+		}
+
+		if existing == nil {
+			existing = &battle{
+				id:       b.nextId,
+				started:  timestamp,
+				startLap: group[0].Lap,
+				lastLap:  group[0].Lap,
+			}
+			b.nextId++
+		} else {
+			// Don't let two new trains continue the same one
+			b.battles = removeBattle(b.battles, existing)
+		}
+
+		existing.drivers = numbers
+		existing.spread = spread
+		if group[0].Lap != existing.lastLap {
+			existing.lastLap = group[0].Lap
+			existing.spreadByLap = append(existing.spreadByLap, spread)
+		}
+
+		battles = append(battles, existing)
+	}
+
+	b.battles = battles
+}
+
+func sharedDrivers(a []int, b []int) int {
+	shared := 0
+	for _, x := range a {
+		for _, y := range b {
+			if x == y {
+				shared++
+			}
+		}
+	}
+	return shared
+}
+
+func removeBattle(battles []*battle, remove *battle) []*battle {
+	result := make([]*battle, 0, len(battles))
+	for _, battle := range battles {
+		if battle != remove {
+			result = append(result, battle)
+		}
+	}
+	return result
+}
+
+// currentBattles returns a copy of the trains in position order and the current session time
+func (b *battleDetector) currentBattles() ([]battle, time.Time) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	result := make([]battle, 0, len(b.battles))
+	for _, current := range b.battles {
+		copied := *current
+		copied.drivers = append([]int{}, current.drivers...)
+		copied.spreadByLap = append([]time.Duration{}, current.spreadByLap...)
+		result = append(result, copied)
+	}
+	return result, b.now
+}
+
+// BattlePair is the two cars in a train with the smallest gap between them
+type BattlePair struct {
+	Ahead  int
+	Behind int
+	Gap    time.Duration
+	Trend  string
+}
+
+// ClosestPair returns the two cars with the smallest gap in any train and false if there aren't any trains
+func (b *battleDetector) ClosestPair() (BattlePair, bool) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	var closest BattlePair
+	found := false
+	for _, current := range b.battles {
+		for x := 1; x < len(current.drivers); x++ {
+			gap := b.timing[current.drivers[x]].TimeDiffToPositionAhead
+			if !found || gap < closest.Gap {
+				closest = BattlePair{
+					Ahead:  current.drivers[x-1],
+					Behind: current.drivers[x],
+					Gap:    gap,
+					Trend:  current.trend().String(),
+				}
+				found = true
+			}
+		}
+	}
+	return closest, found
+}
+
+type battleBracket struct {
+	bracket string
+	color   color.RGBA
+	isNew   bool
+}
+
+// driverBrackets returns the bracket to draw beside each driver in a train
+func (b *battleDetector) driverBrackets() map[int]battleBracket {
+	battles, now := b.currentBattles()
+
+	result := map[int]battleBracket{}
+	for _, current := range battles {
+		for x, driverNumber := range current.drivers {
+			bracket := "│"
+			switch x {
+			case 0:
+				bracket = "┐"
+			case len(current.drivers) - 1:
+				bracket = "┘"
+			}
+			result[driverNumber] = battleBracket{
+				bracket: bracket,
+				color:   current.color(),
+				isNew:   now.Sub(current.started) < newBattleDuration,
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,158 @@
+package panel
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/f1gopher/f1gopherlib/Messages"
+)
+
+func TestBattleDetector(t *testing.T) {
+	type car struct {
+		number   int
+		interval time.Duration
+		location Messages.CarLocation
+	}
+
+	tests := []struct {
+		name      string
+		session   Messages.EventType
+		threshold time.Duration
+		// In position order
+		cars     []car
+		expected [][]int
+	}{
+		{
+			name:    "train",
+			session: Messages.Race,
+			cars: []car{
+				{number: 1, location: Messages.OnTrack},
+				{number: 16, interval: 800 * time.Millisecond, location: Messages.OnTrack},
+				{number: 4, interval: 500 * time.Millisecond, location: Messages.OnTrack},
+				{number: 81, interval: 3 * time.Second, location: Messages.OnTrack},
+			},
+			expected: [][]int{{1, 16, 4}},
+		},
+		{
+			name:    "two trains",
+			session: Messages.Sprint,
+			cars: []car{
+				{number: 1, location: Messages.OnTrack},
+				{number: 16, interval: 800 * time.Millisecond, location: Messages.OnTrack},
+				{number: 4, interval: 2 * time.Second, location: Messages.OnTrack},
+				{number: 81, interval: 300 * time.Millisecond, location: Messages.OnTrack},
+			},
+			expected: [][]int{{1, 16}, {4, 81}},
+		},
+		{
+			name:    "pit stop splits the train",
+			session: Messages.Race,
+			cars: []car{
+				{number: 1, location: Messages.OnTrack},
+				{number: 16, interval: 800 * time.Millisecond, location: Messages.OnTrack},
+				{number: 4, interval: 500 * time.Millisecond, location: Messages.Pitlane},
+				{number: 81, interval: 300 * time.Millisecond, location: Messages.OnTrack},
+				{number: 44, interval: 300 * time.Millisecond, location: Messages.OnTrack},
+			},
+			expected: [][]int{{1, 16}, {81, 44}},
+		},
+		{
+			name:    "retired car",
+			session: Messages.Race,
+			cars: []car{
+				{number: 1, location: Messages.OnTrack},
+				{number: 16, interval: 800 * time.Millisecond, location: Messages.Stopped},
+				{number: 4, interval: 500 * time.Millisecond, location: Messages.OnTrack},
+			},
+			expected: [][]int{},
+		},
+		{
+			name:      "larger threshold",
+			session:   Messages.Race,
+			threshold: 2500 * time.Millisecond,
+			cars: []car{
+				{number: 1, location: Messages.OnTrack},
+				{number: 16, interval: 800 * time.Millisecond, location: Messages.OnTrack},
+				{number: 4, interval: 2 * time.Second, location: Messages.OnTrack},
+				{number: 81, interval: 3 * time.Second, location: Messages.OnTrack},
+			},
+			expected: [][]int{{1, 16, 4}},
+		},
+		{
+			name:    "not a race",
+			session: Messages.Practice1,
+			cars: []car{
+				{number: 1, location: Messages.OnTrack},
+				{number: 16, interval: 800 * time.Millisecond, location: Messages.OnTrack},
+			},
+			expected: [][]int{},
+		},
+	}
+
+	for _, test := range tests {
+		detector := CreateBattleDetector()
+		if test.threshold > 0 {
+			detector.SetThreshold(test.threshold)
+		}
+		detector.ProcessEvent(Messages.Event{Type: test.session, Status: Messages.Started})
+
+		for x, car := range test.cars {
+			detector.ProcessTiming(Messages.Timing{
+				Number:                  car.number,
+				Position:                x + 1,
+				Lap:                     5,
+				TimeDiffToPositionAhead: car.interval,
+				Location:                car.location,
+			})
+		}
+
+		battles, _ := detector.currentBattles()
+		trains := [][]int{}
+		for _, current := range battles {
+			trains = append(trains, current.drivers)
+		}
+		if !slices.EqualFunc(trains, test.expected, slices.Equal) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, trains)
+		}
+	}
+}
+
+func TestBattleTrend(t *testing.T) {
+	detector := CreateBattleDetector()
+	detector.ProcessEvent(Messages.Event{Type: Messages.Race, Status: Messages.Started})
+
+	spreads := []struct {
+		interval time.Duration
+		trend    battleTrend
+	}{
+		{interval: 900 * time.Millisecond, trend: battleSteady},
+		{interval: 900 * time.Millisecond, trend: battleSteady},
+		{interval: 600 * time.Millisecond, trend: battleClosing},
+		{interval: 650 * time.Millisecond, trend: battleSteady},
+		{interval: 900 * time.Millisecond, trend: battleSpreading},
+	}
+
+	// The spread is recorded when the first car in the train starts a new lap
+	for lap, spread := range spreads {
+		detector.ProcessTiming(Messages.Timing{Number: 16, Position: 2, Lap: lap + 1, Location: Messages.OnTrack,
+			TimeDiffToPositionAhead: spread.interval})
+		detector.ProcessTiming(Messages.Timing{Number: 1, Position: 1, Lap: lap + 1, Location: Messages.OnTrack})
+
+		battles, _ := detector.currentBattles()
+		if len(battles) != 1 {
+			t.Fatalf("lap %d: expected 1 battle, got %d", lap+1, len(battles))
+		}
+		if trend := battles[0].trend(); trend != spread.trend {
+			t.Errorf("lap %d: expected %s, got %s", lap+1, spread.trend, trend)
+		}
+		if battles[0].startLap != 1 {
+			t.Errorf("lap %d: expected the battle to continue from lap 1, got %d", lap+1, battles[0].startLap)
+		}
+	}
+
+	pair, found := detector.ClosestPair()
+	if !found || pair.Ahead != 1 || pair.Behind != 16 || pair.Gap != 900*time.Millisecond || pair.Trend != "Spreading" {
+		t.Errorf("unexpected closest pair %+v", pair)
+	}
+}
@@ -0,0 +1,199 @@
+// F1Gopher - Copyright (C) 2025 f1gopher
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package panel
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AllenDang/giu"
+	"github.com/f1gopher/f1gopherlib"
+	"github.com/f1gopher/f1gopherlib/Messages"
+	"golang.org/x/image/colornames"
+)
+
+type battles struct {
+	detector *battleDetector
+	focus    *DriverFocus
+
+	drivers     map[int]overtakesDriver
+	driversLock sync.Mutex
+	isRace      bool
+
+	// Seconds, edited in the panel and copied to the detector
+	threshold float32
+}
+
+func CreateBattles(detector *battleDetector, focus *DriverFocus) Panel {
+	return &battles{
+		detector:  detector,
+		focus:     focus,
+		drivers:   map[int]overtakesDriver{},
+		threshold: float32(detector.Threshold().Seconds()),
+	}
+}
+
+func (b *battles) ProcessRaceControlMessages(data Messages.RaceControlMessage) {}
+func (b *battles) ProcessWeather(data Messages.Weather)                        {}
+func (b *battles) ProcessRadio(data Messages.Radio)                            {}
+func (b *battles) ProcessLocation(data Messages.Location)                      {}
+func (b *battles) ProcessTelemetry(data Messages.Telemetry)                    {}
+func (b *battles) Close()                                                      {}
+
+// The detector is shared with the timing table but only fed from here
+
+func (b *battles) ProcessEventTime(data Messages.EventTime) {
+	b.detector.ProcessEventTime(data)
+}
+
+func (b *battles) ProcessEvent(data Messages.Event) {
+	b.detector.ProcessEvent(data)
+}
+
+func (b *battles) ProcessTiming(data Messages.Timing) {
+	b.detector.ProcessTiming(data)
+}
+
+func (b *battles) Type() Type { return Battles }
+
+func (b *battles) Init(dataSrc f1gopherlib.F1GopherLib, config PanelConfig) {
+	b.isRace = dataSrc.Session() == Messages.RaceSession || dataSrc.Session() == Messages.SprintSession
+	b.detector.Reset()
+
+	b.driversLock.Lock()
+	b.drivers = map[int]overtakesDriver{}
+	b.driversLock.Unlock()
+}
+
+func (b *battles) ProcessDrivers(data Messages.Drivers) {
+	b.driversLock.Lock()
+	defer b.driversLock.Unlock()
+
+	for _, driver := range data.Drivers {
+		b.drivers[driver.Number] = overtakesDriver{name: driver.ShortName, color: driver.Color}
+	}
+}
+
+func (b *battles) driver(driverNumber int) overtakesDriver {
+	b.driversLock.Lock()
+	defer b.driversLock.Unlock()
+
+	driver, exists := b.drivers[driverNumber]
+	if !exists {
+		return overtakesDriver{name: fmt.Sprintf("%d", driverNumber), color: colornames.White}
+	}
+	return driver
+}
+
+func (b *battles) Draw(width int, height int) []giu.Widget {
+	if !b.isRace {
+		return []giu.Widget{giu.Label("Battles are only tracked during races")}
+	}
+
+	trains, now := b.detector.currentBattles()
+
+	rows := make([]*giu.TableRowWidget, 0, len(trains))
+	for x := range trains {
+		train := &trains[x]
+
+		names := make([]giu.Widget, 0, len(train.drivers))
+		var focusColor color.RGBA
+		focused := false
+		for _, driverNumber := range train.drivers {
+			driver := b.driver(driverNumber)
+			names = append(names, giu.Style().SetColor(giu.StyleColorText, driver.color).To(giu.Label(driver.name)))
+			if highlightColor, isFocused := b.focus.highlight(driverNumber); isFocused && !focused {
+				focusColor = highlightColor
+				focused = true
+			}
+		}
+
+		trend := train.trend()
+		trendColor := colornames.White
+		switch trend {
+		case battleClosing:
+			trendColor = colornames.Green
+		case battleSpreading:
+			trendColor = colornames.Red
+		}
+
+		state := ""
+		if now.Sub(train.started) < newBattleDuration {
+			state = "NEW"
+		}
+
+		spreads := make([]string, 0, len(train.spreadByLap))
+		for _, spread := range lastSpreads(train.spreadByLap, 5) {
+			spreads = append(spreads, fmt.Sprintf("%.1f", spread.Seconds()))
+		}
+
+		leader := train.drivers[0]
+		row := giu.TableRow(
+			giu.Selectable(fmt.Sprintf("%d", len(train.drivers))).
+				Flags(giu.SelectableFlagsSpanAllColumns).
+				OnClick(func() { b.focus.Select(leader) }),
+			giu.Style().SetColor(giu.StyleColorText, train.color()).To(giu.Label("■")),
+			giu.Style().SetStyleFloat(giu.StyleVarItemSpacing, 4).To(giu.Row(names...)),
+			giu.Label(fmtDuration(train.spread)),
+			giu.Style().SetColor(giu.StyleColorText, trendColor).To(giu.Label(trend.String())),
+			giu.Label(strings.Join(spreads, " ")),
+			giu.Label(fmt.Sprintf("%d", train.startLap)),
+			giu.Label(now.Sub(train.started).Truncate(time.Second).String()),
+			giu.Style().SetColor(giu.StyleColorText, colornames.Orange).To(giu.Label(state)),
+		)
+		if focused {
+			row = row.BgColor(focusColor)
+		}
+		rows = append(rows, row)
+	}
+
+	return []giu.Widget{
+		giu.Row(
+			giu.SliderFloat(&b.threshold, 0.2, 3.0).
+				Format("%.1fs").
+				Size(150).
+				Label("Battle Gap").
+				OnChange(func() {
+					b.detector.SetThreshold(time.Duration(float64(b.threshold) * float64(time.Second)))
+				}),
+		),
+		giu.Table().FastMode(true).Flags(giu.TableFlagsResizable|giu.TableFlagsSizingFixedSame|giu.TableFlagsScrollY).
+			Size(float32(width-16), float32(height-40)).
+			Columns(
+				giu.TableColumn("Cars").InnerWidthOrWeight(35),
+				giu.TableColumn("").InnerWidthOrWeight(15),
+				giu.TableColumn("Drivers").InnerWidthOrWeight(200),
+				giu.TableColumn("Spread").InnerWidthOrWeight(timeWidth),
+				giu.TableColumn("Trend").InnerWidthOrWeight(65),
+				giu.TableColumn("By Lap").InnerWidthOrWeight(130),
+				giu.TableColumn("Since Lap").InnerWidthOrWeight(60),
+				giu.TableColumn("Duration").InnerWidthOrWeight(60),
+				giu.TableColumn("").InnerWidthOrWeight(35),
+			).
+			Rows(rows...),
+	}
+}
+
+// lastSpreads returns up to the last count spreads
+func lastSpreads(spreads []time.Duration, count int) []time.Duration {
+	if len(spreads) <= count {
+		return spreads
+	}
+	return spreads[len(spreads)-count:]
+}
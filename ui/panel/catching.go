@@ -52,6 +52,9 @@ type catchingBlock struct {
 
 	selectedDriver3Number int
 
+	// When not pinned the tracker follows the focused driver
+	pinned bool
+
 	table *giu.TableWidget
 }
 
@@ -66,10 +69,11 @@ type catching struct {
 	removes []int
 
 	config PanelConfig
+	focus  *DriverFocus
 }
 
-func CreateCatching() Panel {
-	return &catching{}
+func CreateCatching(focus *DriverFocus) Panel {
+	return &catching{focus: focus}
 }
 
 func (c *catching) ProcessEventTime(data Messages.EventTime)                    {}
@@ -193,6 +197,10 @@ func (c *catching) Draw(width int, height int) (widgets []giu.Widget) {
 		block := &c.blocks[x]
 		blockIndex := x
 
+		if !block.pinned {
+			c.followFocus(block)
+		}
+
 		c.update(x)
 
 		if block.selectedDriver1Number != NothingSelected && block.selectedDriver2Number != NothingSelected {
@@ -230,6 +238,7 @@ func (c *catching) Draw(width int, height int) (widgets []giu.Widget) {
 					giu.Combo("Driver", driverName1, c.driverNames, &block.selectedDriver1Index).OnChange(func() {
 						for num, driver := range c.driverData {
 							if driver.name == c.driverNames[block.selectedDriver1Index] {
+								block.pinned = true
 								block.selectedDriver3Number = NothingSelected
 								block.selectedDriver1Number = num
 								break
@@ -239,12 +248,14 @@ func (c *catching) Draw(width int, height int) (widgets []giu.Widget) {
 					giu.Combo("Other Driver", driverName2, c.driverNames, &block.selectedDriver2Index).OnChange(func() {
 						for num, driver := range c.driverData {
 							if driver.name == c.driverNames[block.selectedDriver2Index] {
+								block.pinned = true
 								block.selectedDriver3Number = NothingSelected
 								block.selectedDriver2Number = num
 								break
 							}
 						}
 					}).Size(100),
+					giu.Checkbox("Pin", &block.pinned),
 					giu.Button("Remove").OnClick(func() {
 						c.removes = append(c.removes, blockIndex)
 					}),
@@ -263,6 +274,7 @@ func (c *catching) Draw(width int, height int) (widgets []giu.Widget) {
 					giu.Combo("Driver", driverName1, c.driverNames, &block.selectedDriver1Index).OnChange(func() {
 						for num, driver := range c.driverData {
 							if driver.name == c.driverNames[block.selectedDriver1Index] {
+								block.pinned = true
 								block.selectedDriver1Number = num
 
 								if block.mode == Teammate {
@@ -276,6 +288,7 @@ func (c *catching) Draw(width int, height int) (widgets []giu.Widget) {
 							}
 						}
 					}).Size(100),
+					giu.Checkbox("Pin", &block.pinned),
 					giu.Button("Remove").OnClick(func() {
 						c.removes = append(c.removes, blockIndex)
 					}),
@@ -486,6 +499,37 @@ func (c *catching) driverComparison(driver1Number int, driver2Number int, driver
 	return topRow, rows
 }
 
+// followFocus updates the tracker to the focused driver, and for a driver to driver tracker the comparison driver
+func (c *catching) followFocus(block *catchingBlock) {
+	focused := c.focus.Focused()
+	if _, exists := c.driverData[focused]; exists && focused != block.selectedDriver1Number {
+		block.selectedDriver1Number = focused
+		block.selectedDriver1Index = c.driverIndex(focused)
+		block.selectedDriver3Number = NothingSelected
+
+		if block.mode == Teammate {
+			block.selectedDriver2Number = c.findTeammate(focused)
+		}
+	}
+
+	comparison := c.focus.Comparison()
+	if _, exists := c.driverData[comparison]; exists && block.mode == AnotherDriver && comparison != block.selectedDriver2Number {
+		block.selectedDriver2Number = comparison
+		block.selectedDriver2Index = c.driverIndex(comparison)
+		block.selectedDriver3Number = NothingSelected
+	}
+}
+
+// driverIndex returns the index of the driver in the driver names list
+func (c *catching) driverIndex(driverNumber int) int32 {
+	for x := range c.driverNames {
+		if c.driverNames[x] == c.driverData[driverNumber].name {
+			return int32(x)
+		}
+	}
+	return NothingSelected
+}
+
 func (c *catching) findTeammate(currentDriver int) int {
 	if currentDriver == NothingSelected {
 		return NothingSelected
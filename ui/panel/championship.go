@@ -0,0 +1,466 @@
+// F1Gopher - Copyright (C) 2025 f1gopher
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package panel
+
+import (
+	"encoding/json"
+	"errors"
+	"f1gopher/regulations"
+	"fmt"
+	"image/color"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/AllenDang/giu"
+	"github.com/f1gopher/f1gopherlib"
+	"github.com/f1gopher/f1gopherlib/Messages"
+	"golang.org/x/image/colornames"
+)
+
+// standingsFile is the championship standings before the first saved session and the points scored in each session
+// saved since
+type standingsFile struct {
+	Season       int                   `json:"season"`
+	Round        string                `json:"round"`
+	Drivers      []driverStanding      `json:"drivers"`
+	Constructors []constructorStanding `json:"constructors"`
+	// Saving a session again replaces its points so they are only counted once
+	Sessions []sessionStandings `json:"sessions,omitempty"`
+}
+
+// sessionStandings is the points scored in a single session
+type sessionStandings struct {
+	Round        string                `json:"round"`
+	Drivers      []driverStanding      `json:"drivers"`
+	Constructors []constructorStanding `json:"constructors"`
+}
+
+type driverStanding struct {
+	Number int    `json:"number"`
+	Name   string `json:"name"`
+	Team   string `json:"team"`
+	Points int    `json:"points"`
+}
+
+type constructorStanding struct {
+	Team   string `json:"team"`
+	Points int    `json:"points"`
+}
+
+type standingsRow struct {
+	name             string
+	color            color.RGBA
+	points           int
+	sessionPoints    int
+	previousPosition int
+}
+
+type driverStandingsRow struct {
+	standingsRow
+	number int
+	team   string
+}
+
+type championship struct {
+	dataSrc f1gopherlib.F1GopherLib
+
+	season        int
+	session       Messages.SessionType
+	standingsPath string
+	status        string
+	saved         standingsFile
+	before        standingsFile
+
+	drivers map[int]Messages.DriverInfo
+	timing  map[int]Messages.Timing
+	lock    sync.Mutex
+}
+
+func CreateChampionship() Panel {
+	return &championship{}
+}
+
+func (c *championship) ProcessEventTime(data Messages.EventTime)                    {}
+func (c *championship) ProcessEvent(data Messages.Event)                            {}
+func (c *championship) ProcessRaceControlMessages(data Messages.RaceControlMessage) {}
+func (c *championship) ProcessWeather(data Messages.Weather)                        {}
+func (c *championship) ProcessRadio(data Messages.Radio)                            {}
+func (c *championship) ProcessLocation(data Messages.Location)                      {}
+func (c *championship) ProcessTelemetry(data Messages.Telemetry)                    {}
+func (c *championship) Close()                                                      {}
+
+func (c *championship) Type() Type { return Championship }
+
+func (c *championship) Init(dataSrc f1gopherlib.F1GopherLib, config PanelConfig) {
+	c.dataSrc = dataSrc
+	c.season = dataSrc.SessionStart().Year()
+	c.session = dataSrc.Session()
+	c.standingsPath = config.StandingsFile()
+
+	c.lock.Lock()
+	c.drivers = map[int]Messages.DriverInfo{}
+	c.timing = map[int]Messages.Timing{}
+	c.lock.Unlock()
+
+	c.load()
+}
+
+func (c *championship) ProcessDrivers(data Messages.Drivers) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for _, driver := range data.Drivers {
+		c.drivers[driver.Number] = driver
+	}
+}
+
+func (c *championship) ProcessTiming(data Messages.Timing) {
+	c.lock.Lock()
+	c.timing[data.Number] = data
+	c.lock.Unlock()
+}
+
+func (c *championship) round() string {
+	return fmt.Sprintf("%s %s", c.dataSrc.Name(), c.session.String())
+}
+
+// load reads the standings from before this session, if they are for a different season start from zero. Points
+// already saved for this session are left out so loading again doesn't count them twice.
+func (c *championship) load() {
+	c.saved = standingsFile{Season: c.season}
+	c.before = c.saved
+
+	data, err := os.ReadFile(c.standingsPath)
+	if errors.Is(err, os.ErrNotExist) {
+		c.status = fmt.Sprintf("No standings file, starting %d from zero", c.season)
+		return
+	} else if err != nil {
+		c.status = fmt.Sprintf("Failed to read standings: %v", err)
+		return
+	}
+
+	var standings standingsFile
+	if err = json.Unmarshal(data, &standings); err != nil {
+		c.status = fmt.Sprintf("Failed to read standings: %v", err)
+		return
+	}
+
+	if standings.Season != c.season {
+		c.status = fmt.Sprintf("Standings file is for %d, starting %d from zero", standings.Season, c.season)
+		return
+	}
+
+	c.saved = standings
+	c.before = standings.before(c.round())
+	if c.before.Round == "" {
+		c.status = "Standings before this session"
+	} else {
+		c.status = fmt.Sprintf("Standings before this session, after: %s", c.before.Round)
+	}
+}
+
+// save writes the points scored in this session, replacing any saved before, so they are loaded for the next session
+func (c *championship) save() {
+	drivers, constructors := c.project()
+
+	session := sessionStandings{Round: c.round()}
+	for _, driver := range drivers {
+		session.Drivers = append(session.Drivers, driverStanding{
+			Number: driver.number,
+			Name:   driver.name,
+			Team:   driver.team,
+			Points: driver.sessionPoints,
+		})
+	}
+	for _, constructor := range constructors {
+		session.Constructors = append(session.Constructors, constructorStanding{
+			Team:   constructor.name,
+			Points: constructor.sessionPoints,
+		})
+	}
+
+	standings := c.saved
+	standings.Sessions = make([]sessionStandings, 0, len(c.saved.Sessions)+1)
+	for _, existing := range c.saved.Sessions {
+		if existing.Round != session.Round {
+			standings.Sessions = append(standings.Sessions, existing)
+		}
+	}
+	standings.Sessions = append(standings.Sessions, session)
+
+	data, err := json.MarshalIndent(standings, "", "  ")
+	if err == nil {
+		err = os.WriteFile(c.standingsPath, data, 0644)
+	}
+	if err != nil {
+		c.status = fmt.Sprintf("Failed to save standings: %v", err)
+		return
+	}
+
+	c.saved = standings
+	c.status = fmt.Sprintf("Saved standings after: %s", session.Round)
+}
+
+// before returns the standings with the points from every saved session except round added on
+func (s standingsFile) before(round string) standingsFile {
+	result := standingsFile{Season: s.Season, Round: s.Round}
+
+	drivers := map[int]*driverStanding{}
+	driverOrder := make([]int, 0, len(s.Drivers))
+	addDriver := func(standing driverStanding) {
+		driver, exists := drivers[standing.Number]
+		if !exists {
+			driver = &driverStanding{Number: standing.Number}
+			drivers[standing.Number] = driver
+			driverOrder = append(driverOrder, standing.Number)
+		}
+		driver.Name = standing.Name
+		driver.Team = standing.Team
+		driver.Points += standing.Points
+	}
+
+	constructors := map[string]*constructorStanding{}
+	constructorOrder := make([]string, 0, len(s.Constructors))
+	addConstructor := func(standing constructorStanding) {
+		constructor, exists := constructors[standing.Team]
+		if !exists {
+			constructor = &constructorStanding{Team: standing.Team}
+			constructors[standing.Team] = constructor
+			constructorOrder = append(constructorOrder, standing.Team)
+		}
+		constructor.Points += standing.Points
+	}
+
+	for _, standing := range s.Drivers {
+		addDriver(standing)
+	}
+	for _, standing := range s.Constructors {
+		addConstructor(standing)
+	}
+	// Without constructor standings use the total of the drivers so the saved sessions are added to something
+	if len(s.Constructors) == 0 && len(s.Sessions) > 0 {
+		for _, standing := range s.Drivers {
+			if standing.Team != "" {
+				addConstructor(constructorStanding{Team: standing.Team, Points: standing.Points})
+			}
+		}
+	}
+
+	for _, session := range s.Sessions {
+		if session.Round == round {
+			continue
+		}
+
+		for _, standing := range session.Drivers {
+			addDriver(standing)
+		}
+		for _, standing := range session.Constructors {
+			addConstructor(standing)
+		}
+		result.Round = session.Round
+	}
+
+	for _, number := range driverOrder {
+		result.Drivers = append(result.Drivers, *drivers[number])
+	}
+	for _, team := range constructorOrder {
+		result.Constructors = append(result.Constructors, *constructors[team])
+	}
+	return result
+}
+
+// project returns the drivers and constructors standings if the session finished with the current running order
+func (c *championship) project() ([]*driverStandingsRow, []*standingsRow) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	drivers := map[int]*driverStandingsRow{}
+	for _, standing := range c.before.Drivers {
+		drivers[standing.Number] = &driverStandingsRow{
+			standingsRow: standingsRow{name: standing.Name, points: standing.Points, color: colornames.White},
+			number:       standing.Number,
+			team:         standing.Team,
+		}
+	}
+
+	for num, info := range c.drivers {
+		driver, exists := drivers[num]
+		if !exists {
+			driver = &driverStandingsRow{number: num}
+			drivers[num] = driver
+		}
+		driver.name = info.ShortName
+		driver.team = info.Team
+		driver.color = info.Color
+	}
+
+	// Points for where everyone is running now, stopped cars are assumed to have retired
+	for num, timing := range c.timing {
+		driver, exists := drivers[num]
+		if !exists || timing.Location == Messages.Stopped || timing.Location == Messages.OutOfRace {
+			continue
+		}
+
+		driver.sessionPoints = regulations.Points(c.season, c.session, timing.Position)
+		if timing.OverallFastestLap {
+			driver.sessionPoints += regulations.FastestLapPoints(c.season, c.session, timing.Position)
+		}
+	}
+
+	constructors := map[string]*standingsRow{}
+	for _, standing := range c.before.Constructors {
+		constructors[standing.Team] = &standingsRow{name: standing.Team, points: standing.Points, color: colornames.White}
+	}
+	for _, driver := range drivers {
+		if driver.team == "" {
+			continue
+		}
+
+		constructor, exists := constructors[driver.team]
+		if !exists {
+			constructor = &standingsRow{name: driver.team, color: colornames.White}
+			constructors[driver.team] = constructor
+		}
+
+		// Without constructor standings in the file use the total of the drivers
+		if len(c.before.Constructors) == 0 {
+			constructor.points += driver.points
+		}
+		constructor.sessionPoints += driver.sessionPoints
+		if driver.color != colornames.White && driver.color != (color.RGBA{}) {
+			constructor.color = driver.color
+		}
+	}
+
+	driverRows := make([]*driverStandingsRow, 0, len(drivers))
+	for _, driver := range drivers {
+		driverRows = append(driverRows, driver)
+	}
+	constructorRows := make([]*standingsRow, 0, len(constructors))
+	for _, constructor := range constructors {
+		constructorRows = append(constructorRows, constructor)
+	}
+
+	// Work out the positions before the session then order by the projected points
+	sort.SliceStable(driverRows, func(i, j int) bool {
+		return orderStandings(&driverRows[i].standingsRow, &driverRows[j].standingsRow, false)
+	})
+	for x := range driverRows {
+		driverRows[x].previousPosition = x + 1
+	}
+	sort.SliceStable(driverRows, func(i, j int) bool {
+		return orderStandings(&driverRows[i].standingsRow, &driverRows[j].standingsRow, true)
+	})
+
+	sort.SliceStable(constructorRows, func(i, j int) bool { return orderStandings(constructorRows[i], constructorRows[j], false) })
+	for x := range constructorRows {
+		constructorRows[x].previousPosition = x + 1
+	}
+	sort.SliceStable(constructorRows, func(i, j int) bool { return orderStandings(constructorRows[i], constructorRows[j], true) })
+
+	return driverRows, constructorRows
+}
+
+func orderStandings(a *standingsRow, b *standingsRow, includeSession bool) bool {
+	aPoints := a.points
+	bPoints := b.points
+	if includeSession {
+		aPoints += a.sessionPoints
+		bPoints += b.sessionPoints
+	}
+
+	if aPoints != bPoints {
+		return aPoints > bPoints
+	}
+	return a.name < b.name
+}
+
+func (c *championship) Draw(width int, height int) []giu.Widget {
+	if !regulations.ScoresPoints(c.season, c.session) {
+		return []giu.Widget{giu.Label(fmt.Sprintf("No championship points are awarded for %s", c.session.String()))}
+	}
+
+	drivers, constructors := c.project()
+
+	driverRows := make([]*giu.TableRowWidget, 0, len(drivers))
+	for x, driver := range drivers {
+		driverRows = append(driverRows, c.standingsTableRow(x+1, &driver.standingsRow))
+	}
+	constructorRows := make([]*giu.TableRowWidget, 0, len(constructors))
+	for x, constructor := range constructors {
+		constructorRows = append(constructorRows, c.standingsTableRow(x+1, constructor))
+	}
+
+	tableWidth := float32(width-24) / 2
+
+	return []giu.Widget{
+		giu.Row(
+			giu.Button("Reload").OnClick(c.load),
+			giu.Button("Save Projection").OnClick(c.save),
+			giu.Label(c.status),
+		),
+		giu.Row(
+			giu.Child().Size(tableWidth, float32(height-40)).Layout(
+				giu.Label("Drivers"),
+				c.standingsTable("Driver", driverRows),
+			),
+			giu.Child().Size(tableWidth, float32(height-40)).Layout(
+				giu.Label("Constructors"),
+				c.standingsTable("Team", constructorRows),
+			),
+		),
+	}
+}
+
+func (c *championship) standingsTable(nameColumn string, rows []*giu.TableRowWidget) giu.Widget {
+	return giu.Table().FastMode(true).Flags(giu.TableFlagsResizable|giu.TableFlagsSizingFixedSame|giu.TableFlagsScrollY).
+		Columns(
+			giu.TableColumn("Pos").InnerWidthOrWeight(25),
+			giu.TableColumn("+/-").InnerWidthOrWeight(30),
+			giu.TableColumn(nameColumn).InnerWidthOrWeight(120),
+			giu.TableColumn("Points").InnerWidthOrWeight(45),
+			giu.TableColumn("Session").InnerWidthOrWeight(45),
+			giu.TableColumn("Before").InnerWidthOrWeight(45),
+		).
+		Rows(rows...)
+}
+
+func (c *championship) standingsTableRow(position int, row *standingsRow) *giu.TableRowWidget {
+	change := ""
+	changeColor := colornames.White
+	if position < row.previousPosition {
+		change = fmt.Sprintf("▲%d", row.previousPosition-position)
+		changeColor = colornames.Green
+	} else if position > row.previousPosition {
+		change = fmt.Sprintf("▼%d", position-row.previousPosition)
+		changeColor = colornames.Red
+	}
+
+	sessionPoints := ""
+	if row.sessionPoints > 0 {
+		sessionPoints = fmt.Sprintf("+%d", row.sessionPoints)
+	}
+
+	return giu.TableRow(
+		giu.Label(fmt.Sprintf("%d", position)),
+		giu.Style().SetColor(giu.StyleColorText, changeColor).To(giu.Label(change)),
+		giu.Style().SetColor(giu.StyleColorText, row.color).To(giu.Label(row.name)),
+		giu.Label(fmt.Sprintf("%d", row.points+row.sessionPoints)),
+		giu.Label(sessionPoints),
+		giu.Label(fmt.Sprintf("%d", row.points)),
+	)
+}
@@ -0,0 +1,145 @@
+package panel
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/f1gopher/f1gopherlib"
+	"github.com/f1gopher/f1gopherlib/Messages"
+)
+
+type championshipSource struct {
+	f1gopherlib.F1GopherLib
+	name string
+}
+
+func (c *championshipSource) Name() string { return c.name }
+
+type projectedStanding struct {
+	name          string
+	points        int
+	sessionPoints int
+}
+
+func projectedStandings(c *championship) ([]projectedStanding, []projectedStanding) {
+	drivers, constructors := c.project()
+
+	driverStandings := make([]projectedStanding, 0, len(drivers))
+	for _, driver := range drivers {
+		driverStandings = append(driverStandings, projectedStanding{driver.name, driver.points, driver.sessionPoints})
+	}
+	constructorStandings := make([]projectedStanding, 0, len(constructors))
+	for _, constructor := range constructors {
+		constructorStandings = append(constructorStandings, projectedStanding{constructor.name, constructor.points, constructor.sessionPoints})
+	}
+	return driverStandings, constructorStandings
+}
+
+func equalStandings(a []projectedStanding, b []projectedStanding) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for x := range a {
+		if a[x] != b[x] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestChampionshipReloadIsIdempotent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "standings.json")
+	err := os.WriteFile(path, []byte(`{
+  "season": 2025,
+  "round": "Qatar Grand Prix Race",
+  "drivers": [
+    {"number": 4, "name": "NOR", "team": "McLaren", "points": 408},
+    {"number": 1, "name": "VER", "team": "Red Bull Racing", "points": 396}
+  ],
+  "constructors": [{"team": "McLaren", "points": 800}, {"team": "Red Bull Racing", "points": 426}]
+}`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := &championship{
+		dataSrc:       &championshipSource{name: "Abu Dhabi Grand Prix"},
+		season:        2025,
+		session:       Messages.RaceSession,
+		standingsPath: path,
+		drivers: map[int]Messages.DriverInfo{
+			1: {Number: 1, ShortName: "VER", Team: "Red Bull Racing"},
+			4: {Number: 4, ShortName: "NOR", Team: "McLaren"},
+		},
+		timing: map[int]Messages.Timing{
+			1: {Number: 1, Position: 1, Location: Messages.OnTrack},
+			4: {Number: 4, Position: 3, Location: Messages.OnTrack},
+		},
+	}
+	c.load()
+
+	drivers, constructors := projectedStandings(c)
+	expectedDrivers := []projectedStanding{{"NOR", 408, 15}, {"VER", 396, 25}}
+	if !equalStandings(drivers, expectedDrivers) {
+		t.Fatalf("expected drivers %v, got %v", expectedDrivers, drivers)
+	}
+
+	c.save()
+	for x := 0; x < 2; x++ {
+		c.load()
+
+		reloadedDrivers, reloadedConstructors := projectedStandings(c)
+		if !equalStandings(reloadedDrivers, drivers) {
+			t.Errorf("reload %d: expected drivers %v, got %v", x+1, drivers, reloadedDrivers)
+		}
+		if !equalStandings(reloadedConstructors, constructors) {
+			t.Errorf("reload %d: expected constructors %v, got %v", x+1, constructors, reloadedConstructors)
+		}
+	}
+
+	// Saving the session again replaces its points
+	c.save()
+	c.load()
+	if len(c.saved.Sessions) != 1 {
+		t.Errorf("expected 1 saved session, got %d", len(c.saved.Sessions))
+	}
+
+	// The next session starts from the saved points
+	c.dataSrc = &championshipSource{name: "Las Vegas Grand Prix"}
+	c.timing = map[int]Messages.Timing{}
+	c.load()
+
+	drivers, constructors = projectedStandings(c)
+	expectedDrivers = []projectedStanding{{"NOR", 423, 0}, {"VER", 421, 0}}
+	if !equalStandings(drivers, expectedDrivers) {
+		t.Errorf("expected drivers %v, got %v", expectedDrivers, drivers)
+	}
+	expectedConstructors := []projectedStanding{{"McLaren", 815, 0}, {"Red Bull Racing", 451, 0}}
+	if !equalStandings(constructors, expectedConstructors) {
+		t.Errorf("expected constructors %v, got %v", expectedConstructors, constructors)
+	}
+}
+
+func TestChampionshipRetiredCarsScoreNothing(t *testing.T) {
+	c := &championship{
+		season:  2025,
+		session: Messages.RaceSession,
+		drivers: map[int]Messages.DriverInfo{
+			1:  {Number: 1, ShortName: "VER", Team: "Red Bull Racing"},
+			4:  {Number: 4, ShortName: "NOR", Team: "McLaren"},
+			16: {Number: 16, ShortName: "LEC", Team: "Ferrari"},
+		},
+		timing: map[int]Messages.Timing{
+			1:  {Number: 1, Position: 1, Location: Messages.OnTrack},
+			4:  {Number: 4, Position: 2, Location: Messages.Stopped},
+			16: {Number: 16, Position: 3, Location: Messages.OutOfRace},
+		},
+	}
+
+	drivers, _ := projectedStandings(c)
+	expected := []projectedStanding{{"VER", 0, 25}, {"LEC", 0, 0}, {"NOR", 0, 0}}
+	if !equalStandings(drivers, expected) {
+		t.Errorf("expected drivers %v, got %v", expected, drivers)
+	}
+}
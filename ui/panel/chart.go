@@ -0,0 +1,715 @@
+// F1Gopher - Copyright (C) 2025 f1gopher
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package panel
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"math"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AllenDang/cimgui-go/imgui"
+	"github.com/AllenDang/giu"
+	"github.com/ungerik/go-cairo"
+)
+
+const (
+	chartMargin       = 10.0
+	chartYLabelWidth  = 40.0
+	chartXLabelHeight = 15.0
+	chartMinTickGap   = 30.0
+	chartMinZoomRange = 0.001
+	// Most renders of different sizes kept for a chart
+	chartMaxCachedRenders = 8
+)
+
+// ChartRenderer is implemented by the panels that draw a chart so the chart can be rendered outside of the window,
+// for example by the web timing server
+type ChartRenderer interface {
+	// RenderChart returns the chart as currently displayed drawn at the size as a PNG or SVG image
+	RenderChart(width int, height int, svg bool) ([]byte, error)
+}
+
+type chartRenderKey struct {
+	width  int
+	height int
+	svg    bool
+}
+
+type chartAxis struct {
+	name  string
+	color color.RGBA
+
+	min float64
+	max float64
+	// Gap between major ticks, the gap is increased if the ticks would be too close together. If zero then a
+	// gap is picked from the range of the axis
+	majorTick float64
+	// Y axis only, draw the min value at the top instead of the bottom
+	inverted bool
+
+	format     func(float64) string
+	labelColor func(float64) (color.RGBA, bool)
+
+	// Range currently displayed after zooming and panning
+	viewMin float64
+	viewMax float64
+
+	// Pixel positions of the axis calculated during layout. For the X axis start is left and end is right, for
+	// Y axes start is the top and end is the bottom
+	start float64
+	end   float64
+}
+
+type chartLegendEntry struct {
+	name  string
+	color color.RGBA
+}
+
+// chart builds on plot to draw the axes, gridlines and legend for a chart and handles zooming, panning,
+// hovering and exporting the chart so each panel only has to draw its data
+type chart struct {
+	plot *plot
+	name string
+
+	x chartAxis
+	// More than one Y axis stacks the axes vertically, each with their own range
+	y []*chartAxis
+
+	legend []chartLegendEntry
+	// Displayed instead of the chart when set, for example when there is no data yet
+	message     string
+	showGrid    bool
+	rightMargin float64
+
+	zoomed   bool
+	dragging bool
+
+	width  float64
+	height float64
+
+	// Size to draw at instead of the displayed size, set while rendering outside of the window
+	renderWidth  int
+	renderHeight int
+	// Held while drawing because renders for the web timing server happen outside of the UI thread
+	renderLock    sync.Mutex
+	renders       map[chartRenderKey][]byte
+	renderVersion uint64
+
+	// Called before the background is drawn so the axis ranges, legend and message can be updated
+	prepare func()
+	// Draw behind the data but above the gridlines, clipped to the plot area
+	drawBackground func(dc *cairo.Surface)
+	// Draw the data, clipped to the plot area
+	drawForeground func(dc *cairo.Surface)
+	// Draw on top of everything without clipping
+	drawOverlay func(dc *cairo.Surface)
+	// Returns the tooltip for the mouse position, in pixels, or an empty string for no tooltip
+	hover func(xPos float64, yPos float64) string
+	// Called when the chart is clicked without dragging with the mouse position in pixels
+	click func(xPos float64, yPos float64)
+
+	exportStatus string
+}
+
+func createChart(name string) *chart {
+	c := &chart{
+		name:     name,
+		showGrid: true,
+		x:        chartAxis{format: func(v float64) string { return fmt.Sprintf("%.0f", v) }},
+		y:        []*chartAxis{{format: func(v float64) string { return fmt.Sprintf("%.0f", v) }}},
+	}
+	c.plot = createPlot(c.renderBackground, c.renderForeground)
+	return c
+}
+
+func (c *chart) reset() {
+	c.zoomed = false
+	c.dragging = false
+	c.exportStatus = ""
+	c.plot.reset()
+}
+
+func (c *chart) refreshBackground() {
+	c.plot.refreshBackground()
+}
+
+func (c *chart) refreshForeground() {
+	c.plot.refreshForeground()
+}
+
+// setXRange sets the full range of the X axis, if the user hasn't zoomed then this is the range displayed
+func (c *chart) setXRange(min float64, max float64) {
+	c.x.min = min
+	c.x.max = max
+	if !c.zoomed {
+		c.x.viewMin = min
+		c.x.viewMax = max
+	}
+}
+
+// setYRange sets the full range of a Y axis, if the user hasn't zoomed then this is the range displayed
+func (c *chart) setYRange(axis int, min float64, max float64) {
+	c.y[axis].min = min
+	c.y[axis].max = max
+	if !c.zoomed || len(c.y) > 1 {
+		c.y[axis].viewMin = min
+		c.y[axis].viewMax = max
+	}
+}
+
+func (c *chart) resetZoom() {
+	c.renderLock.Lock()
+	defer c.renderLock.Unlock()
+
+	c.resetView()
+}
+
+// resetView must be called with the render lock held
+func (c *chart) resetView() {
+	c.zoomed = false
+	c.x.viewMin = c.x.min
+	c.x.viewMax = c.x.max
+	for _, axis := range c.y {
+		axis.viewMin = axis.min
+		axis.viewMax = axis.max
+	}
+	c.plot.refreshBackground()
+}
+
+// xPos converts a value on the X axis to a pixel position
+func (c *chart) xPos(value float64) float64 {
+	return c.x.start + (value-c.x.viewMin)/(c.x.viewMax-c.x.viewMin)*(c.x.end-c.x.start)
+}
+
+// yPos converts a value on a Y axis to a pixel position
+func (c *chart) yPos(axis int, value float64) float64 {
+	a := c.y[axis]
+	fraction := (value - a.viewMin) / (a.viewMax - a.viewMin)
+	if a.inverted {
+		return a.start + fraction*(a.end-a.start)
+	}
+	return a.end - fraction*(a.end-a.start)
+}
+
+// xValue converts a pixel position to a value on the X axis
+func (c *chart) xValue(xPos float64) float64 {
+	return c.x.viewMin + (xPos-c.x.start)/(c.x.end-c.x.start)*(c.x.viewMax-c.x.viewMin)
+}
+
+// yValue converts a pixel position to a value on a Y axis
+func (c *chart) yValue(axis int, yPos float64) float64 {
+	a := c.y[axis]
+	fraction := (a.end - yPos) / (a.end - a.start)
+	if a.inverted {
+		fraction = 1 - fraction
+	}
+	return a.viewMin + fraction*(a.viewMax-a.viewMin)
+}
+
+func (c *chart) inPlotArea(xPos float64, yPos float64) bool {
+	return c.message == "" &&
+		xPos >= c.x.start && xPos <= c.x.end &&
+		len(c.y) > 0 && yPos >= c.y[0].start && yPos <= c.y[len(c.y)-1].end
+}
+
+// toolbar returns the widgets for the chart options to display alongside the panel options
+func (c *chart) toolbar() []giu.Widget {
+	// The grid is changed under the render lock because the web server can be rendering the chart
+	c.renderLock.Lock()
+	showGrid := c.showGrid
+	c.renderLock.Unlock()
+
+	widgets := []giu.Widget{
+		giu.Checkbox("Grid", &showGrid).OnChange(func() {
+			c.renderLock.Lock()
+			c.showGrid = showGrid
+			c.plot.refreshBackground()
+			c.renderLock.Unlock()
+		}),
+		giu.Button("PNG").OnClick(c.exportPNG),
+		giu.Button("SVG").OnClick(c.exportSVG),
+	}
+
+	if c.zoomed {
+		widgets = append(widgets, giu.Button("Reset Zoom").OnClick(c.resetZoom))
+	}
+
+	if c.exportStatus != "" {
+		widgets = append(widgets, giu.Label(c.exportStatus))
+	}
+
+	return widgets
+}
+
+func (c *chart) draw(width int, height int) []giu.Widget {
+	c.renderLock.Lock()
+	image := c.plot.draw(width, height)
+	c.renderLock.Unlock()
+
+	return []giu.Widget{
+		image,
+		giu.Custom(c.handleMouse),
+	}
+}
+
+func (c *chart) handleMouse() {
+	c.renderLock.Lock()
+	defer c.renderLock.Unlock()
+
+	if c.plot.widget == nil {
+		return
+	}
+
+	// Place an invisible button over the chart so the mouse is captured by the chart instead of dragging the window
+	origin := imgui.ItemRectMin()
+	imgui.SetCursorScreenPos(origin)
+	imgui.InvisibleButton("##"+c.name, imgui.Vec2{X: c.plot.plotTextureWidth, Y: c.plot.plotTextureHeight})
+
+	mouse := imgui.MousePos()
+	xPos := float64(mouse.X - origin.X)
+	yPos := float64(mouse.Y - origin.Y)
+
+	if imgui.IsItemActive() && imgui.IsMouseDraggingV(imgui.MouseButtonLeft, 2) {
+		delta := imgui.MouseDragDeltaV(imgui.MouseButtonLeft, 2)
+		imgui.ResetMouseDragDeltaV(imgui.MouseButtonLeft)
+		c.dragging = true
+		c.pan(float64(delta.X), float64(delta.Y))
+		return
+	}
+
+	if imgui.IsItemDeactivated() {
+		if !c.dragging && c.click != nil && c.inPlotArea(xPos, yPos) {
+			c.click(xPos, yPos)
+		}
+		c.dragging = false
+	}
+
+	if !imgui.IsItemHovered() || !c.inPlotArea(xPos, yPos) {
+		return
+	}
+
+	if imgui.IsMouseDoubleClicked(imgui.MouseButtonLeft) {
+		c.resetView()
+		return
+	}
+
+	io := imgui.CurrentIO()
+	if wheel := io.MouseWheel(); wheel != 0 {
+		factor := 0.8
+		if wheel < 0 {
+			factor = 1.25
+		}
+		// Holding control zooms the Y axis instead of the X axis
+		c.zoom(xPos, yPos, factor, io.KeyCtrl())
+	}
+
+	if c.hover != nil {
+		if tooltip := c.hover(xPos, yPos); tooltip != "" {
+			imgui.SetTooltip(strings.ReplaceAll(tooltip, "%", "%%"))
+		}
+	}
+}
+
+// zoom scales the displayed range around the mouse position, a factor less than one zooms in
+func (c *chart) zoom(xPos float64, yPos float64, factor float64, yAxis bool) {
+	if yAxis {
+		// Stacked axes only zoom on the X axis
+		if len(c.y) != 1 {
+			return
+		}
+
+		centre := c.yValue(0, yPos)
+		zoomAxis(c.y[0], centre, factor)
+	} else {
+		zoomAxis(&c.x, c.xValue(xPos), factor)
+	}
+
+	c.zoomed = true
+	c.plot.refreshBackground()
+}
+
+func zoomAxis(axis *chartAxis, centre float64, factor float64) {
+	if (axis.viewMax-axis.viewMin)*factor < chartMinZoomRange {
+		return
+	}
+
+	axis.viewMin = centre - (centre-axis.viewMin)*factor
+	axis.viewMax = centre + (axis.viewMax-centre)*factor
+}
+
+// pan moves the displayed range by a number of pixels
+func (c *chart) pan(xDelta float64, yDelta float64) {
+	if c.x.end <= c.x.start {
+		return
+	}
+
+	xShift := xDelta / (c.x.end - c.x.start) * (c.x.viewMax - c.x.viewMin)
+	c.x.viewMin -= xShift
+	c.x.viewMax -= xShift
+
+	// Stacked axes only pan on the X axis
+	if len(c.y) == 1 {
+		axis := c.y[0]
+		yShift := yDelta / (axis.end - axis.start) * (axis.viewMax - axis.viewMin)
+		if axis.inverted {
+			yShift = -yShift
+		}
+		axis.viewMin += yShift
+		axis.viewMax += yShift
+	}
+
+	c.zoomed = true
+	c.plot.refreshBackground()
+}
+
+func (c *chart) exportFile(extension string) string {
+	return fmt.Sprintf("%s %s.%s", c.name, time.Now().Format("2006-01-02 150405"), extension)
+}
+
+// exportPNG saves the chart as currently displayed to a PNG file in the working directory
+func (c *chart) exportPNG() {
+	c.renderLock.Lock()
+	defer c.renderLock.Unlock()
+
+	if c.plot.foregroundGc == nil {
+		c.exportStatus = "Nothing to export"
+		return
+	}
+
+	file := c.exportFile("png")
+	if status := c.plot.foregroundGc.WriteToPNG(file); status != cairo.STATUS_SUCCESS {
+		c.exportStatus = fmt.Sprintf("Export failed: %s", status.String())
+		return
+	}
+
+	c.exportStatus = fmt.Sprintf("Saved %s", file)
+}
+
+// exportSVG redraws the chart to a SVG file in the working directory
+func (c *chart) exportSVG() {
+	c.renderLock.Lock()
+	defer c.renderLock.Unlock()
+
+	if c.plot.currentWidth == 0 || c.plot.currentHeight == 0 {
+		c.exportStatus = "Nothing to export"
+		return
+	}
+
+	file := c.exportFile("svg")
+	svg := cairo.NewSVGSurface(file, float64(c.plot.currentWidth), float64(c.plot.currentHeight), cairo.SVG_VERSION_1_2)
+	if status := svg.GetStatus(); status != cairo.STATUS_SUCCESS {
+		svg.Destroy()
+		c.exportStatus = fmt.Sprintf("Export failed: %s", status.String())
+		return
+	}
+
+	svg.SelectFontFace("sans-serif", cairo.FONT_SLANT_NORMAL, cairo.FONT_WEIGHT_BOLD)
+	svg.SetFontSize(10.0)
+	c.renderBackground(svg)
+	c.renderForeground(svg)
+	svg.Finish()
+	svg.Destroy()
+
+	c.exportStatus = fmt.Sprintf("Saved %s", file)
+}
+
+// render draws the chart at the size without changing what is displayed. Renders are cached until the chart is
+// next refreshed
+func (c *chart) render(width int, height int, svg bool) ([]byte, error) {
+	c.renderLock.Lock()
+	defer c.renderLock.Unlock()
+
+	key := chartRenderKey{width: width, height: height, svg: svg}
+	version := c.plot.version.Load()
+	if c.renders == nil || c.renderVersion != version || len(c.renders) >= chartMaxCachedRenders {
+		c.renders = map[chartRenderKey][]byte{}
+		c.renderVersion = version
+	}
+	if data, exists := c.renders[key]; exists {
+		return data, nil
+	}
+
+	c.renderWidth = width
+	c.renderHeight = height
+	var data []byte
+	var err error
+	if svg {
+		data, err = c.renderSVG(width, height)
+	} else {
+		data, err = c.renderPNG(width, height)
+	}
+	c.renderWidth = 0
+	c.renderHeight = 0
+
+	// Put the axes back where they are displayed so the mouse handling still works
+	c.layout()
+
+	if err != nil {
+		return nil, err
+	}
+	c.renders[key] = data
+	return data, nil
+}
+
+func (c *chart) renderPNG(width int, height int) ([]byte, error) {
+	surface := cairo.NewSurface(cairo.FORMAT_ARGB32, width, height)
+	defer surface.Destroy()
+
+	surface.SelectFontFace("sans-serif", cairo.FONT_SLANT_NORMAL, cairo.FONT_WEIGHT_BOLD)
+	surface.SetFontSize(10.0)
+	c.renderBackground(surface)
+	c.renderForeground(surface)
+	surface.Flush()
+
+	data, status := surface.WriteToPNGStream()
+	if status != cairo.STATUS_SUCCESS {
+		return nil, errors.New(status.String())
+	}
+	return data, nil
+}
+
+// renderSVG draws to a temporary file because cairo can only write SVG surfaces to a file
+func (c *chart) renderSVG(width int, height int) ([]byte, error) {
+	file, err := os.CreateTemp("", "f1gopher-*.svg")
+	if err != nil {
+		return nil, err
+	}
+	file.Close()
+	defer os.Remove(file.Name())
+
+	svg := cairo.NewSVGSurface(file.Name(), float64(width), float64(height), cairo.SVG_VERSION_1_2)
+	if status := svg.GetStatus(); status != cairo.STATUS_SUCCESS {
+		svg.Destroy()
+		return nil, errors.New(status.String())
+	}
+
+	svg.SelectFontFace("sans-serif", cairo.FONT_SLANT_NORMAL, cairo.FONT_WEIGHT_BOLD)
+	svg.SetFontSize(10.0)
+	c.renderBackground(svg)
+	c.renderForeground(svg)
+	svg.Finish()
+	svg.Destroy()
+
+	return os.ReadFile(file.Name())
+}
+
+// layout works out where the axes are for the current size of the chart
+func (c *chart) layout() {
+	c.width = float64(c.plot.currentWidth)
+	c.height = float64(c.plot.currentHeight)
+	if c.renderWidth != 0 && c.renderHeight != 0 {
+		c.width = float64(c.renderWidth)
+		c.height = float64(c.renderHeight)
+	}
+
+	c.x.start = chartMargin + chartYLabelWidth
+	c.x.end = c.width - chartMargin - c.rightMargin
+
+	top := chartMargin
+	bottom := c.height - chartMargin - chartXLabelHeight
+	const stackedGap = 7.0
+	axisHeight := (bottom - top - stackedGap*float64(len(c.y)-1)) / float64(len(c.y))
+
+	for _, axis := range c.y {
+		axis.start = top
+		axis.end = top + axisHeight
+		top = axis.end + stackedGap
+	}
+}
+
+// ticks returns the values for the major ticks of an axis
+func (c *chart) ticks(axis *chartAxis) []float64 {
+	valueRange := axis.viewMax - axis.viewMin
+	pixels := math.Abs(axis.end - axis.start)
+	if valueRange <= 0 || pixels <= 0 {
+		return nil
+	}
+
+	step := axis.majorTick
+	if step <= 0 {
+		step = math.Pow(10, math.Floor(math.Log10(valueRange/10)))
+	}
+
+	// Increase the gap using 1, 2, 5 steps until the labels have enough room
+	multipliers := []float64{2, 2.5, 2}
+	for x := 0; step/valueRange*pixels < chartMinTickGap; x++ {
+		step *= multipliers[x%len(multipliers)]
+	}
+
+	result := []float64{}
+	for value := math.Ceil(axis.viewMin/step) * step; value <= axis.viewMax; value += step {
+		result = append(result, value)
+	}
+	return result
+}
+
+func (c *chart) renderBackground(dc *cairo.Surface) {
+	if c.prepare != nil {
+		c.prepare()
+	}
+
+	c.layout()
+
+	// Black background
+	dc.SetSourceRGB(0.0, 0.0, 0.0)
+	dc.Rectangle(0, 0, c.width, c.height)
+	dc.Fill()
+
+	if c.message != "" {
+		dc.SetSourceRGB(1.0, 1.0, 1.0)
+		dc.MoveTo((c.width/2)-50, c.height/2)
+		dc.ShowText(c.message)
+		dc.Stroke()
+		return
+	}
+
+	xTicks := c.ticks(&c.x)
+
+	if c.showGrid {
+		dc.SetLineWidth(1.0)
+		dc.SetSourceRGB(0.25, 0.25, 0.25)
+		for _, value := range xTicks {
+			dc.MoveTo(c.xPos(value), c.y[0].start)
+			dc.LineTo(c.xPos(value), c.y[len(c.y)-1].end)
+		}
+		for axisIndex, axis := range c.y {
+			for _, value := range c.ticks(axis) {
+				dc.MoveTo(c.x.start, c.yPos(axisIndex, value))
+				dc.LineTo(c.x.end, c.yPos(axisIndex, value))
+			}
+		}
+		dc.Stroke()
+	}
+
+	if c.drawBackground != nil {
+		c.clip(dc)
+		c.drawBackground(dc)
+		dc.ResetClip()
+	}
+
+	dc.SetLineWidth(2.0)
+
+	// X axis with ticks and labels below the bottom axis
+	bottom := c.y[len(c.y)-1].end
+	dc.SetSourceRGB(1.0, 1.0, 1.0)
+	dc.MoveTo(c.x.start, bottom)
+	dc.LineTo(c.x.end, bottom)
+	for _, value := range xTicks {
+		xPos := c.xPos(value)
+		label := c.x.format(value)
+		dc.MoveTo(xPos, bottom)
+		dc.LineTo(xPos, bottom+4)
+		dc.MoveTo(xPos-dc.TextExtents(label).Width/2, bottom+14)
+		dc.ShowText(label)
+	}
+	dc.Stroke()
+
+	// Y axes with ticks and right aligned labels
+	for axisIndex, axis := range c.y {
+		axisColor := axis.color
+		if axisColor.A == 0 {
+			axisColor = color.RGBA{R: 255, G: 255, B: 255, A: 255}
+		}
+		dc.SetSourceRGBA(floatColor(axisColor))
+		dc.MoveTo(c.x.start, axis.start)
+		dc.LineTo(c.x.start, axis.end)
+		dc.Stroke()
+
+		for _, value := range c.ticks(axis) {
+			yPos := c.yPos(axisIndex, value)
+			label := axis.format(value)
+
+			dc.SetSourceRGBA(floatColor(axisColor))
+			dc.MoveTo(c.x.start-6, yPos)
+			dc.LineTo(c.x.start, yPos)
+			dc.Stroke()
+
+			if axis.labelColor != nil {
+				if labelColor, exists := axis.labelColor(value); exists {
+					dc.SetSourceRGBA(floatColor(labelColor))
+				}
+			}
+			dc.MoveTo(c.x.start-8-dc.TextExtents(label).Width, yPos+3)
+			dc.ShowText(label)
+			dc.Stroke()
+		}
+	}
+}
+
+func (c *chart) renderForeground(dc *cairo.Surface) {
+	if c.message != "" {
+		return
+	}
+
+	dc.SetLineWidth(2.0)
+	c.clip(dc)
+	c.drawForeground(dc)
+	dc.ResetClip()
+	dc.SetLineWidth(2.0)
+
+	c.drawLegend(dc)
+
+	if c.drawOverlay != nil {
+		c.drawOverlay(dc)
+	}
+}
+
+func (c *chart) clip(dc *cairo.Surface) {
+	dc.NewPath()
+	dc.Rectangle(c.x.start, c.y[0].start, c.x.end-c.x.start, c.y[len(c.y)-1].end-c.y[0].start)
+	dc.Clip()
+	dc.NewPath()
+}
+
+// drawLegend draws the legend entries in the top right of the plot area
+func (c *chart) drawLegend(dc *cairo.Surface) {
+	if len(c.legend) == 0 {
+		return
+	}
+
+	const lineHeight = 12.0
+	const swatchSize = 8.0
+
+	legendWidth := 0.0
+	for _, entry := range c.legend {
+		legendWidth = math.Max(legendWidth, dc.TextExtents(entry.name).Width)
+	}
+	legendWidth += swatchSize + 12
+	legendHeight := float64(len(c.legend))*lineHeight + 6
+
+	left := c.x.end - legendWidth - 5
+	top := c.y[0].start + 5
+
+	dc.SetSourceRGBA(0.0, 0.0, 0.0, 0.7)
+	dc.Rectangle(left, top, legendWidth, legendHeight)
+	dc.Fill()
+
+	yPos := top + 3
+	for _, entry := range c.legend {
+		dc.SetSourceRGBA(floatColor(entry.color))
+		dc.Rectangle(left+4, yPos+2, swatchSize, swatchSize)
+		dc.Fill()
+		dc.MoveTo(left+swatchSize+8, yPos+lineHeight-2)
+		dc.ShowText(entry.name)
+		dc.Stroke()
+		yPos += lineHeight
+	}
+}
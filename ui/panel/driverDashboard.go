@@ -0,0 +1,598 @@
+// F1Gopher - Copyright (C) 2025 f1gopher
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package panel
+
+import (
+	"f1gopher/raceControl"
+	"fmt"
+	"image/color"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/AllenDang/giu"
+	"github.com/f1gopher/f1gopherlib"
+	"github.com/f1gopher/f1gopherlib/Messages"
+	"github.com/ungerik/go-cairo"
+	"golang.org/x/image/colornames"
+)
+
+type dashboardLap struct {
+	lap      int
+	time     time.Duration
+	sector1  time.Duration
+	sector2  time.Duration
+	sector3  time.Duration
+	tire     Messages.TireType
+	position int
+	pitLap   bool
+}
+
+type dashboardStint struct {
+	tire     Messages.TireType
+	startLap int
+	laps     int
+}
+
+type dashboardGap struct {
+	lap    int
+	ahead  time.Duration
+	behind time.Duration
+}
+
+type dashboardDriver struct {
+	info   Messages.DriverInfo
+	timing Messages.Timing
+
+	laps   []dashboardLap
+	stints []dashboardStint
+	gaps   []dashboardGap
+
+	lastLap       int
+	pitstops      int
+	currentPitLap bool
+}
+
+type driverDashboard struct {
+	dataSrc f1gopherlib.F1GopherLib
+	sources *TelemetrySources
+	radio   RadioControls
+
+	drivers  map[int]*dashboardDriver
+	dataLock sync.Mutex
+
+	driverNames    []string
+	selectedDriver int32
+	// Only changed on the UI thread but read by the data processing, changed with the data lock held
+	selectedDriverNumber int
+	focus                *DriverFocus
+	pinned               bool
+
+	rcMessages []raceControl.Message
+
+	speed         *circularBuffer[float32]
+	throttle      *circularBuffer[float32]
+	brake         *circularBuffer[float32]
+	telemetryTime *circularBuffer[time.Time]
+
+	gapChart       *chart
+	telemetryChart *chart
+	trackMap       *trackMap
+}
+
+func CreateDriverDashboard(trackMaps *trackMapStore, focus *DriverFocus, sources *TelemetrySources, radio RadioControls) Panel {
+	const bufferSize = 150
+
+	panel := &driverDashboard{
+		drivers:       map[int]*dashboardDriver{},
+		focus:         focus,
+		sources:       sources,
+		radio:         radio,
+		speed:         createCircularBuffer[float32](bufferSize),
+		throttle:      createCircularBuffer[float32](bufferSize),
+		brake:         createCircularBuffer[float32](bufferSize),
+		telemetryTime: createCircularBuffer[time.Time](bufferSize),
+	}
+
+	panel.trackMap = createDriverTrackMap(trackMaps, focus, panel.selectedNumber)
+
+	panel.gapChart = createChart("Driver Gaps")
+	panel.gapChart.prepare = panel.prepareGapChart
+	panel.gapChart.drawForeground = panel.drawGaps
+	panel.gapChart.x.majorTick = 1.0
+	panel.gapChart.y[0].majorTick = 1.0
+	panel.gapChart.legend = []chartLegendEntry{
+		{name: "Car Ahead", color: colornames.Green},
+		{name: "Car Behind", color: colornames.Red},
+	}
+
+	panel.telemetryChart = createChart("Driver Telemetry")
+	panel.telemetryChart.prepare = panel.prepareTelemetryChart
+	panel.telemetryChart.drawForeground = panel.drawTelemetry
+	panel.telemetryChart.x.majorTick = 10.0
+	panel.telemetryChart.x.format = func(v float64) string { return fmt.Sprintf("%.0fs", v) }
+	panel.telemetryChart.y = []*chartAxis{
+		{name: "Speed", color: colornames.Green, majorTick: 100, format: func(v float64) string { return fmt.Sprintf("%.0f", v) }},
+		{name: "Throttle", color: colornames.Dodgerblue, majorTick: 50, format: func(v float64) string { return fmt.Sprintf("%.0f", v) }},
+		{name: "Brake", color: colornames.Red, majorTick: 50, format: func(v float64) string { return fmt.Sprintf("%.0f", v) }},
+	}
+
+	return panel
+}
+
+func (d *driverDashboard) ProcessEventTime(data Messages.EventTime) {}
+func (d *driverDashboard) ProcessWeather(data Messages.Weather)     {}
+func (d *driverDashboard) ProcessRadio(data Messages.Radio)         {}
+func (d *driverDashboard) Close()                                   {}
+
+func (d *driverDashboard) Type() Type { return DriverDashboard }
+
+func (d *driverDashboard) Init(dataSrc f1gopherlib.F1GopherLib, config PanelConfig) {
+	d.dataSrc = dataSrc
+
+	// Clear previous session data
+	d.dataLock.Lock()
+	d.drivers = map[int]*dashboardDriver{}
+	d.rcMessages = []raceControl.Message{}
+	d.selectedDriverNumber = NoDriver
+	d.resetTelemetry()
+	d.dataLock.Unlock()
+
+	d.driverNames = nil
+	d.selectedDriver = NothingSelected
+	d.pinned = false
+
+	d.trackMap.Init(dataSrc, config)
+	d.gapChart.reset()
+	d.telemetryChart.reset()
+}
+
+// resetTelemetry must be called with the data lock held
+func (d *driverDashboard) resetTelemetry() {
+	d.speed.reset()
+	d.throttle.reset()
+	d.brake.reset()
+	d.telemetryTime.reset()
+}
+
+func (d *driverDashboard) ProcessDrivers(data Messages.Drivers) {
+	d.dataLock.Lock()
+	for x := range data.Drivers {
+		d.drivers[data.Drivers[x].Number] = &dashboardDriver{info: data.Drivers[x]}
+		d.driverNames = append(d.driverNames, data.Drivers[x].ShortName)
+	}
+	d.dataLock.Unlock()
+
+	sort.Strings(d.driverNames)
+
+	d.trackMap.ProcessDrivers(data)
+}
+
+func (d *driverDashboard) ProcessEvent(data Messages.Event) {
+	d.trackMap.ProcessEvent(data)
+}
+
+func (d *driverDashboard) ProcessLocation(data Messages.Location) {
+	d.trackMap.ProcessLocation(data)
+}
+
+func (d *driverDashboard) ProcessRaceControlMessages(data Messages.RaceControlMessage) {
+	d.dataLock.Lock()
+	d.rcMessages = append(d.rcMessages, raceControl.Parse(data))
+	d.dataLock.Unlock()
+}
+
+func (d *driverDashboard) ProcessTelemetry(data Messages.Telemetry) {
+	d.dataLock.Lock()
+	defer d.dataLock.Unlock()
+
+	if data.DriverNumber != d.selectedDriverNumber {
+		return
+	}
+
+	d.speed.add(data.Speed)
+	d.throttle.add(data.Throttle)
+	d.brake.add(data.Brake)
+	d.telemetryTime.add(data.Timestamp)
+	d.telemetryChart.refreshBackground()
+}
+
+func (d *driverDashboard) ProcessTiming(data Messages.Timing) {
+	d.trackMap.ProcessTiming(data)
+
+	d.dataLock.Lock()
+	defer d.dataLock.Unlock()
+
+	driver, exists := d.drivers[data.Number]
+	if !exists {
+		return
+	}
+	driver.timing = data
+
+	if data.Location == Messages.Pitlane || data.Location == Messages.PitOut {
+		driver.currentPitLap = true
+	}
+
+	// A new stint starts after a pitstop or if the tire changes without a pitstop, for example under a red flag
+	if len(driver.stints) == 0 || data.Pitstops != driver.pitstops || data.Tire != driver.stints[len(driver.stints)-1].tire {
+		driver.pitstops = data.Pitstops
+		driver.stints = append(driver.stints, dashboardStint{tire: data.Tire, startLap: max(data.Lap, 1)})
+	}
+	driver.stints[len(driver.stints)-1].laps = data.LapsOnTire
+
+	// We don't get a lap time for the first lap so the last lap is always the one before the current lap
+	completedLap := data.Lap - 1
+	if data.LastLap == 0 || completedLap <= driver.lastLap {
+		return
+	}
+
+	driver.laps = append(driver.laps, dashboardLap{
+		lap:      completedLap,
+		time:     data.LastLap,
+		sector1:  data.Sector1,
+		sector2:  data.Sector2,
+		sector3:  data.Sector3,
+		tire:     data.Tire,
+		position: data.Position,
+		pitLap:   driver.currentPitLap,
+	})
+	driver.lastLap = completedLap
+	driver.currentPitLap = false
+
+	// The gap behind is the interval of the car in the position behind
+	gap := dashboardGap{lap: completedLap, ahead: data.TimeDiffToPositionAhead}
+	for _, other := range d.drivers {
+		if other.timing.Position == data.Position+1 {
+			gap.behind = other.timing.TimeDiffToPositionAhead
+			break
+		}
+	}
+	driver.gaps = append(driver.gaps, gap)
+
+	if data.Number == d.selectedDriverNumber {
+		d.gapChart.refreshBackground()
+	}
+}
+
+func (d *driverDashboard) selectDriver(driverNumber int) {
+	d.dataLock.Lock()
+	driver, exists := d.drivers[driverNumber]
+	if !exists {
+		d.dataLock.Unlock()
+		return
+	}
+	d.selectedDriverNumber = driverNumber
+	d.resetTelemetry()
+	d.dataLock.Unlock()
+
+	for x := range d.driverNames {
+		if d.driverNames[x] == driver.info.ShortName {
+			d.selectedDriver = int32(x)
+			break
+		}
+	}
+
+	// Telemetry is only sent for the requested drivers, added to the drivers the telemetry panel wants
+	d.sources.selectDrivers(DriverDashboard, driverNumber)
+
+	d.gapChart.refreshBackground()
+	d.telemetryChart.refreshBackground()
+}
+
+func (d *driverDashboard) selectedNumber() int {
+	d.dataLock.Lock()
+	defer d.dataLock.Unlock()
+	return d.selectedDriverNumber
+}
+
+func (d *driverDashboard) Draw(width int, height int) []giu.Widget {
+	// Follow the focused driver unless pinned to a driver
+	if focused := d.focus.Focused(); !d.pinned && focused != NoDriver && focused != d.selectedDriverNumber {
+		d.selectDriver(focused)
+	}
+
+	driverName := "<none>"
+	if d.selectedDriver != NothingSelected {
+		driverName = d.driverNames[d.selectedDriver]
+	}
+
+	widgets := []giu.Widget{
+		giu.Row(
+			giu.Combo("Driver", driverName, d.driverNames, &d.selectedDriver).OnChange(func() {
+				d.dataLock.Lock()
+				selected := NoDriver
+				for num, driver := range d.drivers {
+					if driver.info.ShortName == d.driverNames[d.selectedDriver] {
+						selected = num
+						break
+					}
+				}
+				d.dataLock.Unlock()
+
+				// Choosing a driver stops following the focused driver
+				d.pinned = true
+				d.selectDriver(selected)
+			}).Size(100),
+			giu.Checkbox("Pin", &d.pinned),
+		),
+	}
+
+	if d.selectedDriverNumber == NoDriver {
+		return append(widgets, giu.Label("Select a driver or click on a driver in the timing table or track map"))
+	}
+
+	const timingRowHeight = 50
+	columnWidth := max((width-40)/3, 100)
+	columnHeight := max(height-timingRowHeight-40, 200)
+
+	// Charts lock the data when drawing so build them before taking the lock
+	centreColumn := append(
+		d.gapChart.draw(columnWidth-16, columnHeight/2-8),
+		d.telemetryChart.draw(columnWidth-16, columnHeight/2-8)...)
+	trackMap := d.trackMap.Draw(columnWidth, columnHeight/2)
+
+	d.dataLock.Lock()
+	defer d.dataLock.Unlock()
+
+	driver := d.drivers[d.selectedDriverNumber]
+
+	return append(widgets,
+		d.timingRow(driver).Size(giu.Auto, timingRowHeight),
+		giu.Row(
+			giu.Child().Size(float32(columnWidth), float32(columnHeight)).Layout(
+				giu.Label("Laps"),
+				d.lapTable(driver).Size(giu.Auto, float32(columnHeight)*0.65),
+				giu.Label("Stints"),
+				d.stintTable(driver),
+			),
+			giu.Child().Size(float32(columnWidth), float32(columnHeight)).Layout(centreColumn...),
+			giu.Child().Size(float32(columnWidth), float32(columnHeight)).Layout(
+				append(trackMap,
+					giu.TabBar().TabItems(
+						giu.TabItem("Radio").Layout(d.radioMessages(driver)...),
+						giu.TabItem("Race Control").Layout(d.raceControlMessages(driver)...),
+					))...),
+		),
+	)
+}
+
+// timingRow is the drivers row from the timing table. Caller must hold the data lock.
+func (d *driverDashboard) timingRow(driver *dashboardDriver) *giu.TableWidget {
+	timing := driver.timing
+
+	return giu.Table().FastMode(true).Flags(giu.TableFlagsResizable|giu.TableFlagsSizingFixedSame).
+		Columns(
+			giu.TableColumn("Pos").InnerWidthOrWeight(25),
+			giu.TableColumn("Drv").InnerWidthOrWeight(35),
+			giu.TableColumn("Gap").InnerWidthOrWeight(timeWidth),
+			giu.TableColumn("Interval").InnerWidthOrWeight(timeWidth),
+			giu.TableColumn("Fastest").InnerWidthOrWeight(timeWidth),
+			giu.TableColumn("S1").InnerWidthOrWeight(timeWidth),
+			giu.TableColumn("S2").InnerWidthOrWeight(timeWidth),
+			giu.TableColumn("S3").InnerWidthOrWeight(timeWidth),
+			giu.TableColumn("Last Lap").InnerWidthOrWeight(timeWidth),
+			giu.TableColumn("Tire").InnerWidthOrWeight(50),
+			giu.TableColumn("Lap").InnerWidthOrWeight(30),
+			giu.TableColumn("Pits").InnerWidthOrWeight(30),
+			giu.TableColumn("Location").InnerWidthOrWeight(70),
+		).
+		Rows(giu.TableRow(
+			giu.Label(fmt.Sprintf("%d", timing.Position)),
+			giu.Style().SetColor(giu.StyleColorText, driver.info.Color).To(giu.Label(driver.info.ShortName)),
+			giu.Label(fmtDuration(timing.GapToLeader)),
+			giu.Label(fmtDuration(timing.TimeDiffToPositionAhead)),
+			giu.Style().SetColor(giu.StyleColorText, fastestLapColor(timing.OverallFastestLap)).To(
+				giu.Label(fmtDuration(timing.FastestLap))),
+			giu.Style().SetColor(giu.StyleColorText, timeColor(timing.Sector1PersonalFastest, timing.Sector1OverallFastest)).To(
+				giu.Label(fmtDuration(timing.Sector1))),
+			giu.Style().SetColor(giu.StyleColorText, timeColor(timing.Sector2PersonalFastest, timing.Sector2OverallFastest)).To(
+				giu.Label(fmtDuration(timing.Sector2))),
+			giu.Style().SetColor(giu.StyleColorText, timeColor(timing.Sector3PersonalFastest, timing.Sector3OverallFastest)).To(
+				giu.Label(fmtDuration(timing.Sector3))),
+			giu.Style().SetColor(giu.StyleColorText, timeColor(timing.LastLapPersonalFastest, timing.LastLapOverallFastest)).To(
+				giu.Label(fmtDuration(timing.LastLap))),
+			giu.Style().SetColor(giu.StyleColorText, tireColor(timing.Tire)).To(giu.Label(timing.Tire.String())),
+			giu.Label(fmt.Sprintf("%d", timing.LapsOnTire)),
+			giu.Label(fmt.Sprintf("%d", timing.Pitstops)),
+			giu.Style().SetColor(giu.StyleColorText, locationColor(timing.Location)).To(giu.Label(timing.Location.String())),
+		))
+}
+
+// lapTable lists every completed lap with the most recent first. Caller must hold the data lock.
+func (d *driverDashboard) lapTable(driver *dashboardDriver) *giu.TableWidget {
+	rows := make([]*giu.TableRowWidget, 0, len(driver.laps))
+	for x := len(driver.laps) - 1; x >= 0; x-- {
+		lap := driver.laps[x]
+
+		lapNumber := fmt.Sprintf("%d", lap.lap)
+		if lap.pitLap {
+			lapNumber += " P"
+		}
+
+		rows = append(rows, giu.TableRow(
+			giu.Label(lapNumber),
+			giu.Label(fmtDuration(lap.time)),
+			giu.Label(fmtDuration(lap.sector1)),
+			giu.Label(fmtDuration(lap.sector2)),
+			giu.Label(fmtDuration(lap.sector3)),
+			giu.Style().SetColor(giu.StyleColorText, tireColor(lap.tire)).To(giu.Label(lap.tire.String())),
+			giu.Label(fmt.Sprintf("%d", lap.position)),
+		))
+	}
+
+	return giu.Table().FastMode(true).Flags(giu.TableFlagsResizable|giu.TableFlagsSizingFixedSame|giu.TableFlagsScrollY).
+		Columns(
+			giu.TableColumn("Lap").InnerWidthOrWeight(35),
+			giu.TableColumn("Time").InnerWidthOrWeight(timeWidth),
+			giu.TableColumn("S1").InnerWidthOrWeight(timeWidth),
+			giu.TableColumn("S2").InnerWidthOrWeight(timeWidth),
+			giu.TableColumn("S3").InnerWidthOrWeight(timeWidth),
+			giu.TableColumn("Tire").InnerWidthOrWeight(50),
+			giu.TableColumn("Pos").InnerWidthOrWeight(25),
+		).
+		Rows(rows...)
+}
+
+// stintTable lists the tires used by the driver. Caller must hold the data lock.
+func (d *driverDashboard) stintTable(driver *dashboardDriver) *giu.TableWidget {
+	rows := make([]*giu.TableRowWidget, 0, len(driver.stints))
+	for x, stint := range driver.stints {
+		rows = append(rows, giu.TableRow(
+			giu.Label(fmt.Sprintf("%d", x+1)),
+			giu.Style().SetColor(giu.StyleColorText, tireColor(stint.tire)).To(giu.Label(stint.tire.String())),
+			giu.Label(fmt.Sprintf("%d", stint.startLap)),
+			giu.Label(fmt.Sprintf("%d", stint.laps)),
+		))
+	}
+
+	return giu.Table().FastMode(true).Flags(giu.TableFlagsResizable|giu.TableFlagsSizingFixedSame).
+		Columns(
+			giu.TableColumn("Stint").InnerWidthOrWeight(40),
+			giu.TableColumn("Tire").InnerWidthOrWeight(60),
+			giu.TableColumn("From Lap").InnerWidthOrWeight(60),
+			giu.TableColumn("Laps").InnerWidthOrWeight(40),
+		).
+		Rows(rows...)
+}
+
+// radioMessages lists the drivers team radio messages from the team radio panel so they can be played again. Caller
+// must hold the data lock.
+func (d *driverDashboard) radioMessages(driver *dashboardDriver) []giu.Widget {
+	widgets := []giu.Widget{}
+	for x, msg := range d.radio.DriverMessages(driver.info.Name) {
+		widgets = append(widgets, giu.Row(
+			giu.Button(fmt.Sprintf("Play##radio%d", x)).OnClick(func() { d.radio.Play(msg) }),
+			giu.Label(msg.Timestamp.In(d.dataSrc.CircuitTimezone()).Format("15:04:05")),
+		))
+	}
+
+	if len(widgets) == 0 {
+		widgets = append(widgets, giu.Label("No radio messages"))
+	}
+	return widgets
+}
+
+// raceControlMessages lists the race control messages that mention the drivers car. Caller must hold the data lock.
+func (d *driverDashboard) raceControlMessages(driver *dashboardDriver) []giu.Widget {
+	widgets := []giu.Widget{}
+	for _, msg := range d.rcMessages {
+		if !msg.MentionsCar(driver.info.Number) {
+			continue
+		}
+
+		widgets = append(widgets, giu.Label(fmt.Sprintf("%s - %s",
+			msg.Timestamp.In(d.dataSrc.CircuitTimezone()).Format("15:04:05"), msg.Msg)).Wrapped(true))
+	}
+
+	if len(widgets) == 0 {
+		widgets = append(widgets, giu.Label("No race control messages"))
+	}
+	return widgets
+}
+
+func (d *driverDashboard) prepareGapChart() {
+	d.dataLock.Lock()
+	defer d.dataLock.Unlock()
+
+	driver, exists := d.drivers[d.selectedDriverNumber]
+	if !exists || len(driver.gaps) == 0 {
+		d.gapChart.message = "Waiting for data..."
+		return
+	}
+
+	maxGap := 1.0
+	for _, gap := range driver.gaps {
+		maxGap = math.Max(maxGap, math.Max(gap.ahead.Seconds(), gap.behind.Seconds()))
+	}
+
+	d.gapChart.message = ""
+	d.gapChart.setXRange(float64(driver.gaps[0].lap), float64(max(driver.gaps[len(driver.gaps)-1].lap, driver.gaps[0].lap+1)))
+	d.gapChart.setYRange(0, 0, math.Ceil(maxGap))
+}
+
+func (d *driverDashboard) drawGaps(dc *cairo.Surface) {
+	d.dataLock.Lock()
+	defer d.dataLock.Unlock()
+
+	driver, exists := d.drivers[d.selectedDriverNumber]
+	if !exists {
+		return
+	}
+
+	lines := []struct {
+		color color.RGBA
+		value func(gap dashboardGap) time.Duration
+	}{
+		{color: colornames.Green, value: func(gap dashboardGap) time.Duration { return gap.ahead }},
+		{color: colornames.Red, value: func(gap dashboardGap) time.Duration { return gap.behind }},
+	}
+
+	for _, line := range lines {
+		dc.SetSourceRGBA(floatColor(line.color))
+		dc.NewPath()
+		for _, gap := range driver.gaps {
+			// No car ahead or behind
+			if line.value(gap) == 0 {
+				dc.Stroke()
+				continue
+			}
+
+			dc.LineTo(d.gapChart.xPos(float64(gap.lap)), d.gapChart.yPos(0, line.value(gap).Seconds()))
+		}
+		dc.Stroke()
+	}
+}
+
+func (d *driverDashboard) prepareTelemetryChart() {
+	d.dataLock.Lock()
+	defer d.dataLock.Unlock()
+
+	if d.telemetryTime.count() == 0 {
+		d.telemetryChart.message = "Waiting for telemetry..."
+		return
+	}
+
+	// Time axis is seconds before the latest sample
+	d.telemetryChart.message = ""
+	d.telemetryChart.setXRange(-60, 0)
+	d.telemetryChart.setYRange(0, 0, 380)
+	d.telemetryChart.setYRange(1, 0, 100)
+	d.telemetryChart.setYRange(2, 0, 100)
+}
+
+func (d *driverDashboard) drawTelemetry(dc *cairo.Surface) {
+	d.dataLock.Lock()
+	defer d.dataLock.Unlock()
+
+	count := d.telemetryTime.count()
+	if count == 0 {
+		return
+	}
+
+	latest := d.telemetryTime.get(count - 1)
+	channels := []*circularBuffer[float32]{d.speed, d.throttle, d.brake}
+
+	for axis, channel := range channels {
+		dc.SetSourceRGBA(floatColor(d.telemetryChart.y[axis].color))
+		dc.NewPath()
+		for x := 0; x < count; x++ {
+			secondsBefore := d.telemetryTime.get(x).Sub(latest).Seconds()
+			dc.LineTo(d.telemetryChart.xPos(secondsBefore), d.telemetryChart.yPos(axis, float64(channel.get(x))))
+		}
+		dc.Stroke()
+	}
+}
@@ -0,0 +1,146 @@
+// F1Gopher - Copyright (C) 2025 f1gopher
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package panel
+
+import (
+	"image/color"
+	"sync"
+
+	"github.com/AllenDang/cimgui-go/imgui"
+)
+
+var focusedBackground = color.RGBA{R: 20, G: 90, B: 160, A: 255}
+var comparisonBackground = color.RGBA{R: 130, G: 80, B: 20, A: 255}
+
+// DriverFocus is the driver being followed for the whole session, and optionally a driver to compare them against.
+// Panels highlight the focused driver and panels with a driver selection follow it unless they have been pinned.
+type DriverFocus struct {
+	focused    int
+	comparison int
+	lock       sync.Mutex
+}
+
+func CreateDriverFocus() *DriverFocus {
+	return &DriverFocus{
+		focused:    NoDriver,
+		comparison: NoDriver,
+	}
+}
+
+func (d *DriverFocus) Reset() {
+	d.lock.Lock()
+	d.focused = NoDriver
+	d.comparison = NoDriver
+	d.lock.Unlock()
+}
+
+func (d *DriverFocus) Focused() int {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	return d.focused
+}
+
+func (d *DriverFocus) Comparison() int {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	return d.comparison
+}
+
+// Focus follows the driver, focusing on the already focused driver clears the focus
+func (d *DriverFocus) Focus(driverNumber int) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if d.focused == driverNumber {
+		d.focused = NoDriver
+		return
+	}
+
+	d.focused = driverNumber
+	if d.comparison == driverNumber {
+		d.comparison = NoDriver
+	}
+}
+
+// Follow focuses the driver, unlike Focus it doesn't clear the focus if they are already followed
+func (d *DriverFocus) Follow(driverNumber int) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	d.focused = driverNumber
+	if d.comparison == driverNumber {
+		d.comparison = NoDriver
+	}
+}
+
+// Clear stops following the focused driver
+func (d *DriverFocus) Clear() {
+	d.lock.Lock()
+	d.focused = NoDriver
+	d.lock.Unlock()
+}
+
+// Compare sets the driver to compare against the focused driver, comparing the already compared driver clears it
+func (d *DriverFocus) Compare(driverNumber int) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if d.comparison == driverNumber || d.focused == driverNumber {
+		d.comparison = NoDriver
+		return
+	}
+
+	d.comparison = driverNumber
+}
+
+// Select focuses the driver on a normal click or sets them as the comparison driver on a shift click
+func (d *DriverFocus) Select(driverNumber int) {
+	if imgui.CurrentIO().KeyShift() {
+		d.Compare(driverNumber)
+	} else {
+		d.Focus(driverNumber)
+	}
+}
+
+// changedSince reports if the focused or comparison driver is different to the last seen values and updates them
+func (d *DriverFocus) changedSince(lastFocused *int, lastComparison *int) bool {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if d.focused == *lastFocused && d.comparison == *lastComparison {
+		return false
+	}
+
+	*lastFocused = d.focused
+	*lastComparison = d.comparison
+	return true
+}
+
+// highlight returns the background color for the driver if they are focused or being compared
+func (d *DriverFocus) highlight(driverNumber int) (color.RGBA, bool) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	switch driverNumber {
+	case NoDriver:
+		return color.RGBA{}, false
+	case d.focused:
+		return focusedBackground, true
+	case d.comparison:
+		return comparisonBackground, true
+	}
+	return color.RGBA{}, false
+}
@@ -20,6 +20,7 @@ import (
 	"image/color"
 	"math"
 	"sort"
+	"time"
 
 	"github.com/AllenDang/cimgui-go/imgui"
 	"github.com/AllenDang/giu"
@@ -44,29 +45,36 @@ type gapperPlot struct {
 	driverNames          []string
 	selectedDriver       int32
 	selectedDriverNumber int
+	focus                *DriverFocus
+	pinned               bool
+	drawnFocus           int
+	drawnComparison      int
 	yMin                 float64
 	yMax                 float64
 
 	visibleDriversSelect *gapperDriverDisplaySelectWidget
 
-	plot            *plot
-	yAxisPos        float64
-	firstDriverY    float64
-	xGap            float64
-	yGap            float64
-	endXPos         float64
-	yAxisPosForZero float64
+	chart *chart
+	plot  *plot
 }
 
 const NothingSelected = -1
 const NoDriver = 0
 
-func CreateGapperPlot() Panel {
+func CreateGapperPlot(focus *DriverFocus) Panel {
 	panel := &gapperPlot{
 		driverData: map[int]*gapperPlotInfo{},
 		totalLaps:  0,
+		focus:      focus,
 	}
-	panel.plot = createPlot(panel.drawBackground, panel.drawForeground)
+	panel.chart = createChart("Gapper Plot")
+	panel.chart.prepare = panel.prepareChart
+	panel.chart.drawBackground = panel.drawBackground
+	panel.chart.drawForeground = panel.drawForeground
+	panel.chart.hover = panel.hover
+	panel.chart.y[0].majorTick = 1.0
+	panel.chart.x.majorTick = 1.0
+	panel.plot = panel.chart.plot
 	panel.visibleDriversSelect = &gapperDriverDisplaySelectWidget{
 		plot:    panel.plot,
 		drivers: []*gapperPlotInfo{},
@@ -90,11 +98,12 @@ func (g *gapperPlot) Init(dataSrc f1gopherlib.F1GopherLib, config PanelConfig) {
 	g.driverNames = []string{}
 	g.selectedDriver = NothingSelected
 	g.selectedDriverNumber = NothingSelected
+	g.pinned = false
 	g.yMin = math.MaxFloat64
 	g.yMax = -math.MaxFloat64
 	g.visibleDriversSelect.drivers = []*gapperPlotInfo{}
 	g.visibleDriversSelect.visibleCount = 0
-	g.plot.reset()
+	g.chart.reset()
 }
 
 func (g *gapperPlot) ProcessDrivers(data Messages.Drivers) {
@@ -229,85 +238,95 @@ func (g *gapperPlot) refreshYMinMax() bool {
 	return refreshBackground
 }
 
+func (g *gapperPlot) RenderChart(width int, height int, svg bool) ([]byte, error) {
+	return g.chart.render(width, height, svg)
+}
+
 func (g *gapperPlot) Draw(width int, height int) []giu.Widget {
+	// Follow the focused driver unless pinned to a driver
+	if focused := g.focus.Focused(); !g.pinned && focused != NoDriver && focused != g.selectedDriverNumber {
+		g.selectDriver(focused)
+	}
+
+	// Redraw when the focused drivers change so they are highlighted
+	if g.focus.changedSince(&g.drawnFocus, &g.drawnComparison) {
+		g.plot.refreshForeground()
+	}
+
 	driverName := "<none>"
 	if g.selectedDriver != NothingSelected {
 		driverName = g.driverNames[g.selectedDriver]
 	}
 
-	return []giu.Widget{
-		giu.Row(
+	return append([]giu.Widget{
+		giu.Row(append([]giu.Widget{
 			giu.Combo("Driver", driverName, g.driverNames, &g.selectedDriver).OnChange(func() {
 				for num, driver := range g.driverData {
 					if driver.name == g.driverNames[g.selectedDriver] {
-						g.selectedDriverNumber = num
-						if g.refreshYMinMax() {
-							g.plot.refreshBackground()
-						}
+						// Choosing a driver stops following the focused driver
+						g.pinned = true
+						g.selectDriver(num)
 						break
 					}
 				}
-
-				g.plot.refreshForeground()
 			}).Size(100),
+			giu.Checkbox("Pin", &g.pinned),
 			g.visibleDriversSelect,
-		),
-		g.plot.draw(width-16, height-38),
+		}, g.chart.toolbar()...)...),
+	}, g.chart.draw(width-16, height-38)...)
+}
+
+func (g *gapperPlot) selectDriver(driverNumber int) {
+	driver, exists := g.driverData[driverNumber]
+	if !exists {
+		return
 	}
+
+	for x := range g.driverNames {
+		if g.driverNames[x] == driver.name {
+			g.selectedDriver = int32(x)
+			break
+		}
+	}
+	g.selectedDriverNumber = driverNumber
+
+	if g.refreshYMinMax() {
+		g.plot.refreshBackground()
+	}
+	g.plot.refreshForeground()
 }
 
-func (g *gapperPlot) drawBackground(dc *cairo.Surface) {
-	width := float64(dc.GetWidth())
-	height := float64(dc.GetHeight())
+// gapperLapNumber returns the lap number for an index into the lap times, we don't get a lap time for the first lap
+func gapperLapNumber(index int) int {
+	return index + 2
+}
 
+func (g *gapperPlot) prepareChart() {
 	// If no driver selected then draw nothing
-	if g.selectedDriver == NothingSelected || math.Abs(g.driverData[g.selectedDriverNumber].fastest-math.MaxFloat64) < math.SmallestNonzeroFloat64 {
-		// Black background
-		dc.SetSourceRGB(0.0, 0.0, 0.0)
-		dc.Rectangle(0, 0, width, height)
-		dc.Fill()
-		dc.Stroke()
-
-		dc.SetSourceRGB(1.0, 1.0, 1.0)
-		dc.MoveTo((width/2)-50, height/2)
-		dc.ShowText("Waiting for data...")
-		dc.Stroke()
+	if g.selectedDriver == NothingSelected || g.yMin == math.MaxFloat64 ||
+		math.Abs(g.driverData[g.selectedDriverNumber].fastest-math.MaxFloat64) < math.SmallestNonzeroFloat64 {
+		g.chart.message = "Waiting for data..."
 		return
 	}
 
-	// Leave border all around the chart
-	margin := 10.0
-	// X location for Y axis
-	g.yAxisPos = margin + 30
-	// X pos end of X axis location
-	g.endXPos = width - margin
-	g.xGap = (g.endXPos - g.yAxisPos) / float64(g.totalLaps+1)
-	yAxisLength := height - margin - margin
-	// Gap per 1.0 increment on the y axis
-	g.yGap = yAxisLength / (g.yMax + math.Abs(g.yMin))
-
-	// Black background
-	dc.SetSourceRGB(0.0, 0.0, 0.0)
-	dc.Rectangle(0, 0, width, height)
-	dc.Fill()
-	dc.Stroke()
+	g.chart.message = ""
+	g.chart.setXRange(float64(gapperLapNumber(0)), float64(max(g.totalLaps, gapperLapNumber(0)+1)))
+	g.chart.setYRange(0, math.Min(g.yMin, 0), math.Max(g.yMax, 0))
+
+	g.chart.legend = []chartLegendEntry{}
+	for _, driver := range g.visibleDriversSelect.drivers {
+		if driver.visible {
+			g.chart.legend = append(g.chart.legend, chartLegendEntry{name: driver.name, color: driver.color})
+		}
+	}
+}
 
-	// X Axis line - at 0 for the Y value
+func (g *gapperPlot) drawBackground(dc *cairo.Surface) {
+	// Line at 0 for the selected drivers fastest lap
 	dc.SetSourceRGB(1.0, 1.0, 1.0)
-	g.yAxisPosForZero = margin + (g.yMax * g.yGap)
-	dc.MoveTo(g.yAxisPos, g.yAxisPosForZero)
-	dc.LineTo(width-margin, g.yAxisPosForZero)
+	dc.MoveTo(g.chart.x.start, g.chart.yPos(0, 0))
+	dc.LineTo(g.chart.x.end, g.chart.yPos(0, 0))
 	dc.Stroke()
-
-	drawYAxis(
-		dc,
-		g.yAxisPos,
-		margin,
-		height-margin,
-		g.yAxisPosForZero,
-		0.0,
-		g.yGap,
-		1.0)
 }
 
 func (g *gapperPlot) drawForeground(dc *cairo.Surface) {
@@ -329,25 +348,56 @@ func (g *gapperPlot) drawForeground(dc *cairo.Surface) {
 
 		// Draw line from start position to current position
 		dc.SetSourceRGBA(floatColor(g.driverData[key].color))
-
-		currentXPos := g.yAxisPos
+		if _, focused := g.focus.highlight(key); focused {
+			dc.SetLineWidth(4.0)
+		}
 
 		for x, lapTime := range g.driverData[key].lapTimes {
-			value := lapTime - baseline
-			yPos := g.yAxisPosForZero - (value * g.yGap)
+			xPos := g.chart.xPos(float64(gapperLapNumber(x)))
+			yPos := g.chart.yPos(0, lapTime-baseline)
 
 			if x == 0 {
-				dc.MoveTo(currentXPos, yPos)
+				dc.MoveTo(xPos, yPos)
 			}
 
-			dc.LineTo(currentXPos, yPos)
-
-			currentXPos += g.xGap
+			dc.LineTo(xPos, yPos)
 		}
 		dc.Stroke()
+		dc.SetLineWidth(2.0)
 	}
 }
 
+// hover shows the lap time of the closest visible driver to the mouse
+func (g *gapperPlot) hover(xPos float64, yPos float64) string {
+	if g.selectedDriverNumber == NothingSelected {
+		return ""
+	}
+
+	index := int(math.Round(g.chart.xValue(xPos))) - gapperLapNumber(0)
+	baseline := g.driverData[g.selectedDriverNumber].fastest
+
+	// Only match drivers within a few pixels of the mouse
+	closest := 8.0
+	result := ""
+	for _, driver := range g.driverData {
+		if !driver.visible || index < 0 || index >= len(driver.lapTimes) {
+			continue
+		}
+
+		distance := math.Abs(g.chart.yPos(0, driver.lapTimes[index]-baseline) - yPos)
+		if distance < closest {
+			closest = distance
+			result = fmt.Sprintf("%s\nLap: %d\nLap Time: %s\nGap: %+.3fs",
+				driver.name,
+				gapperLapNumber(index),
+				fmtDuration(time.Duration(driver.lapTimes[index]*float64(time.Second))),
+				driver.lapTimes[index]-baseline)
+		}
+	}
+
+	return result
+}
+
 type gapperDriverDisplaySelectWidget struct {
 	id           string
 	drivers      []*gapperPlotInfo
@@ -16,6 +16,7 @@
 package panel
 
 import (
+	"f1gopher/regulations"
 	"image/color"
 	"math"
 	"sort"
@@ -55,14 +56,17 @@ type fastLapInfo struct {
 	driverColor  color.RGBA
 	position     int
 	location     Messages.CarLocation
+	lastLap      int
+	lastLapTime  time.Duration
 
 	diffToPole         time.Duration
 	diffToPersonalBest time.Duration
 }
 
 type improving struct {
-	dataSrc   f1gopherlib.F1GopherLib
-	trackMaps *trackMapStore
+	dataSrc     f1gopherlib.F1GopherLib
+	trackMaps   *trackMapStore
+	trackLimits *trackLimitsTracker
 
 	fastestDriverNum int
 	fastestLap       *fastLapInfo
@@ -72,17 +76,21 @@ type improving struct {
 
 	lastSegmentIndex int
 
-	sortedDrivers []*fastLapInfo
-	session       Messages.EventType
+	sortedDrivers      []*fastLapInfo
+	trackLimitsUpdates int
+	session            Messages.EventType
+	season             int
+	qualifyingType     regulations.QualifyingType
 
 	lock  sync.Mutex
 	table *giu.TableWidget
 }
 
-func CreateImproving(trackMaps *trackMapStore) Panel {
+func CreateImproving(trackMaps *trackMapStore, trackLimits *trackLimitsTracker) Panel {
 	return &improving{
-		lock:      sync.Mutex{},
-		trackMaps: trackMaps,
+		lock:        sync.Mutex{},
+		trackMaps:   trackMaps,
+		trackLimits: trackLimits,
 	}
 }
 
@@ -101,10 +109,13 @@ func (i *improving) Init(dataSrc f1gopherlib.F1GopherLib, config PanelConfig) {
 	i.driverFastestLaps = make(map[int]*fastLapInfo)
 	i.table = nil
 	i.session = Messages.Qualifying0
+	i.season = dataSrc.SessionStart().Year()
+	i.qualifyingType = regulations.QualifyingSessionType(dataSrc.SessionStart())
 	i.sortedDrivers = make([]*fastLapInfo, 0)
 	i.fastestDriverNum = 0
 	i.fastestLap = nil
 	i.lastSegmentIndex = 0
+	i.trackLimitsUpdates = 0
 
 }
 
@@ -112,6 +123,7 @@ func (i *improving) ProcessEvent(data Messages.Event) {
 	// Reset times when the session changes
 	if data.Type != i.session {
 		i.session = data.Type
+		qualifying := regulations.QualifyingRules(i.season, i.qualifyingType, len(i.driverCurrentLaps))
 
 		// Reset driver info's
 		for _, driverInfo := range i.driverCurrentLaps {
@@ -122,13 +134,9 @@ func (i *improving) ProcessEvent(data Messages.Event) {
 			driverInfo.isRecording = false
 
 			// Don't display drivers who are out of qualifying
-			switch data.Type {
-			case Messages.Qualifying1:
-				driverInfo.displayDriver = driverInfo.location != Messages.Stopped
-			case Messages.Qualifying2:
-				driverInfo.displayDriver = driverInfo.position <= 15 && driverInfo.location != Messages.Stopped
-			case Messages.Qualifying3:
-				driverInfo.displayDriver = driverInfo.position <= 10 && driverInfo.location != Messages.Stopped
+			if segment := qualifying.Segment(data.Type); segment > 0 {
+				driverInfo.displayDriver = !qualifying.KnockedOut(segment, driverInfo.position) &&
+					driverInfo.location != Messages.Stopped
 			}
 		}
 
@@ -175,6 +183,21 @@ func (i *improving) ProcessTiming(data Messages.Timing) {
 		driverInfo.displayDriver = false
 	}
 	driverInfo.location = data.Location
+
+	// We don't get a lap time for the first lap so the last lap is always the one before the current lap
+	if data.LastLap != driverInfo.lastLapTime {
+		driverInfo.lastLap = data.Lap - 1
+		driverInfo.lastLapTime = data.LastLap
+		i.updateTable()
+	}
+
+	// Redraw when a lap has been deleted or reinstated. The tracker is fed by the timing table which may see the race
+	// control message after this panel.
+	if updates := i.trackLimits.updateCount(); updates != i.trackLimitsUpdates {
+		i.trackLimitsUpdates = updates
+		i.updateTable()
+	}
+
 	if driverInfo.position != data.Position {
 		driverInfo.position = data.Position
 		sort.Slice(
@@ -413,6 +436,15 @@ func (i *improving) updateTable() {
 			parts = append(parts, giu.Label("       "))
 		}
 
+		// Deleted laps don't count so make it obvious when the last lap has gone
+		if _, deleted := i.trackLimits.deletedLap(driver.driverNumber, driver.lastLap, driver.lastLapTime); deleted {
+			parts = append(parts,
+				giu.Style().SetColor(giu.StyleColorText, colornames.Red).
+					To(giu.Label(fmtDuration(driver.lastLapTime)+" DEL")))
+		} else {
+			parts = append(parts, giu.Label(fmtDuration(driver.lastLapTime)))
+		}
+
 		rows = append(rows, giu.TableRow(parts...))
 	}
 
@@ -422,6 +454,7 @@ func (i *improving) updateTable() {
 			giu.TableColumn("Driver").InnerWidthOrWeight(70),
 			giu.TableColumn("Personal Δ").InnerWidthOrWeight(100),
 			giu.TableColumn("Pole Δ").InnerWidthOrWeight(100),
+			giu.TableColumn("Last Lap").InnerWidthOrWeight(timeWidth+30),
 		).Rows(rows...)
 
 	i.lock.Lock()
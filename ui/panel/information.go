@@ -16,6 +16,7 @@
 package panel
 
 import (
+	"f1gopher/regulations"
 	"fmt"
 	"sync"
 	"time"
@@ -25,6 +26,16 @@ import (
 	"github.com/f1gopher/f1gopherlib/Messages"
 )
 
+// SessionControls are the playback actions of the information panel so they can also be run by the remote control
+type SessionControls interface {
+	IsPaused() bool
+	SetPaused(paused bool)
+	Skip(duration time.Duration)
+	CanSkipToStart() bool
+	SkipToStart()
+	Back()
+}
+
 type information struct {
 	exit          func()
 	dataSrc       f1gopherlib.F1GopherLib
@@ -34,7 +45,7 @@ type information struct {
 	eventLock     sync.Mutex
 	eventTime     time.Time
 	remainingTime time.Duration
-	eventHasDRS   bool
+	overtakeAid   regulations.OvertakeAid
 }
 
 func CreateInformation(exit func(), isLiveSession bool) Panel {
@@ -61,7 +72,7 @@ func (i *information) Init(dataSrc f1gopherlib.F1GopherLib, config PanelConfig)
 	// Clear previous session data
 	i.event = Messages.Event{}
 	i.remainingTime = 0
-	i.eventHasDRS = dataSrc.SessionStart().Year() <= 2025
+	i.overtakeAid = regulations.OvertakeAidForSeason(dataSrc.SessionStart().Year())
 }
 
 func (i *information) ProcessEventTime(data Messages.EventTime) {
@@ -75,6 +86,40 @@ func (i *information) ProcessEvent(data Messages.Event) {
 	i.eventLock.Unlock()
 }
 
+func (i *information) IsPaused() bool {
+	return i.dataSrc != nil && i.dataSrc.IsPaused()
+}
+
+func (i *information) SetPaused(paused bool) {
+	if i.dataSrc != nil && i.dataSrc.IsPaused() != paused {
+		i.dataSrc.TogglePause()
+	}
+}
+
+func (i *information) Skip(duration time.Duration) {
+	if i.dataSrc != nil {
+		i.dataSrc.IncrementTime(duration)
+	}
+}
+
+func (i *information) CanSkipToStart() bool {
+	// Once the remaining time counter is not zero it has started counting down because the session has started
+	hasStarted := i.remainingTime != 0 && !i.isLiveSession
+	return !hasStarted
+}
+
+func (i *information) SkipToStart() {
+	if i.dataSrc != nil {
+		i.dataSrc.SkipToSessionStart()
+	}
+}
+
+func (i *information) Back() {
+	// Do this on another routine so this one can exit and stop drawing releasing the waitgroup that
+	// exit will wait for
+	go func() { i.exit() }()
+}
+
 func (i *information) Draw(width int, height int) []giu.Widget {
 
 	pauseTxt := "Pause"
@@ -82,28 +127,23 @@ func (i *information) Draw(width int, height int) []giu.Widget {
 		pauseTxt = "Resume"
 	}
 
-	// Once the remaining time counter is not zero it has started counting down because the session has started
-	hasStarted := i.remainingTime != 0 && !i.isLiveSession
-
 	panelWidgets := []giu.Widget{
 		giu.Row(
 			i.infoWidgets(),
 			giu.Button("Skip 5 Seconds").OnClick(func() {
-				i.dataSrc.IncrementTime(time.Second * 5)
+				i.Skip(time.Second * 5)
 			}),
 			giu.Button("Skip Minute").OnClick(func() {
-				i.dataSrc.IncrementTime(time.Minute * 1)
+				i.Skip(time.Minute * 1)
 			}),
 			giu.Button("Skip To Start").OnClick(func() {
-				i.dataSrc.SkipToSessionStart()
-			}).Disabled(hasStarted),
+				i.SkipToStart()
+			}).Disabled(!i.CanSkipToStart()),
 			giu.Button(pauseTxt).OnClick(func() {
-				i.dataSrc.TogglePause()
+				i.SetPaused(!i.IsPaused())
 			}),
 			giu.Button("Back").OnClick(func() {
-				// Do this on another routine so this one can exit and stop drawing releasing the waitgroup that
-				// exit will wait for
-				go func() { i.exit() }()
+				i.Back()
 			})),
 	}
 
@@ -131,13 +171,10 @@ func (i *information) infoWidgets() *giu.RowWidget {
 
 	// These are only relevant for a race session
 	if i.event.Type == Messages.Race || i.event.Type == Messages.Sprint {
-		if i.eventHasDRS {
-			widgets = append(widgets,
-				giu.Label(fmt.Sprintf(", DRS: %v, Safety Car:",
-					i.event.DRSEnabled.String())))
-		} else {
-			giu.Label(", Safety Car:")
-		}
+		widgets = append(widgets,
+			giu.Label(fmt.Sprintf(", %s: %v, Safety Car:",
+				i.overtakeAid.String(),
+				i.overtakeAid.EnabledState(i.event))))
 
 		widgets = append(widgets,
 			giu.Style().SetColor(giu.StyleColorText, safetyCarFormat(i.event.SafetyCar)).To(
@@ -0,0 +1,387 @@
+// F1Gopher - Copyright (C) 2025 f1gopher
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package panel
+
+import (
+	"fmt"
+	"image/color"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AllenDang/cimgui-go/imgui"
+	"github.com/AllenDang/giu"
+	"github.com/f1gopher/f1gopherlib"
+	"github.com/f1gopher/f1gopherlib/Messages"
+	"golang.org/x/image/colornames"
+)
+
+type analysisLap struct {
+	lap         int
+	lapTime     time.Duration
+	pitLap      bool
+	neutralised bool
+}
+
+type lapAnalysisDriver struct {
+	number   int
+	name     string
+	color    color.RGBA
+	position int
+	laps     map[int]*analysisLap
+
+	// State for the lap currently being driven
+	lastLap            int
+	currentPitLap      bool
+	currentNeutralised bool
+}
+
+type lapAnalysis struct {
+	trackLimits *trackLimitsTracker
+
+	drivers     map[int]*lapAnalysisDriver
+	neutralised bool
+	lock        sync.Mutex
+
+	// Driver whose lap times the rows are sorted by, or NoDriver to sort by the lap number. The driver columns are in
+	// position order so the column moves when the positions change.
+	sortDriver     int
+	sortDescending bool
+	status         string
+}
+
+func CreateLapAnalysis(trackLimits *trackLimitsTracker) Panel {
+	return &lapAnalysis{
+		trackLimits: trackLimits,
+		drivers:     map[int]*lapAnalysisDriver{},
+		sortDriver:  NoDriver,
+	}
+}
+
+func (l *lapAnalysis) ProcessEventTime(data Messages.EventTime)                    {}
+func (l *lapAnalysis) ProcessRaceControlMessages(data Messages.RaceControlMessage) {}
+func (l *lapAnalysis) ProcessWeather(data Messages.Weather)                        {}
+func (l *lapAnalysis) ProcessRadio(data Messages.Radio)                            {}
+func (l *lapAnalysis) ProcessLocation(data Messages.Location)                      {}
+func (l *lapAnalysis) ProcessTelemetry(data Messages.Telemetry)                    {}
+func (l *lapAnalysis) Close()                                                      {}
+
+func (l *lapAnalysis) Type() Type { return LapAnalysis }
+
+func (l *lapAnalysis) Init(dataSrc f1gopherlib.F1GopherLib, config PanelConfig) {
+	l.sortDriver = NoDriver
+	l.sortDescending = false
+	l.status = ""
+
+	l.lock.Lock()
+	l.drivers = map[int]*lapAnalysisDriver{}
+	l.neutralised = false
+	l.lock.Unlock()
+}
+
+func (l *lapAnalysis) ProcessDrivers(data Messages.Drivers) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	for _, driver := range data.Drivers {
+		l.drivers[driver.Number] = &lapAnalysisDriver{
+			number:   driver.Number,
+			name:     driver.ShortName,
+			color:    driver.Color,
+			position: driver.StartPosition,
+			laps:     map[int]*analysisLap{},
+		}
+	}
+}
+
+func (l *lapAnalysis) ProcessEvent(data Messages.Event) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.neutralised = data.SafetyCar != Messages.Clear || data.TrackStatus == Messages.RedFlag
+
+	// Any lap that is driven while the safety car or a red flag is out isn't representative
+	if l.neutralised {
+		for _, driver := range l.drivers {
+			driver.currentNeutralised = true
+		}
+	}
+}
+
+func (l *lapAnalysis) ProcessTiming(data Messages.Timing) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	driver, exists := l.drivers[data.Number]
+	if !exists {
+		return
+	}
+
+	if data.Position > 0 {
+		driver.position = data.Position
+	}
+
+	inPitlane := data.Location == Messages.Pitlane || data.Location == Messages.PitOut
+	if inPitlane {
+		driver.currentPitLap = true
+	}
+
+	// We don't get a lap time for the first lap so the last lap is always the one before the current lap
+	completedLap := data.Lap - 1
+	if data.LastLap == 0 || completedLap <= driver.lastLap {
+		return
+	}
+
+	driver.laps[completedLap] = &analysisLap{
+		lap:         completedLap,
+		lapTime:     data.LastLap,
+		pitLap:      driver.currentPitLap,
+		neutralised: driver.currentNeutralised,
+	}
+	driver.lastLap = completedLap
+
+	// Start tracking the next lap, if we are still in the pits then the next lap is an outlap
+	driver.currentPitLap = inPitlane
+	driver.currentNeutralised = l.neutralised
+}
+
+type lapAnalysisCell struct {
+	lapTime      time.Duration
+	pitLap       bool
+	neutralised  bool
+	deleted      bool
+	personalBest bool
+	overallBest  bool
+	hasLap       bool
+}
+
+func (c *lapAnalysisCell) text() string {
+	if !c.hasLap {
+		return ""
+	}
+
+	markers := []string{}
+	if c.pitLap {
+		markers = append(markers, "PIT")
+	}
+	if c.neutralised {
+		markers = append(markers, "SC")
+	}
+	if c.deleted {
+		markers = append(markers, "DEL")
+	}
+
+	if len(markers) == 0 {
+		return fmtDuration(c.lapTime)
+	}
+	return fmt.Sprintf("%s %s", fmtDuration(c.lapTime), strings.Join(markers, " "))
+}
+
+func (c *lapAnalysisCell) color() color.RGBA {
+	switch {
+	case c.deleted:
+		return colornames.Red
+	case c.overallBest:
+		return purpleColor
+	case c.personalBest:
+		return colornames.Green
+	case c.pitLap || c.neutralised:
+		return colornames.Gray
+	}
+	return colornames.White
+}
+
+// grid returns the drivers in position order and a row for every lap with a cell for each driver
+func (l *lapAnalysis) grid() ([]lapAnalysisDriver, [][]lapAnalysisCell, []int) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	drivers := make([]lapAnalysisDriver, 0, len(l.drivers))
+	for _, driver := range l.drivers {
+		drivers = append(drivers, *driver)
+	}
+	sort.Slice(drivers, func(i, j int) bool {
+		if drivers[i].position != drivers[j].position {
+			return drivers[i].position < drivers[j].position
+		}
+		return drivers[i].number < drivers[j].number
+	})
+
+	isDeleted := func(driverNumber int, lap *analysisLap) bool {
+		_, deleted := l.trackLimits.deletedLap(driverNumber, lap.lap, lap.lapTime)
+		return deleted
+	}
+
+	// Deleted laps don't count as bests
+	lastLap := 0
+	var overallBest time.Duration
+	personalBest := make([]time.Duration, len(drivers))
+	for x := range drivers {
+		for _, lap := range drivers[x].laps {
+			lastLap = max(lastLap, lap.lap)
+			if isDeleted(drivers[x].number, lap) {
+				continue
+			}
+			if personalBest[x] == 0 || lap.lapTime < personalBest[x] {
+				personalBest[x] = lap.lapTime
+			}
+			if overallBest == 0 || lap.lapTime < overallBest {
+				overallBest = lap.lapTime
+			}
+		}
+	}
+
+	rows := make([][]lapAnalysisCell, 0, lastLap)
+	lapNumbers := make([]int, 0, lastLap)
+	for lapNumber := 1; lapNumber <= lastLap; lapNumber++ {
+		row := make([]lapAnalysisCell, len(drivers))
+		for x := range drivers {
+			lap, exists := drivers[x].laps[lapNumber]
+			if !exists {
+				continue
+			}
+
+			deleted := isDeleted(drivers[x].number, lap)
+			row[x] = lapAnalysisCell{
+				lapTime:      lap.lapTime,
+				pitLap:       lap.pitLap,
+				neutralised:  lap.neutralised,
+				deleted:      deleted,
+				personalBest: !deleted && lap.lapTime == personalBest[x],
+				overallBest:  !deleted && lap.lapTime == overallBest,
+				hasLap:       true,
+			}
+		}
+		rows = append(rows, row)
+		lapNumbers = append(lapNumbers, lapNumber)
+	}
+
+	return drivers, rows, lapNumbers
+}
+
+// sortRows orders the laps by the selected drivers lap times, laps without a time for the driver go last
+func (l *lapAnalysis) sortRows(drivers []lapAnalysisDriver, rows [][]lapAnalysisCell, lapNumbers []int) {
+	column := -1
+	for x := range drivers {
+		if drivers[x].number == l.sortDriver {
+			column = x
+			break
+		}
+	}
+
+	order := make([]int, len(rows))
+	for x := range order {
+		order[x] = x
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := order[i], order[j]
+		if column < 0 || column >= len(rows[a]) {
+			if l.sortDescending {
+				return lapNumbers[a] > lapNumbers[b]
+			}
+			return lapNumbers[a] < lapNumbers[b]
+		}
+
+		cellA := rows[a][column]
+		cellB := rows[b][column]
+		if cellA.hasLap != cellB.hasLap {
+			return cellA.hasLap
+		}
+		if l.sortDescending {
+			return cellA.lapTime > cellB.lapTime
+		}
+		return cellA.lapTime < cellB.lapTime
+	})
+
+	sortedRows := make([][]lapAnalysisCell, len(rows))
+	sortedLaps := make([]int, len(rows))
+	for x, index := range order {
+		sortedRows[x] = rows[index]
+		sortedLaps[x] = lapNumbers[index]
+	}
+	copy(rows, sortedRows)
+	copy(lapNumbers, sortedLaps)
+}
+
+func (l *lapAnalysis) Draw(width int, height int) []giu.Widget {
+	drivers, rows, lapNumbers := l.grid()
+	l.sortRows(drivers, rows, lapNumbers)
+
+	sortBy := func(driverNumber int) func(giu.SortDirection) {
+		return func(direction giu.SortDirection) {
+			l.sortDriver = driverNumber
+			l.sortDescending = direction == giu.SortDescending
+		}
+	}
+
+	columns := []*giu.TableColumnWidget{
+		giu.TableColumn("Lap").InnerWidthOrWeight(30).Sort(sortBy(NoDriver)),
+	}
+	for x := range drivers {
+		columns = append(columns, giu.TableColumn(drivers[x].name).InnerWidthOrWeight(95).Sort(sortBy(drivers[x].number)))
+	}
+
+	tableRows := make([]*giu.TableRowWidget, 0, len(rows))
+	for x := range rows {
+		widgets := []giu.Widget{giu.Label(fmt.Sprintf("%d", lapNumbers[x]))}
+		for y := range rows[x] {
+			cell := &rows[x][y]
+			widgets = append(widgets, giu.Style().SetColor(giu.StyleColorText, cell.color()).To(giu.Label(cell.text())))
+		}
+		tableRows = append(tableRows, giu.TableRow(widgets...))
+	}
+
+	return []giu.Widget{
+		giu.Row(
+			giu.Button("Copy CSV").OnClick(func() {
+				imgui.SetClipboardText(l.csv(drivers, rows, lapNumbers))
+				l.status = fmt.Sprintf("Copied %d laps", len(rows))
+			}),
+			giu.Label(l.status),
+		),
+		giu.Table().FastMode(true).
+			Flags(giu.TableFlagsResizable|giu.TableFlagsSizingFixedSame|giu.TableFlagsScrollY|giu.TableFlagsScrollX|giu.TableFlagsSortable).
+			Freeze(1, 1).
+			Size(float32(width-16), float32(height-40)).
+			Columns(columns...).
+			Rows(tableRows...),
+	}
+}
+
+// csv returns the grid in the displayed order with the pit, safety car and deleted markers
+func (l *lapAnalysis) csv(drivers []lapAnalysisDriver, rows [][]lapAnalysisCell, lapNumbers []int) string {
+	var result strings.Builder
+
+	header := []string{"Lap"}
+	for x := range drivers {
+		header = append(header, drivers[x].name)
+	}
+	result.WriteString(strings.Join(header, ","))
+	result.WriteString("\n")
+
+	for x := range rows {
+		values := []string{fmt.Sprintf("%d", lapNumbers[x])}
+		for y := range rows[x] {
+			values = append(values, rows[x][y].text())
+		}
+		result.WriteString(strings.Join(values, ","))
+		result.WriteString("\n")
+	}
+
+	return result.String()
+}
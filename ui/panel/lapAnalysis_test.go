@@ -0,0 +1,56 @@
+package panel
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/f1gopher/f1gopherlib/Messages"
+)
+
+func TestLapAnalysisSortFollowsDriver(t *testing.T) {
+	l := CreateLapAnalysis(CreateTrackLimitsTracker()).(*lapAnalysis)
+	l.ProcessDrivers(Messages.Drivers{Drivers: []Messages.DriverInfo{
+		{Number: 1, ShortName: "VER", StartPosition: 1},
+		{Number: 16, ShortName: "LEC", StartPosition: 2},
+	}})
+	l.ProcessEvent(Messages.Event{Type: Messages.Race, SafetyCar: Messages.Clear})
+
+	// VER gets quicker each lap and LEC gets slower
+	verLaps := []time.Duration{93 * time.Second, 92 * time.Second, 91 * time.Second}
+	lecLaps := []time.Duration{90 * time.Second, 91 * time.Second, 92 * time.Second}
+	for x := range verLaps {
+		l.ProcessTiming(Messages.Timing{Number: 1, Lap: x + 2, LastLap: verLaps[x], Location: Messages.OnTrack})
+		l.ProcessTiming(Messages.Timing{Number: 16, Lap: x + 2, LastLap: lecLaps[x], Location: Messages.OnTrack})
+	}
+
+	l.sortDriver = 16
+	drivers, rows, lapNumbers := l.grid()
+	l.sortRows(drivers, rows, lapNumbers)
+	if !slices.Equal(lapNumbers, []int{1, 2, 3}) {
+		t.Errorf("expected the laps sorted by LEC's times, got %v", lapNumbers)
+	}
+
+	// LEC passes VER so the driver columns swap but the sort stays on LEC
+	l.ProcessTiming(Messages.Timing{Number: 16, Position: 1, Lap: 4, Location: Messages.OnTrack})
+	l.ProcessTiming(Messages.Timing{Number: 1, Position: 2, Lap: 4, Location: Messages.OnTrack})
+	drivers, rows, lapNumbers = l.grid()
+	l.sortRows(drivers, rows, lapNumbers)
+	if drivers[0].number != 16 {
+		t.Fatalf("expected LEC in the first column, got %d", drivers[0].number)
+	}
+	if !slices.Equal(lapNumbers, []int{1, 2, 3}) {
+		t.Errorf("expected the laps still sorted by LEC's times, got %v", lapNumbers)
+	}
+	if rows[0][0].lapTime != lecLaps[0] {
+		t.Errorf("expected LEC's quickest lap first, got %s", rows[0][0].lapTime)
+	}
+
+	l.sortDriver = NoDriver
+	l.sortDescending = true
+	drivers, rows, lapNumbers = l.grid()
+	l.sortRows(drivers, rows, lapNumbers)
+	if !slices.Equal(lapNumbers, []int{3, 2, 1}) {
+		t.Errorf("expected the laps sorted by lap number, got %v", lapNumbers)
+	}
+}
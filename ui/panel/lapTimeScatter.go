@@ -0,0 +1,418 @@
+// F1Gopher - Copyright (C) 2025 f1gopher
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package panel
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/AllenDang/giu"
+	"github.com/f1gopher/f1gopherlib"
+	"github.com/f1gopher/f1gopherlib/Messages"
+	"github.com/ungerik/go-cairo"
+)
+
+type scatterLap struct {
+	lap         int
+	seconds     float64
+	tire        Messages.TireType
+	stint       int
+	pitLap      bool
+	neutralised bool
+}
+
+type lapTimeScatterInfo struct {
+	color   color.RGBA
+	name    string
+	number  int
+	laps    []scatterLap
+	visible bool
+
+	// State for the lap currently being driven
+	lastLap            int
+	currentTire        Messages.TireType
+	currentStint       int
+	pitstops           int
+	currentPitLap      bool
+	currentNeutralised bool
+}
+
+type lapTimeScatter struct {
+	driverData map[int]*lapTimeScatterInfo
+	dataLock   sync.Mutex
+
+	totalLaps        int
+	neutralised      bool
+	hidePitAndSCLaps bool
+	showStintTrends  bool
+
+	visibleDriversSelect *gapperDriverDisplaySelectWidget
+
+	focus           *DriverFocus
+	drawnFocus      int
+	drawnComparison int
+
+	chart *chart
+	plot  *plot
+	yMax  float64
+}
+
+func CreateLapTimeScatter(focus *DriverFocus) Panel {
+	panel := &lapTimeScatter{
+		driverData: map[int]*lapTimeScatterInfo{},
+		focus:      focus,
+	}
+	panel.chart = createChart("Lap Times")
+	panel.chart.prepare = panel.prepareChart
+	panel.chart.drawForeground = panel.drawForeground
+	panel.chart.hover = panel.hover
+	panel.chart.x.majorTick = 1.0
+	panel.chart.y[0].majorTick = 1.0
+	panel.plot = panel.chart.plot
+	panel.visibleDriversSelect = &gapperDriverDisplaySelectWidget{
+		plot:    panel.plot,
+		drivers: []*gapperPlotInfo{},
+	}
+	return panel
+}
+
+func (l *lapTimeScatter) ProcessEventTime(data Messages.EventTime)                    {}
+func (l *lapTimeScatter) ProcessRaceControlMessages(data Messages.RaceControlMessage) {}
+func (l *lapTimeScatter) ProcessWeather(data Messages.Weather)                        {}
+func (l *lapTimeScatter) ProcessRadio(data Messages.Radio)                            {}
+func (l *lapTimeScatter) ProcessLocation(data Messages.Location)                      {}
+func (l *lapTimeScatter) ProcessTelemetry(data Messages.Telemetry)                    {}
+func (l *lapTimeScatter) Close()                                                      {}
+
+func (l *lapTimeScatter) Type() Type { return LapTimeScatter }
+
+func (l *lapTimeScatter) Init(dataSrc f1gopherlib.F1GopherLib, config PanelConfig) {
+	l.dataLock.Lock()
+	l.driverData = map[int]*lapTimeScatterInfo{}
+	l.dataLock.Unlock()
+
+	l.totalLaps = 0
+	l.neutralised = false
+	l.visibleDriversSelect.drivers = []*gapperPlotInfo{}
+	l.visibleDriversSelect.visibleCount = 0
+	l.chart.reset()
+}
+
+func (l *lapTimeScatter) ProcessDrivers(data Messages.Drivers) {
+	l.dataLock.Lock()
+	defer l.dataLock.Unlock()
+
+	for x := range data.Drivers {
+		driver := &lapTimeScatterInfo{
+			color:  data.Drivers[x].Color,
+			name:   data.Drivers[x].ShortName,
+			number: data.Drivers[x].Number,
+			laps:   []scatterLap{},
+		}
+		l.driverData[data.Drivers[x].Number] = driver
+
+		// Reuse the gapper driver selection, the visible flag is copied across when drawing
+		l.visibleDriversSelect.drivers = append(l.visibleDriversSelect.drivers, &gapperPlotInfo{
+			color: driver.color,
+			name:  driver.name,
+		})
+	}
+
+	sort.Slice(l.visibleDriversSelect.drivers, func(i, j int) bool {
+		return l.visibleDriversSelect.drivers[i].name < l.visibleDriversSelect.drivers[j].name
+	})
+
+	// Overlaying every driver is unreadable so default to just the first one
+	if len(l.visibleDriversSelect.drivers) > 0 {
+		l.visibleDriversSelect.drivers[0].visible = true
+		l.visibleDriversSelect.visibleCount = 1
+	}
+}
+
+func (l *lapTimeScatter) ProcessEvent(data Messages.Event) {
+	if l.totalLaps != data.TotalLaps {
+		l.totalLaps = data.TotalLaps
+		l.plot.refreshBackground()
+	}
+
+	l.neutralised = data.SafetyCar != Messages.Clear || data.TrackStatus == Messages.RedFlag
+
+	// Any lap that is driven while the safety car or a red flag is out isn't representative
+	if l.neutralised {
+		l.dataLock.Lock()
+		for _, driver := range l.driverData {
+			driver.currentNeutralised = true
+		}
+		l.dataLock.Unlock()
+	}
+}
+
+func (l *lapTimeScatter) ProcessTiming(data Messages.Timing) {
+	l.dataLock.Lock()
+	defer l.dataLock.Unlock()
+
+	driverInfo, exists := l.driverData[data.Number]
+	if !exists {
+		return
+	}
+
+	inPitlane := data.Location == Messages.Pitlane || data.Location == Messages.PitOut
+	if inPitlane {
+		driverInfo.currentPitLap = true
+	}
+
+	if data.Pitstops != driverInfo.pitstops {
+		driverInfo.pitstops = data.Pitstops
+		driverInfo.currentStint++
+	}
+
+	if driverInfo.currentTire == Messages.Unknown {
+		driverInfo.currentTire = data.Tire
+	}
+
+	// We don't get a lap time for the first lap so the last lap is always the one before the current lap
+	completedLap := data.Lap - 1
+	if data.LastLap == 0 || completedLap <= driverInfo.lastLap {
+		return
+	}
+
+	driverInfo.laps = append(driverInfo.laps, scatterLap{
+		lap:         completedLap,
+		seconds:     data.LastLap.Seconds(),
+		tire:        driverInfo.currentTire,
+		stint:       driverInfo.currentStint,
+		pitLap:      driverInfo.currentPitLap,
+		neutralised: driverInfo.currentNeutralised,
+	})
+	driverInfo.lastLap = completedLap
+
+	// Start tracking the next lap, if we are still in the pits then the next lap is an outlap
+	driverInfo.currentTire = data.Tire
+	driverInfo.currentPitLap = inPitlane
+	driverInfo.currentNeutralised = l.neutralised
+
+	l.plot.refreshBackground()
+}
+
+func (l *lapTimeScatter) Draw(width int, height int) []giu.Widget {
+	// Redraw when the focused drivers change so they are highlighted
+	if l.focus.changedSince(&l.drawnFocus, &l.drawnComparison) {
+		l.plot.refreshForeground()
+	}
+
+	return append([]giu.Widget{
+		giu.Row(append([]giu.Widget{
+			l.visibleDriversSelect,
+			giu.Checkbox("Hide Pit & SC Laps", &l.hidePitAndSCLaps).OnChange(func() {
+				l.plot.refreshBackground()
+			}),
+			giu.Checkbox("Stint Trends", &l.showStintTrends).OnChange(func() {
+				l.plot.refreshForeground()
+			}),
+		}, l.chart.toolbar()...)...),
+	}, l.chart.draw(width-16, height-38)...)
+}
+
+// visibleDrivers returns the drivers selected for display. Caller must hold the data lock.
+func (l *lapTimeScatter) visibleDrivers() []*lapTimeScatterInfo {
+	visible := map[string]bool{}
+	for _, driver := range l.visibleDriversSelect.drivers {
+		visible[driver.name] = driver.visible
+	}
+
+	result := []*lapTimeScatterInfo{}
+	for _, driver := range l.driverData {
+		driver.visible = visible[driver.name]
+		if driver.visible {
+			result = append(result, driver)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].name < result[j].name
+	})
+	return result
+}
+
+func (l *lapTimeScatter) isHidden(lap scatterLap) bool {
+	return l.hidePitAndSCLaps && (lap.pitLap || lap.neutralised)
+}
+
+func (l *lapTimeScatter) prepareChart() {
+	l.dataLock.Lock()
+	drivers := l.visibleDrivers()
+
+	// Work out the lap time range using only representative laps so a slow safety car lap doesn't squash
+	// everything else down to a flat line
+	fastest := math.MaxFloat64
+	slowest := 0.0
+	lastLap := l.totalLaps
+	l.chart.legend = []chartLegendEntry{}
+	for _, driver := range drivers {
+		l.chart.legend = append(l.chart.legend, chartLegendEntry{name: driver.name, color: driver.color})
+
+		for _, lap := range driver.laps {
+			lastLap = max(lastLap, lap.lap)
+
+			if lap.pitLap || lap.neutralised {
+				continue
+			}
+
+			fastest = math.Min(fastest, lap.seconds)
+			slowest = math.Max(slowest, lap.seconds)
+		}
+	}
+	l.dataLock.Unlock()
+
+	if fastest == math.MaxFloat64 {
+		l.chart.message = "Waiting for data..."
+		return
+	}
+
+	// Pad by a second either side and cap how slow a lap can be before it is pinned to the top of the chart
+	l.yMax = math.Min(math.Ceil(slowest)+1, fastest*1.15)
+	l.chart.message = ""
+	l.chart.setXRange(0, float64(lastLap+1))
+	l.chart.setYRange(0, math.Floor(fastest)-1, l.yMax)
+}
+
+func (l *lapTimeScatter) drawForeground(dc *cairo.Surface) {
+	l.dataLock.Lock()
+	defer l.dataLock.Unlock()
+
+	for _, driver := range l.visibleDrivers() {
+		// Bigger markers for the focused drivers
+		radius := 3.0
+		if driver.number == l.drawnFocus || driver.number == l.drawnComparison {
+			radius = 5.0
+		}
+
+		for _, lap := range driver.laps {
+			if l.isHidden(lap) {
+				continue
+			}
+
+			x, y := l.lapPosition(lap)
+
+			dc.NewPath()
+			dc.Arc(x, y, radius, 0, 2*math.Pi)
+			dc.SetSourceRGBA(floatColor(toRGBA(tireColor(lap.tire))))
+			if lap.pitLap || lap.neutralised {
+				// Draw none representative laps hollow
+				dc.Stroke()
+			} else {
+				dc.FillPreserve()
+				// Outline in the driver color so drivers on the same compound can be told apart
+				dc.SetSourceRGBA(floatColor(driver.color))
+				dc.Stroke()
+			}
+		}
+
+		if l.showStintTrends {
+			l.drawStintTrends(dc, driver)
+		}
+	}
+}
+
+// hover shows the details of the lap closest to the mouse
+func (l *lapTimeScatter) hover(xPos float64, yPos float64) string {
+	l.dataLock.Lock()
+	defer l.dataLock.Unlock()
+
+	// Only match laps within a few pixels of the mouse
+	closest := 6.0
+	result := ""
+	for _, driver := range l.visibleDrivers() {
+		for _, lap := range driver.laps {
+			if l.isHidden(lap) {
+				continue
+			}
+
+			x, y := l.lapPosition(lap)
+			distance := math.Hypot(x-xPos, y-yPos)
+			if distance < closest {
+				closest = distance
+				result = fmt.Sprintf("%s\nLap: %d\nLap Time: %s\nTire: %s",
+					driver.name,
+					lap.lap,
+					fmtDuration(time.Duration(lap.seconds*float64(time.Second))),
+					lap.tire.String())
+			}
+		}
+	}
+
+	return result
+}
+
+func (l *lapTimeScatter) drawStintTrends(dc *cairo.Surface, driver *lapTimeScatterInfo) {
+	stints := map[int][]scatterLap{}
+	for _, lap := range driver.laps {
+		if lap.pitLap || lap.neutralised {
+			continue
+		}
+		stints[lap.stint] = append(stints[lap.stint], lap)
+	}
+
+	dc.SetSourceRGBA(floatColor(driver.color))
+	for _, laps := range stints {
+		// Need a few laps for a trend to mean anything
+		if len(laps) < 3 {
+			continue
+		}
+
+		slope, intercept := lapTimeTrend(laps)
+		first := laps[0].lap
+		last := laps[len(laps)-1].lap
+
+		startX, startY := l.lapPosition(scatterLap{lap: first, seconds: intercept + slope*float64(first)})
+		endX, endY := l.lapPosition(scatterLap{lap: last, seconds: intercept + slope*float64(last)})
+		dc.MoveTo(startX, startY)
+		dc.LineTo(endX, endY)
+	}
+	dc.Stroke()
+}
+
+func (l *lapTimeScatter) lapPosition(lap scatterLap) (x float64, y float64) {
+	// Pin anything too slow to the top of the chart
+	return l.chart.xPos(float64(lap.lap)), l.chart.yPos(0, math.Min(lap.seconds, l.yMax))
+}
+
+// lapTimeTrend returns the least squares fit of lap time against lap number
+func lapTimeTrend(laps []scatterLap) (slope float64, intercept float64) {
+	var sumX, sumY, sumXY, sumXX float64
+	n := float64(len(laps))
+
+	for _, lap := range laps {
+		x := float64(lap.lap)
+		sumX += x
+		sumY += lap.seconds
+		sumXY += x * lap.seconds
+		sumXX += x * x
+	}
+
+	divisor := n*sumXX - sumX*sumX
+	if divisor == 0 {
+		return 0, sumY / n
+	}
+
+	slope = (n*sumXY - sumX*sumY) / divisor
+	intercept = (sumY - slope*sumX) / n
+	return slope, intercept
+}
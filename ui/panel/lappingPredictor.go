@@ -0,0 +1,318 @@
+// F1Gopher - Copyright (C) 2025 f1gopher
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package panel
+
+import (
+	"f1gopher/raceControl"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/f1gopher/f1gopherlib/Messages"
+)
+
+// Number of recent laps averaged for a drivers pace
+const paceLaps = 3
+
+// Laps slower than this compared to the drivers fastest recent lap are ignored for pace (traffic, mistakes...)
+const slowLapRatio = 1.1
+
+// How long a blue flag shown to a car means it is being lapped
+const blueFlagDuration = time.Minute
+
+type lapSample struct {
+	// Time since the start of the lap
+	offset   time.Duration
+	location location
+}
+
+type lappingDriver struct {
+	lap      int
+	lapStart time.Time
+	validLap bool
+	// The lap time can arrive after the lap number changes
+	completedLapValid bool
+	lastLapTime       time.Duration
+	recentLaps        []time.Duration
+	currentPath       []lapSample
+	lastPath          []lapSample
+}
+
+// pace returns the average of the recent laps or zero if there aren't any
+func (l *lappingDriver) pace() time.Duration {
+	if len(l.recentLaps) == 0 {
+		return 0
+	}
+
+	var total time.Duration
+	for _, lap := range l.recentLaps {
+		total += lap
+	}
+	return total / time.Duration(len(l.recentLaps))
+}
+
+func (l *lappingDriver) addLap(lapTime time.Duration) {
+	fastest := lapTime
+	for _, lap := range l.recentLaps {
+		fastest = min(fastest, lap)
+	}
+	if float64(lapTime) > float64(fastest)*slowLapRatio {
+		return
+	}
+
+	l.recentLaps = append(l.recentLaps, lapTime)
+	if len(l.recentLaps) > paceLaps {
+		l.recentLaps = l.recentLaps[1:]
+	}
+}
+
+// lappingPrediction is when and where the leader is expected to lap a car
+type lappingPrediction struct {
+	driverNumber int
+	// Number of the leaders laps until they catch the car
+	lapsToCatch float64
+	// The lap the leader will be on when they catch the car
+	leaderLap int
+	// Where on the track it will happen, from the path of the leaders last lap
+	location    location
+	hasLocation bool
+	// Blue flag shown to the car recently and the lap of the leader when the first one was shown
+	blueFlag     bool
+	blueFlagLap  int
+	predictedLap int
+}
+
+// blueFlagRecord is the lap the leader was on when a car was first shown a blue flag and the lap that was predicted
+type blueFlagRecord struct {
+	leaderLap    int
+	predictedLap int
+}
+
+// lappingPredictor predicts when the leader will lap the cars behind from the gap to the leader and the recent pace of
+// each driver. It is shared between the timing table that feeds it and the track map that marks where it will happen.
+type lappingPredictor struct {
+	event         Messages.Event
+	timing        map[int]Messages.Timing
+	drivers       map[int]*lappingDriver
+	blueFlags     map[int]time.Time
+	firstBlueFlag map[int]blueFlagRecord
+	lastUpdate    time.Time
+	lock          sync.Mutex
+}
+
+func CreateLappingPredictor() *lappingPredictor {
+	predictor := &lappingPredictor{}
+	predictor.Reset()
+	return predictor
+}
+
+func (l *lappingPredictor) Reset() {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.event = Messages.Event{}
+	l.timing = map[int]Messages.Timing{}
+	l.drivers = map[int]*lappingDriver{}
+	l.blueFlags = map[int]time.Time{}
+	l.firstBlueFlag = map[int]blueFlagRecord{}
+	l.lastUpdate = time.Time{}
+}
+
+func (l *lappingPredictor) ProcessEvent(data Messages.Event) {
+	l.lock.Lock()
+	l.event = data
+	l.lock.Unlock()
+}
+
+func (l *lappingPredictor) ProcessLocation(data Messages.Location) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	driver, exists := l.drivers[data.DriverNumber]
+	if !exists || driver.lapStart.IsZero() {
+		return
+	}
+
+	driver.currentPath = append(driver.currentPath, lapSample{
+		offset:   data.Timestamp.Sub(driver.lapStart),
+		location: location{x: data.X, y: data.Y},
+	})
+}
+
+func (l *lappingPredictor) ProcessTiming(data Messages.Timing) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.timing[data.Number] = data
+	l.lastUpdate = data.Timestamp
+
+	driver, exists := l.drivers[data.Number]
+	if !exists {
+		driver = &lappingDriver{lap: data.Lap}
+		l.drivers[data.Number] = driver
+	}
+
+	// Laps that include the pits or were under a safety car don't show the drivers pace
+	if data.Location != Messages.OnTrack || l.event.SafetyCar != Messages.Clear {
+		driver.validLap = false
+	}
+
+	if data.Lap != driver.lap {
+		driver.completedLapValid = driver.validLap
+		driver.lap = data.Lap
+		driver.lapStart = data.Timestamp
+		driver.validLap = data.Location == Messages.OnTrack
+		driver.lastPath = driver.currentPath
+		driver.currentPath = nil
+	}
+
+	if data.LastLap > 0 && data.LastLap != driver.lastLapTime {
+		driver.lastLapTime = data.LastLap
+		if driver.completedLapValid {
+			driver.addLap(data.LastLap)
+		}
+		driver.completedLapValid = false
+	}
+}
+
+func (l *lappingPredictor) ProcessRaceControlMessages(data Messages.RaceControlMessage) {
+	if data.Flag != Messages.BlueFlag {
+		return
+	}
+
+	msg := raceControl.Parse(data)
+	predictions := l.predictions()
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	for driverNumber := range l.timing {
+		if !msg.MentionsCar(driverNumber) {
+			continue
+		}
+
+		l.blueFlags[driverNumber] = data.Timestamp
+
+		// Remember what was predicted when the lapping actually happened
+		if _, exists := l.firstBlueFlag[driverNumber]; !exists {
+			predictedLap := 0
+			if prediction, exists := predictions[driverNumber]; exists {
+				predictedLap = prediction.leaderLap
+			}
+			l.firstBlueFlag[driverNumber] = blueFlagRecord{leaderLap: l.leader().Lap, predictedLap: predictedLap}
+		}
+	}
+}
+
+// leader returns the timing for the car in first place
+func (l *lappingPredictor) leader() Messages.Timing {
+	for _, timing := range l.timing {
+		if timing.Position == 1 {
+			return timing
+		}
+	}
+	return Messages.Timing{}
+}
+
+// predictions returns when the leader will catch each car that they are faster than
+func (l *lappingPredictor) predictions() map[int]lappingPrediction {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	result := map[int]lappingPrediction{}
+
+	if l.event.Type != Messages.Race && l.event.Type != Messages.Sprint {
+		return result
+	}
+
+	leader := l.leader()
+	leaderDriver, exists := l.drivers[leader.Number]
+	if !exists {
+		return result
+	}
+	leaderPace := leaderDriver.pace()
+	if leaderPace == 0 {
+		return result
+	}
+
+	// How far around the current lap the leader is
+	leaderProgress := 0.0
+	if !leaderDriver.lapStart.IsZero() {
+		leaderProgress = math.Min(float64(l.lastUpdate.Sub(leaderDriver.lapStart))/float64(leaderPace), 1)
+	}
+
+	for driverNumber, timing := range l.timing {
+		if driverNumber == leader.Number || timing.Location == Messages.Stopped || timing.Location == Messages.OutOfRace {
+			continue
+		}
+
+		prediction := lappingPrediction{driverNumber: driverNumber}
+		if blueFlag, exists := l.blueFlags[driverNumber]; exists && l.lastUpdate.Sub(blueFlag) < blueFlagDuration {
+			prediction.blueFlag = true
+		}
+		if first, exists := l.firstBlueFlag[driverNumber]; exists {
+			prediction.blueFlagLap = first.leaderLap
+			prediction.predictedLap = first.predictedLap
+		}
+
+		// Cars that have already been lapped don't have a gap to the leader
+		driver := l.drivers[driverNumber]
+		if timing.GapToLeader <= 0 || driver == nil {
+			if prediction.blueFlag {
+				result[driverNumber] = prediction
+			}
+			continue
+		}
+
+		pace := driver.pace()
+		closing := pace - leaderPace
+		if pace == 0 || closing <= 0 {
+			if prediction.blueFlag {
+				result[driverNumber] = prediction
+			}
+			continue
+		}
+
+		// The car is lapped when the leader is a whole lap ahead of them
+		prediction.lapsToCatch = math.Max(float64(leaderPace-timing.GapToLeader)/float64(closing), 0)
+		prediction.leaderLap = leader.Lap + int(leaderProgress+prediction.lapsToCatch)
+
+		if l.event.TotalLaps > 0 && prediction.leaderLap > l.event.TotalLaps {
+			if prediction.blueFlag {
+				result[driverNumber] = prediction
+			}
+			continue
+		}
+
+		// Use the path of the leaders last lap to find where they will be
+		_, fraction := math.Modf(leaderProgress + prediction.lapsToCatch)
+		if len(leaderDriver.lastPath) > 0 {
+			lapLength := leaderDriver.lastPath[len(leaderDriver.lastPath)-1].offset
+			target := time.Duration(fraction * float64(lapLength))
+			index := sort.Search(len(leaderDriver.lastPath), func(i int) bool {
+				return leaderDriver.lastPath[i].offset >= target
+			})
+			index = min(index, len(leaderDriver.lastPath)-1)
+			prediction.location = leaderDriver.lastPath[index].location
+			prediction.hasLocation = true
+		}
+
+		result[driverNumber] = prediction
+	}
+
+	return result
+}
@@ -0,0 +1,79 @@
+package panel
+
+import (
+	"testing"
+	"time"
+
+	"github.com/f1gopher/f1gopherlib/Messages"
+)
+
+func TestLappingPredictor(t *testing.T) {
+	start := time.Date(2024, 5, 26, 13, 0, 0, 0, time.UTC)
+	const laps = 6
+	const leaderLap = 90 * time.Second
+
+	tests := []struct {
+		name    string
+		lapTime time.Duration
+		// Lap started in the pits, zero for none
+		pitLap      int
+		pitLapTime  time.Duration
+		endLocation Messages.CarLocation
+		// Zero if the car shouldn't be caught
+		expectedLap int
+	}{
+		{name: "slower car", lapTime: 92 * time.Second, endLocation: Messages.OnTrack, expectedLap: 11},
+		{name: "faster car", lapTime: 89 * time.Second, endLocation: Messages.OnTrack},
+		{name: "pit stop lap ignored", lapTime: 92 * time.Second, pitLap: 4, pitLapTime: 100 * time.Second,
+			endLocation: Messages.OnTrack, expectedLap: 11},
+		{name: "retired", lapTime: 92 * time.Second, endLocation: Messages.Stopped},
+		{name: "out of the race", lapTime: 92 * time.Second, endLocation: Messages.OutOfRace},
+	}
+
+	for _, test := range tests {
+		predictor := CreateLappingPredictor()
+		predictor.ProcessEvent(Messages.Event{Type: Messages.Race, Status: Messages.Started, TotalLaps: 50})
+
+		// Each lap time arrives with the start of the next lap, the times change a little so every lap is new
+		for lap := 1; lap <= laps; lap++ {
+			timestamp := start.Add(time.Duration(lap) * leaderLap)
+			variation := time.Duration(lap) * time.Millisecond
+
+			leader := Messages.Timing{Timestamp: timestamp, Number: 1, Position: 1, Lap: lap, Location: Messages.OnTrack}
+			car := Messages.Timing{Timestamp: timestamp, Number: 16, Position: 2, Lap: lap, Location: Messages.OnTrack,
+				GapToLeader: 80 * time.Second}
+			if lap > 1 {
+				leader.LastLap = leaderLap + variation
+				car.LastLap = test.lapTime + variation
+				if lap-1 == test.pitLap {
+					car.LastLap = test.pitLapTime + variation
+				}
+			}
+			if lap == test.pitLap {
+				car.Location = Messages.Pitlane
+			}
+			if lap == laps {
+				car.Location = test.endLocation
+			}
+
+			predictor.ProcessTiming(leader)
+			predictor.ProcessTiming(car)
+		}
+
+		prediction, exists := predictor.predictions()[16]
+		if test.expectedLap == 0 {
+			if exists {
+				t.Errorf("%s: expected no prediction, got %+v", test.name, prediction)
+			}
+			continue
+		}
+
+		if !exists {
+			t.Errorf("%s: expected a prediction", test.name)
+			continue
+		}
+		if prediction.leaderLap != test.expectedLap {
+			t.Errorf("%s: expected to be lapped on lap %d, got %d", test.name, test.expectedLap, prediction.leaderLap)
+		}
+	}
+}
@@ -0,0 +1,437 @@
+// F1Gopher - Copyright (C) 2025 f1gopher
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package panel
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/AllenDang/giu"
+	"github.com/f1gopher/f1gopherlib"
+	"github.com/f1gopher/f1gopherlib/Messages"
+	"golang.org/x/image/colornames"
+)
+
+// Minimum number of representative laps in a stint for it to count as a long run
+const minLongRunLaps = 5
+
+// Laps slower than this compared to the fastest lap in the stint are cool down or traffic laps
+const longRunSlowLapRatio = 1.07
+
+// Laps further than this many (scaled) median absolute deviations from the median are outliers
+const longRunOutlierLimit = 3.0
+
+type stintLap struct {
+	lap     int
+	lapTime time.Duration
+	tireAge int
+}
+
+type longRunStint struct {
+	driverNumber int
+	compound     Messages.TireType
+	laps         []stintLap
+	finished     bool
+}
+
+// longRunStats is the pace over the representative laps of a stint
+type longRunStats struct {
+	laps    int
+	average time.Duration
+	// Change in lap time for each lap the tire gets older
+	degradation time.Duration
+	// Standard deviation of the lap times
+	consistency time.Duration
+}
+
+// analyseStint filters out the laps that don't show the drivers pace and works out the stats for the rest
+func analyseStint(laps []stintLap) longRunStats {
+	if len(laps) == 0 {
+		return longRunStats{}
+	}
+
+	times := make([]float64, len(laps))
+	fastest := math.MaxFloat64
+	for x := range laps {
+		times[x] = laps[x].lapTime.Seconds()
+		fastest = math.Min(fastest, times[x])
+	}
+
+	median := medianOf(times)
+	deviations := make([]float64, len(times))
+	for x := range times {
+		deviations[x] = math.Abs(times[x] - median)
+	}
+	// Scaled so it matches the standard deviation for normally distributed lap times
+	mad := medianOf(deviations) * 1.4826
+
+	representative := make([]stintLap, 0, len(laps))
+	for x := range laps {
+		if times[x] > fastest*longRunSlowLapRatio {
+			continue
+		}
+		if mad > 0 && math.Abs(times[x]-median) > longRunOutlierLimit*mad {
+			continue
+		}
+		representative = append(representative, laps[x])
+	}
+
+	stats := longRunStats{laps: len(representative)}
+	if len(representative) == 0 {
+		return stats
+	}
+
+	var total, totalAge float64
+	for _, lap := range representative {
+		total += lap.lapTime.Seconds()
+		totalAge += float64(lap.tireAge)
+	}
+	average := total / float64(len(representative))
+	averageAge := totalAge / float64(len(representative))
+
+	// Least squares fit of lap time against tire age for the degradation
+	var variance, covariance, ageVariance float64
+	for _, lap := range representative {
+		diff := lap.lapTime.Seconds() - average
+		ageDiff := float64(lap.tireAge) - averageAge
+		variance += diff * diff
+		covariance += diff * ageDiff
+		ageVariance += ageDiff * ageDiff
+	}
+
+	stats.average = time.Duration(average * float64(time.Second))
+	stats.consistency = time.Duration(math.Sqrt(variance/float64(len(representative))) * float64(time.Second))
+	if ageVariance > 0 {
+		stats.degradation = time.Duration(covariance / ageVariance * float64(time.Second))
+	}
+
+	return stats
+}
+
+func medianOf(values []float64) float64 {
+	sorted := append([]float64{}, values...)
+	sort.Float64s(sorted)
+
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+	return sorted[middle]
+}
+
+type longRunDriver struct {
+	name  string
+	team  string
+	color color.RGBA
+
+	lap      int
+	validLap bool
+	// The lap time can arrive after the lap number changes
+	completedLapValid bool
+	lastLapTime       time.Duration
+	current           *longRunStint
+}
+
+type longRunRow struct {
+	stint  *longRunStint
+	driver longRunDriver
+	stats  longRunStats
+}
+
+// teamLongRun is a teams quickest long run on a compound
+type teamLongRun struct {
+	row      longRunRow
+	position int
+	gap      time.Duration
+}
+
+type longRuns struct {
+	focus *DriverFocus
+
+	isPractice bool
+	event      Messages.Event
+	drivers    map[int]*longRunDriver
+	stints     []*longRunStint
+	lock       sync.Mutex
+
+	compounds        []string
+	selectedCompound int32
+}
+
+func CreateLongRuns(focus *DriverFocus) Panel {
+	return &longRuns{
+		focus:     focus,
+		drivers:   map[int]*longRunDriver{},
+		compounds: []string{"All", Messages.Soft.String(), Messages.Medium.String(), Messages.Hard.String()},
+	}
+}
+
+func (l *longRuns) ProcessEventTime(data Messages.EventTime)                    {}
+func (l *longRuns) ProcessRaceControlMessages(data Messages.RaceControlMessage) {}
+func (l *longRuns) ProcessWeather(data Messages.Weather)                        {}
+func (l *longRuns) ProcessRadio(data Messages.Radio)                            {}
+func (l *longRuns) ProcessLocation(data Messages.Location)                      {}
+func (l *longRuns) ProcessTelemetry(data Messages.Telemetry)                    {}
+func (l *longRuns) Close()                                                      {}
+
+func (l *longRuns) Type() Type { return LongRuns }
+
+func (l *longRuns) Init(dataSrc f1gopherlib.F1GopherLib, config PanelConfig) {
+	session := dataSrc.Session()
+	l.isPractice = session == Messages.Practice1Session || session == Messages.Practice2Session ||
+		session == Messages.Practice3Session || session == Messages.PreSeasonSession
+
+	l.lock.Lock()
+	l.event = Messages.Event{}
+	l.drivers = map[int]*longRunDriver{}
+	l.stints = nil
+	l.lock.Unlock()
+}
+
+func (l *longRuns) ProcessDrivers(data Messages.Drivers) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	for _, driver := range data.Drivers {
+		existing, exists := l.drivers[driver.Number]
+		if !exists {
+			existing = &longRunDriver{}
+			l.drivers[driver.Number] = existing
+		}
+		existing.name = driver.ShortName
+		existing.team = driver.Team
+		existing.color = driver.Color
+	}
+}
+
+func (l *longRuns) ProcessEvent(data Messages.Event) {
+	l.lock.Lock()
+	l.event = data
+	l.lock.Unlock()
+}
+
+func (l *longRuns) ProcessTiming(data Messages.Timing) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	driver, exists := l.drivers[data.Number]
+	if !exists {
+		return
+	}
+
+	// A stint ends when the car comes back into the pits
+	inPits := data.Location == Messages.Pitlane || data.Location == Messages.PitOut ||
+		data.Location == Messages.Stopped || data.Location == Messages.OutOfRace
+	if inPits && driver.current != nil {
+		driver.current.finished = true
+		driver.current = nil
+	}
+
+	// In and out laps and laps under yellow flags don't show the drivers pace
+	if data.Location != Messages.OnTrack || l.event.SafetyCar != Messages.Clear {
+		driver.validLap = false
+	}
+
+	if data.Lap != driver.lap {
+		driver.completedLapValid = driver.validLap
+		driver.lap = data.Lap
+		driver.validLap = data.Location == Messages.OnTrack
+	}
+
+	if data.LastLap > 0 && data.LastLap != driver.lastLapTime {
+		driver.lastLapTime = data.LastLap
+
+		if driver.completedLapValid {
+			if driver.current == nil || driver.current.compound != data.Tire {
+				if driver.current != nil {
+					driver.current.finished = true
+				}
+				driver.current = &longRunStint{driverNumber: data.Number, compound: data.Tire}
+				l.stints = append(l.stints, driver.current)
+			}
+
+			driver.current.laps = append(driver.current.laps, stintLap{
+				lap:     data.Lap - 1,
+				lapTime: data.LastLap,
+				tireAge: data.LapsOnTire,
+			})
+		}
+		driver.completedLapValid = false
+	}
+}
+
+// longRunRows returns the stints with enough representative laps to be a long run for the selected compound
+func (l *longRuns) longRunRows() []longRunRow {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	rows := []longRunRow{}
+	for _, stint := range l.stints {
+		if l.selectedCompound > 0 && stint.compound.String() != l.compounds[l.selectedCompound] {
+			continue
+		}
+
+		stats := analyseStint(stint.laps)
+		if stats.laps < minLongRunLaps {
+			continue
+		}
+
+		copied := *stint
+		copied.laps = append([]stintLap{}, stint.laps...)
+		rows = append(rows, longRunRow{stint: &copied, driver: *l.drivers[stint.driverNumber], stats: stats})
+	}
+
+	// Grouped by compound so the pace is only compared on the same tires
+	sort.SliceStable(rows, func(i, j int) bool {
+		if rows[i].stint.compound != rows[j].stint.compound {
+			return rows[i].stint.compound < rows[j].stint.compound
+		}
+		return rows[i].stats.average < rows[j].stats.average
+	})
+	return rows
+}
+
+// teamLongRuns ranks each teams quickest long run against the other teams on the same compound, the rows
+// must be in the order from longRunRows
+func teamLongRuns(rows []longRunRow) []teamLongRun {
+	teams := []teamLongRun{}
+	leaders := map[Messages.TireType]time.Duration{}
+	teamsSeen := map[Messages.TireType]map[string]bool{}
+	for _, row := range rows {
+		compound := row.stint.compound
+		if teamsSeen[compound] == nil {
+			teamsSeen[compound] = map[string]bool{}
+			leaders[compound] = row.stats.average
+		}
+		if teamsSeen[compound][row.driver.team] {
+			continue
+		}
+		teamsSeen[compound][row.driver.team] = true
+
+		teams = append(teams, teamLongRun{
+			row:      row,
+			position: len(teamsSeen[compound]),
+			gap:      row.stats.average - leaders[compound],
+		})
+	}
+	return teams
+}
+
+func (l *longRuns) Draw(width int, height int) []giu.Widget {
+	if !l.isPractice {
+		return []giu.Widget{giu.Label("Long runs are only analysed in practice sessions")}
+	}
+
+	rows := l.longRunRows()
+
+	stintRows := make([]*giu.TableRowWidget, 0, len(rows))
+	for _, row := range rows {
+		driverNumber := row.stint.driverNumber
+
+		state := ""
+		if !row.stint.finished {
+			state = "Running"
+		}
+
+		tableRow := giu.TableRow(
+			giu.Selectable(row.driver.name).
+				Flags(giu.SelectableFlagsSpanAllColumns).
+				OnClick(func() { l.focus.Select(driverNumber) }),
+			giu.Style().SetColor(giu.StyleColorText, tireColor(row.stint.compound)).To(giu.Label(row.stint.compound.String())),
+			giu.Label(fmt.Sprintf("%d-%d", row.stint.laps[0].lap, row.stint.laps[len(row.stint.laps)-1].lap)),
+			giu.Label(fmt.Sprintf("%d/%d", row.stats.laps, len(row.stint.laps))),
+			giu.Label(fmtDuration(row.stats.average)),
+			giu.Style().SetColor(giu.StyleColorText, degradationColor(row.stats.degradation)).To(
+				giu.Label(fmt.Sprintf("%+.3fs", row.stats.degradation.Seconds()))),
+			giu.Label(fmt.Sprintf("%.3fs", row.stats.consistency.Seconds())),
+			giu.Label(state),
+		)
+		if focusColor, focused := l.focus.highlight(driverNumber); focused {
+			tableRow = tableRow.BgColor(focusColor)
+		}
+		stintRows = append(stintRows, tableRow)
+	}
+
+	teamRows := []*giu.TableRowWidget{}
+	for _, team := range teamLongRuns(rows) {
+		gap := ""
+		if team.position > 1 {
+			gap = fmt.Sprintf("+%.3f", team.gap.Seconds())
+		}
+
+		teamRows = append(teamRows, giu.TableRow(
+			giu.Label(fmt.Sprintf("%d", team.position)),
+			giu.Style().SetColor(giu.StyleColorText, team.row.driver.color).To(giu.Label(team.row.driver.team)),
+			giu.Label(team.row.driver.name),
+			giu.Style().SetColor(giu.StyleColorText, tireColor(team.row.stint.compound)).To(giu.Label(team.row.stint.compound.String())),
+			giu.Label(fmtDuration(team.row.stats.average)),
+			giu.Label(gap),
+		))
+	}
+
+	tableHeight := float32(height - 40)
+	stintWidth := float32(width-24) * 0.55
+
+	return []giu.Widget{
+		giu.Row(
+			giu.Combo("Compound", l.compounds[l.selectedCompound], l.compounds, &l.selectedCompound).Size(100),
+			giu.Label(fmt.Sprintf("Stints of at least %d representative laps", minLongRunLaps)),
+		),
+		giu.Row(
+			giu.Child().Size(stintWidth, tableHeight).Layout(
+				giu.Table().FastMode(true).Flags(giu.TableFlagsResizable|giu.TableFlagsSizingFixedSame|giu.TableFlagsScrollY).
+					Columns(
+						giu.TableColumn("Drv").InnerWidthOrWeight(35),
+						giu.TableColumn("Tire").InnerWidthOrWeight(50),
+						giu.TableColumn("Laps").InnerWidthOrWeight(45),
+						giu.TableColumn("Used").InnerWidthOrWeight(40),
+						giu.TableColumn("Average").InnerWidthOrWeight(timeWidth),
+						giu.TableColumn("Deg/Lap").InnerWidthOrWeight(60),
+						giu.TableColumn("Std Dev").InnerWidthOrWeight(55),
+						giu.TableColumn("").InnerWidthOrWeight(50),
+					).
+					Rows(stintRows...),
+			),
+			giu.Child().Size(float32(width-24)-stintWidth, tableHeight).Layout(
+				giu.Label("Team Long Run Pace"),
+				giu.Table().FastMode(true).Flags(giu.TableFlagsResizable|giu.TableFlagsSizingFixedSame|giu.TableFlagsScrollY).
+					Columns(
+						giu.TableColumn("Pos").InnerWidthOrWeight(25),
+						giu.TableColumn("Team").InnerWidthOrWeight(120),
+						giu.TableColumn("Drv").InnerWidthOrWeight(35),
+						giu.TableColumn("Tire").InnerWidthOrWeight(50),
+						giu.TableColumn("Average").InnerWidthOrWeight(timeWidth),
+						giu.TableColumn("Gap").InnerWidthOrWeight(55),
+					).
+					Rows(teamRows...),
+			),
+		),
+	}
+}
+
+func degradationColor(degradation time.Duration) color.RGBA {
+	switch {
+	case degradation > 100*time.Millisecond:
+		return colornames.Red
+	case degradation > 50*time.Millisecond:
+		return colornames.Yellow
+	}
+	return colornames.Green
+}
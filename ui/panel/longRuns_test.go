@@ -0,0 +1,125 @@
+package panel
+
+import (
+	"testing"
+	"time"
+
+	"github.com/f1gopher/f1gopherlib/Messages"
+)
+
+type longRunSegment struct {
+	compound Messages.TireType
+	laps     int
+	lapTime  time.Duration
+}
+
+// driveLongRuns sends the timing for a driver doing each segment in turn with a pit stop between them
+func driveLongRuns(l *longRuns, number int, segments []longRunSegment) {
+	lap := 0
+	var lastLap time.Duration
+	for s, segment := range segments {
+		for x := 0; x < segment.laps; x++ {
+			lap++
+			location := Messages.OnTrack
+			if s > 0 && x == 0 {
+				location = Messages.PitOut
+			}
+
+			timing := Messages.Timing{Number: number, Lap: lap, Location: location, Tire: segment.compound,
+				LapsOnTire: x, LastLap: lastLap}
+			l.ProcessTiming(timing)
+			// Into the pits at the end of the in lap
+			if s < len(segments)-1 && x == segment.laps-1 {
+				timing.Location = Messages.Pitlane
+				l.ProcessTiming(timing)
+			}
+			// The times change a little so every lap is new
+			lastLap = segment.lapTime + time.Duration(x)*time.Millisecond
+		}
+	}
+	l.ProcessTiming(Messages.Timing{Number: number, Lap: lap + 1, Location: Messages.OnTrack,
+		Tire: segments[len(segments)-1].compound, LapsOnTire: segments[len(segments)-1].laps, LastLap: lastLap})
+}
+
+func TestLongRuns(t *testing.T) {
+	l := CreateLongRuns(nil).(*longRuns)
+	l.ProcessDrivers(Messages.Drivers{Drivers: []Messages.DriverInfo{
+		{Number: 1, ShortName: "VER", Team: "Red Bull Racing"},
+		{Number: 11, ShortName: "PER", Team: "Red Bull Racing"},
+		{Number: 16, ShortName: "LEC", Team: "Ferrari"},
+		{Number: 44, ShortName: "HAM", Team: "Mercedes"},
+	}})
+	l.ProcessEvent(Messages.Event{Type: Messages.Practice2, SafetyCar: Messages.Clear})
+
+	driveLongRuns(l, 1, []longRunSegment{{Messages.Soft, 8, 89500 * time.Millisecond}})
+	driveLongRuns(l, 11, []longRunSegment{{Messages.Medium, 8, 89800 * time.Millisecond}})
+	driveLongRuns(l, 16, []longRunSegment{
+		{Messages.Soft, 8, 90 * time.Second},
+		{Messages.Medium, 8, 91 * time.Second},
+	})
+	// The soft run is too short to count
+	driveLongRuns(l, 44, []longRunSegment{
+		{Messages.Soft, 5, 89 * time.Second},
+		{Messages.Hard, 8, 92 * time.Second},
+	})
+
+	tests := []struct {
+		name     string
+		compound int32
+		drivers  []int
+		// The in and out laps aren't part of the run
+		laps []int
+		// The position and gap to the quickest team on the same compound for each teams quickest run
+		teams []teamLongRun
+	}{
+		{
+			name:     "all",
+			compound: 0,
+			drivers:  []int{1, 16, 11, 16, 44},
+			laps:     []int{8, 7, 8, 7, 7},
+			teams: []teamLongRun{
+				{position: 1}, {position: 2, gap: 500 * time.Millisecond},
+				{position: 1}, {position: 2, gap: 1200 * time.Millisecond},
+				{position: 1},
+			},
+		},
+		{
+			name:     "medium",
+			compound: 2,
+			drivers:  []int{11, 16},
+			laps:     []int{8, 7},
+			teams:    []teamLongRun{{position: 1}, {position: 2, gap: 1200 * time.Millisecond}},
+		},
+	}
+
+	for _, test := range tests {
+		l.selectedCompound = test.compound
+		rows := l.longRunRows()
+
+		if len(rows) != len(test.drivers) {
+			t.Errorf("%s: expected %d long runs, got %d", test.name, len(test.drivers), len(rows))
+			continue
+		}
+		for x, row := range rows {
+			if row.stint.driverNumber != test.drivers[x] {
+				t.Errorf("%s: expected car %d at %d, got %d", test.name, test.drivers[x], x, row.stint.driverNumber)
+			}
+			if row.stats.laps != test.laps[x] {
+				t.Errorf("%s: expected %d laps for car %d, got %d", test.name, test.laps[x], row.stint.driverNumber, row.stats.laps)
+			}
+		}
+
+		teams := teamLongRuns(rows)
+		if len(teams) != len(test.teams) {
+			t.Errorf("%s: expected %d team runs, got %d", test.name, len(test.teams), len(teams))
+			continue
+		}
+		for x, team := range teams {
+			gapError := team.gap - test.teams[x].gap
+			if team.position != test.teams[x].position || gapError < -10*time.Millisecond || gapError > 10*time.Millisecond {
+				t.Errorf("%s: expected %s to be P%d +%s, got P%d +%s", test.name, team.row.driver.team,
+					test.teams[x].position, test.teams[x].gap, team.position, team.gap)
+			}
+		}
+	}
+}
@@ -0,0 +1,154 @@
+// F1Gopher - Copyright (C) 2025 f1gopher
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package panel
+
+import (
+	"f1gopher/mqttPublisher"
+	"fmt"
+	"sync"
+
+	"github.com/AllenDang/giu"
+	"github.com/f1gopher/f1gopherlib"
+	"github.com/f1gopher/f1gopherlib/Messages"
+	"golang.org/x/image/colornames"
+)
+
+// mqtt publishes the session to an MQTT broker when it is enabled in the options and shows what has been published
+type mqtt struct {
+	publisher *mqttPublisher.Publisher
+	broker    string
+	status    string
+	lock      sync.Mutex
+}
+
+func CreateMQTT() Panel {
+	return &mqtt{}
+}
+
+func (m *mqtt) ProcessWeather(data Messages.Weather)     {}
+func (m *mqtt) ProcessRadio(data Messages.Radio)         {}
+func (m *mqtt) ProcessLocation(data Messages.Location)   {}
+func (m *mqtt) ProcessTelemetry(data Messages.Telemetry) {}
+func (m *mqtt) ProcessEventTime(data Messages.EventTime) {}
+
+func (m *mqtt) Type() Type { return MQTT }
+
+func (m *mqtt) Init(dataSrc f1gopherlib.F1GopherLib, config PanelConfig) {
+	settings, enabled := config.MQTTSettings()
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.publisher = nil
+	m.broker = settings.Broker
+	m.status = ""
+
+	if !enabled {
+		m.status = "Disabled, turn on the MQTT publisher in the options"
+		return
+	}
+
+	publisher, err := mqttPublisher.Connect(settings)
+	if err != nil {
+		m.status = err.Error()
+		return
+	}
+	publisher.Reset(dataSrc.Name(), dataSrc.Session())
+	m.publisher = publisher
+}
+
+func (m *mqtt) Close() {
+	m.lock.Lock()
+	publisher := m.publisher
+	m.publisher = nil
+	m.lock.Unlock()
+
+	if publisher != nil {
+		publisher.Close()
+	}
+}
+
+func (m *mqtt) currentPublisher() *mqttPublisher.Publisher {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return m.publisher
+}
+
+func (m *mqtt) ProcessDrivers(data Messages.Drivers) {
+	if publisher := m.currentPublisher(); publisher != nil {
+		publisher.ProcessDrivers(data)
+	}
+}
+
+func (m *mqtt) ProcessTiming(data Messages.Timing) {
+	if publisher := m.currentPublisher(); publisher != nil {
+		publisher.ProcessTiming(data)
+	}
+}
+
+func (m *mqtt) ProcessEvent(data Messages.Event) {
+	if publisher := m.currentPublisher(); publisher != nil {
+		publisher.ProcessEvent(data)
+	}
+}
+
+func (m *mqtt) ProcessRaceControlMessages(data Messages.RaceControlMessage) {
+	if publisher := m.currentPublisher(); publisher != nil {
+		publisher.ProcessRaceControlMessage(data)
+	}
+}
+
+func (m *mqtt) Draw(width int, height int) []giu.Widget {
+	m.lock.Lock()
+	publisher := m.publisher
+	status := m.status
+	broker := m.broker
+	m.lock.Unlock()
+
+	statusColor := colornames.Red
+	var topics []mqttPublisher.Topic
+	if publisher != nil {
+		topics = publisher.Topics()
+
+		if publisher.IsConnected() {
+			status = fmt.Sprintf("Connected to %s", broker)
+			statusColor = colornames.Green
+		} else {
+			status = fmt.Sprintf("Connecting to %s...", broker)
+			statusColor = colornames.Yellow
+		}
+	}
+
+	rows := make([]*giu.TableRowWidget, 0, len(topics))
+	for _, topic := range topics {
+		rows = append(rows, giu.TableRow(
+			giu.Label(topic.Name),
+			giu.Label(topic.Value),
+		))
+	}
+
+	return []giu.Widget{
+		giu.Style().SetColor(giu.StyleColorText, statusColor).To(giu.Label(status)),
+		giu.Table().FastMode(true).Flags(giu.TableFlagsResizable|giu.TableFlagsSizingFixedSame|giu.TableFlagsScrollY).
+			Size(float32(width-16), float32(height-40)).
+			Columns(
+				giu.TableColumn("Retained Topic").InnerWidthOrWeight(300),
+				giu.TableColumn("Value").InnerWidthOrWeight(250),
+			).
+			Rows(rows...),
+	}
+}
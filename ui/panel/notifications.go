@@ -0,0 +1,87 @@
+// F1Gopher - Copyright (C) 2025 f1gopher
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package panel
+
+import (
+	"image/color"
+	"sync"
+	"time"
+
+	"github.com/AllenDang/giu"
+)
+
+// How long a notification is shown for
+const notificationDuration = 10 * time.Second
+
+// Older notifications are dropped when there are more than this
+const maxNotifications = 5
+
+type notification struct {
+	text      string
+	color     color.RGBA
+	createdAt time.Time
+}
+
+// Notifications are short messages shown on top of the panels for a few seconds. Any panel can add one and the data
+// view draws them.
+type Notifications struct {
+	items []notification
+	lock  sync.Mutex
+}
+
+func CreateNotifications() *Notifications {
+	return &Notifications{}
+}
+
+func (n *Notifications) Reset() {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	n.items = nil
+}
+
+// Notify shows the text until it expires. The time it is shown for is real time rather than session time so it is
+// the same for replays.
+func (n *Notifications) Notify(text string, textColor color.RGBA) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	n.items = append(n.items, notification{text: text, color: textColor, createdAt: time.Now()})
+	if len(n.items) > maxNotifications {
+		n.items = n.items[len(n.items)-maxNotifications:]
+	}
+}
+
+// Widgets returns a label for each notification that hasn't expired, newest first
+func (n *Notifications) Widgets() []giu.Widget {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	now := time.Now()
+	current := n.items[:0]
+	for _, item := range n.items {
+		if now.Sub(item.createdAt) < notificationDuration {
+			current = append(current, item)
+		}
+	}
+	n.items = current
+
+	widgets := make([]giu.Widget, 0, len(n.items))
+	for x := len(n.items) - 1; x >= 0; x-- {
+		widgets = append(widgets, giu.Style().SetColor(giu.StyleColorText, n.items[x].color).To(giu.Label(n.items[x].text)))
+	}
+	return widgets
+}
@@ -0,0 +1,175 @@
+// F1Gopher - Copyright (C) 2025 f1gopher
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package panel
+
+import (
+	"f1gopher/oscOutput"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/AllenDang/giu"
+	"github.com/f1gopher/f1gopherlib"
+	"github.com/f1gopher/f1gopherlib/Messages"
+	"golang.org/x/image/colornames"
+)
+
+// osc sends the session to broadcast graphics software as OSC messages when it is enabled in the options and shows
+// what has been sent
+type osc struct {
+	battles *battleDetector
+	output  *oscOutput.Output
+	status  string
+	lock    sync.Mutex
+}
+
+func CreateOSC(battles *battleDetector) Panel {
+	return &osc{battles: battles}
+}
+
+func (o *osc) ProcessWeather(data Messages.Weather)                        {}
+func (o *osc) ProcessRadio(data Messages.Radio)                            {}
+func (o *osc) ProcessLocation(data Messages.Location)                      {}
+func (o *osc) ProcessTelemetry(data Messages.Telemetry)                    {}
+func (o *osc) ProcessRaceControlMessages(data Messages.RaceControlMessage) {}
+
+func (o *osc) Type() Type { return OSC }
+
+func (o *osc) Init(dataSrc f1gopherlib.F1GopherLib, config PanelConfig) {
+	settings, enabled := config.OSCSettings()
+
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	o.output = nil
+	o.status = ""
+
+	if !enabled {
+		o.status = "Disabled, turn on the OSC output in the options"
+		return
+	}
+
+	output, err := oscOutput.Start(settings, o.currentBattles)
+	if err != nil {
+		o.status = err.Error()
+		return
+	}
+	output.Reset(dataSrc.Name(), dataSrc.Session())
+	o.output = output
+}
+
+func (o *osc) Close() {
+	o.lock.Lock()
+	output := o.output
+	o.output = nil
+	o.lock.Unlock()
+
+	if output != nil {
+		output.Close()
+	}
+}
+
+// currentBattles is called by the output before each send so it doesn't matter which panel gets the timing first
+func (o *osc) currentBattles() []oscOutput.Battle {
+	current, _ := o.battles.currentBattles()
+
+	battles := make([]oscOutput.Battle, len(current))
+	for x := range current {
+		battles[x] = oscOutput.Battle{
+			Drivers: current[x].drivers,
+			Spread:  current[x].spread,
+			Trend:   current[x].trend().String(),
+		}
+	}
+	return battles
+}
+
+func (o *osc) currentOutput() *oscOutput.Output {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	return o.output
+}
+
+func (o *osc) ProcessDrivers(data Messages.Drivers) {
+	if output := o.currentOutput(); output != nil {
+		output.ProcessDrivers(data)
+	}
+}
+
+func (o *osc) ProcessTiming(data Messages.Timing) {
+	if output := o.currentOutput(); output != nil {
+		output.ProcessTiming(data)
+	}
+}
+
+func (o *osc) ProcessEvent(data Messages.Event) {
+	if output := o.currentOutput(); output != nil {
+		output.ProcessEvent(data)
+	}
+}
+
+func (o *osc) ProcessEventTime(data Messages.EventTime) {
+	if output := o.currentOutput(); output != nil {
+		output.ProcessEventTime(data)
+	}
+}
+
+func (o *osc) Draw(width int, height int) []giu.Widget {
+	o.lock.Lock()
+	output := o.output
+	status := o.status
+	o.lock.Unlock()
+
+	statusColor := colornames.Red
+	var values []oscOutput.Message
+	if output != nil {
+		values = output.Values()
+
+		sent, err := output.Stats()
+		status = fmt.Sprintf("Sending to %s, listening on %s, %d messages sent",
+			strings.Join(output.Targets(), ", "), output.LocalAddress(), sent)
+		statusColor = colornames.Green
+		if err != nil {
+			status += fmt.Sprintf(", last error: %s", err)
+			statusColor = colornames.Yellow
+		}
+	}
+
+	rows := make([]*giu.TableRowWidget, 0, len(values))
+	for _, value := range values {
+		args := make([]string, len(value.Args))
+		for x, arg := range value.Args {
+			args[x] = fmt.Sprintf("%v", arg)
+		}
+
+		rows = append(rows, giu.TableRow(
+			giu.Label(value.Address),
+			giu.Label(strings.Join(args, ", ")),
+		))
+	}
+
+	return []giu.Widget{
+		giu.Style().SetColor(giu.StyleColorText, statusColor).To(giu.Label(status)),
+		giu.Table().FastMode(true).Flags(giu.TableFlagsResizable|giu.TableFlagsSizingFixedSame|giu.TableFlagsScrollY).
+			Size(float32(width-16), float32(height-40)).
+			Columns(
+				giu.TableColumn("Address").InnerWidthOrWeight(200),
+				giu.TableColumn("Arguments").InnerWidthOrWeight(500),
+			).
+			Rows(rows...),
+	}
+}
@@ -0,0 +1,265 @@
+// F1Gopher - Copyright (C) 2025 f1gopher
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package panel
+
+import (
+	"f1gopher/raceControl"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/f1gopher/f1gopherlib/Messages"
+)
+
+type positionChangeType int
+
+const (
+	OnTrackOvertake positionChangeType = iota
+	PitCycle
+	Retirement
+	Penalty
+)
+
+func (p positionChangeType) String() string {
+	return [...]string{"Overtake", "Pit Cycle", "Retirement", "Penalty"}[p]
+}
+
+// positionChange is one driver passing another
+type positionChange struct {
+	timestamp  time.Time
+	lap        int
+	changeType positionChangeType
+	gainer     int
+	loser      int
+	// New position of the driver who gained
+	position int
+	// Where on the track an on track overtake happened
+	location location
+}
+
+// pendingChange is a position change for one driver waiting for the other drivers involved to be updated
+type pendingChange struct {
+	driver    int
+	from      int
+	to        int
+	remaining int
+	timestamp time.Time
+}
+
+func (p *pendingChange) gained() bool {
+	return p.to < p.from
+}
+
+// How long to wait for the other drivers involved in a position change to be updated
+const positionChangeWindow = 10 * time.Second
+
+// How long after a penalty message a position change can be caused by it
+const penaltyWindow = 5 * time.Minute
+
+// Cars further apart than this (in location units, decimetres) can't have passed each other on track
+const overtakeDistance = 1000.0
+
+// overtakeDetector pairs up the timing position changes into drivers passing each other and works out why.
+// It is shared between the overtakes panel that feeds it and the track map that draws where the overtakes were.
+type overtakeDetector struct {
+	event     Messages.Event
+	timing    map[int]Messages.Timing
+	locations map[int]Messages.Location
+	penalties map[int]time.Time
+	pending   []*pendingChange
+	changes   []positionChange
+	lock      sync.Mutex
+}
+
+func CreateOvertakeDetector() *overtakeDetector {
+	detector := &overtakeDetector{}
+	detector.Reset()
+	return detector
+}
+
+func (o *overtakeDetector) Reset() {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	o.event = Messages.Event{}
+	o.timing = map[int]Messages.Timing{}
+	o.locations = map[int]Messages.Location{}
+	o.penalties = map[int]time.Time{}
+	o.pending = nil
+	o.changes = nil
+}
+
+func (o *overtakeDetector) ProcessEvent(data Messages.Event) {
+	o.lock.Lock()
+	o.event = data
+	o.lock.Unlock()
+}
+
+func (o *overtakeDetector) ProcessLocation(data Messages.Location) {
+	o.lock.Lock()
+	o.locations[data.DriverNumber] = data
+	o.lock.Unlock()
+}
+
+func (o *overtakeDetector) ProcessRaceControlMessages(data Messages.RaceControlMessage) {
+	msg := raceControl.Parse(data)
+	if msg.Category != raceControl.Penalties || msg.PenaltyServed() {
+		return
+	}
+
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	// The penalty can be given before any timing has arrived for the car
+	for _, driverNumber := range msg.Cars {
+		o.penalties[driverNumber] = data.Timestamp
+	}
+}
+
+func (o *overtakeDetector) ProcessTiming(data Messages.Timing) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	previous, exists := o.timing[data.Number]
+	o.timing[data.Number] = data
+
+	// Only races have position changes from passing, otherwise they are from lap times
+	isRace := o.event.Type == Messages.Race || o.event.Type == Messages.Sprint
+	if !exists || !isRace || o.event.Status != Messages.Started ||
+		previous.Position == data.Position || previous.Position == 0 || data.Position == 0 {
+		return
+	}
+
+	change := &pendingChange{
+		driver:    data.Number,
+		from:      previous.Position,
+		to:        data.Position,
+		remaining: int(math.Abs(float64(data.Position - previous.Position))),
+		timestamp: data.Timestamp,
+	}
+
+	// Forget anything that hasn't been matched in time
+	pending := []*pendingChange{}
+	for _, other := range o.pending {
+		if data.Timestamp.Sub(other.timestamp) < positionChangeWindow {
+			pending = append(pending, other)
+		}
+	}
+	o.pending = pending
+
+	// Match against the changes for the other drivers involved that have already been updated
+	for _, other := range o.pending {
+		if change.remaining == 0 {
+			break
+		}
+		if other.remaining == 0 || other.driver == change.driver || other.gained() == change.gained() {
+			continue
+		}
+
+		gainer, loser := change, other
+		if !change.gained() {
+			gainer, loser = other, change
+		}
+
+		// The loser was ahead of the gainer before the change and is behind them after it
+		if loser.from >= gainer.from || loser.to <= gainer.to {
+			continue
+		}
+
+		o.addChange(gainer.driver, loser.driver, data.Timestamp)
+		gainer.remaining--
+		loser.remaining--
+	}
+
+	// Keep the changes still waiting for other drivers
+	pending = []*pendingChange{}
+	for _, other := range o.pending {
+		if other.remaining > 0 {
+			pending = append(pending, other)
+		}
+	}
+	if change.remaining > 0 {
+		pending = append(pending, change)
+	}
+	o.pending = pending
+}
+
+func (o *overtakeDetector) addChange(gainerNumber int, loserNumber int, timestamp time.Time) {
+	gainer := o.timing[gainerNumber]
+	loser := o.timing[loserNumber]
+
+	// A car out of the race can't gain places on anyone
+	if gainer.Location == Messages.OutOfRace {
+		return
+	}
+
+	change := positionChange{
+		timestamp: timestamp,
+		lap:       gainer.Lap,
+		gainer:    gainerNumber,
+		loser:     loserNumber,
+		position:  gainer.Position,
+	}
+
+	inPits := func(l Messages.CarLocation) bool { return l == Messages.Pitlane || l == Messages.PitOut }
+	recentPenalty := func(driverNumber int) bool {
+		penaltyTime, exists := o.penalties[driverNumber]
+		return exists && timestamp.Sub(penaltyTime) < penaltyWindow
+	}
+
+	gainerLocation, gainerHasLocation := o.locations[gainerNumber]
+	loserLocation, loserHasLocation := o.locations[loserNumber]
+	nearby := gainerHasLocation && loserHasLocation &&
+		math.Hypot(gainerLocation.X-loserLocation.X, gainerLocation.Y-loserLocation.Y) < overtakeDistance
+
+	switch {
+	case loser.Location == Messages.Stopped || loser.Location == Messages.OutOfRace:
+		change.changeType = Retirement
+	case inPits(loser.Location) || inPits(gainer.Location):
+		change.changeType = PitCycle
+	case !nearby && (recentPenalty(loserNumber) || recentPenalty(gainerNumber)):
+		change.changeType = Penalty
+	default:
+		change.changeType = OnTrackOvertake
+		change.location = location{x: gainerLocation.X, y: gainerLocation.Y}
+	}
+
+	o.changes = append(o.changes, change)
+}
+
+// positionChanges returns a copy of all the position changes in the order they happened
+func (o *overtakeDetector) positionChanges() []positionChange {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	result := make([]positionChange, len(o.changes))
+	copy(result, o.changes)
+	return result
+}
+
+// overtakeLocations returns where all of the on track overtakes happened
+func (o *overtakeDetector) overtakeLocations() []location {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	result := []location{}
+	for _, change := range o.changes {
+		if change.changeType == OnTrackOvertake && (change.location.x != 0 || change.location.y != 0) {
+			result = append(result, change.location)
+		}
+	}
+	return result
+}
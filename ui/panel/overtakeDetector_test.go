@@ -0,0 +1,129 @@
+package panel
+
+import (
+	"testing"
+	"time"
+
+	"github.com/f1gopher/f1gopherlib/Messages"
+)
+
+func TestOvertakeDetector(t *testing.T) {
+	start := time.Date(2024, 5, 26, 13, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		session        Messages.EventType
+		gainerLocation Messages.CarLocation
+		loserLocation  Messages.CarLocation
+		// Distance between the cars when they swap places
+		distance float64
+		penalty  string
+		expected []positionChangeType
+	}{
+		{name: "overtake", session: Messages.Race, gainerLocation: Messages.OnTrack, loserLocation: Messages.OnTrack,
+			distance: 10, expected: []positionChangeType{OnTrackOvertake}},
+		{name: "sprint overtake", session: Messages.Sprint, gainerLocation: Messages.OnTrack, loserLocation: Messages.OnTrack,
+			distance: 10, expected: []positionChangeType{OnTrackOvertake}},
+		{name: "loser pits", session: Messages.Race, gainerLocation: Messages.OnTrack, loserLocation: Messages.Pitlane,
+			distance: 5000, expected: []positionChangeType{PitCycle}},
+		{name: "gainer leaving the pits", session: Messages.Race, gainerLocation: Messages.PitOut, loserLocation: Messages.OnTrack,
+			distance: 10, expected: []positionChangeType{PitCycle}},
+		{name: "loser stopped", session: Messages.Race, gainerLocation: Messages.OnTrack, loserLocation: Messages.Stopped,
+			distance: 5000, expected: []positionChangeType{Retirement}},
+		{name: "loser out of the race", session: Messages.Race, gainerLocation: Messages.OnTrack, loserLocation: Messages.OutOfRace,
+			distance: 5000, expected: []positionChangeType{Retirement}},
+		{name: "penalty applied", session: Messages.Race, gainerLocation: Messages.OnTrack, loserLocation: Messages.OnTrack,
+			distance: 5000, penalty: "FIA STEWARDS: 5 SECOND TIME PENALTY FOR CAR 1 (VER) - CAUSING A COLLISION",
+			expected: []positionChangeType{Penalty}},
+		{name: "penalty served", session: Messages.Race, gainerLocation: Messages.OnTrack, loserLocation: Messages.OnTrack,
+			distance: 5000, penalty: "FIA STEWARDS: 5 SECOND TIME PENALTY SERVED BY CAR 1 (VER)",
+			expected: []positionChangeType{OnTrackOvertake}},
+		{name: "penalty but passed on track", session: Messages.Race, gainerLocation: Messages.OnTrack, loserLocation: Messages.OnTrack,
+			distance: 10, penalty: "FIA STEWARDS: 5 SECOND TIME PENALTY FOR CAR 1 (VER) - CAUSING A COLLISION",
+			expected: []positionChangeType{OnTrackOvertake}},
+		{name: "not a race", session: Messages.Qualifying1, gainerLocation: Messages.OnTrack, loserLocation: Messages.OnTrack,
+			distance: 10, expected: []positionChangeType{}},
+	}
+
+	for _, test := range tests {
+		detector := CreateOvertakeDetector()
+		detector.ProcessEvent(Messages.Event{Type: test.session, Status: Messages.Started})
+
+		// The penalty is given before there is any timing for the car
+		if test.penalty != "" {
+			detector.ProcessRaceControlMessages(Messages.RaceControlMessage{Timestamp: start, Msg: test.penalty})
+		}
+
+		// Car 1 leads car 16
+		detector.ProcessTiming(Messages.Timing{Timestamp: start, Number: 1, Position: 1, Lap: 10, Location: Messages.OnTrack})
+		detector.ProcessTiming(Messages.Timing{Timestamp: start, Number: 16, Position: 2, Lap: 10, Location: Messages.OnTrack})
+
+		detector.ProcessLocation(Messages.Location{Timestamp: start, DriverNumber: 1, X: 100, Y: 100})
+		detector.ProcessLocation(Messages.Location{Timestamp: start, DriverNumber: 16, X: 100 + test.distance, Y: 100})
+
+		// Car 16 moves ahead, the position changes arrive separately
+		swap := start.Add(time.Second)
+		detector.ProcessTiming(Messages.Timing{Timestamp: swap, Number: 16, Position: 1, Lap: 10, Location: test.gainerLocation})
+		detector.ProcessTiming(Messages.Timing{Timestamp: swap, Number: 1, Position: 2, Lap: 10, Location: test.loserLocation})
+
+		changes := detector.positionChanges()
+		if len(changes) != len(test.expected) {
+			t.Errorf("%s: expected %d changes, got %v", test.name, len(test.expected), changes)
+			continue
+		}
+		for x, change := range changes {
+			if change.changeType != test.expected[x] {
+				t.Errorf("%s: expected a %s, got a %s", test.name, test.expected[x], change.changeType)
+			}
+			if change.gainer != 16 || change.loser != 1 || change.position != 1 || change.lap != 10 {
+				t.Errorf("%s: unexpected change %+v", test.name, change)
+			}
+		}
+
+		locations := detector.overtakeLocations()
+		overtakes := 0
+		for _, expected := range test.expected {
+			if expected == OnTrackOvertake {
+				overtakes++
+			}
+		}
+		if len(locations) != overtakes {
+			t.Errorf("%s: expected %d overtake locations, got %v", test.name, overtakes, locations)
+		}
+	}
+}
+
+func TestOvertakeDetectorMultiplePlaces(t *testing.T) {
+	start := time.Date(2024, 5, 26, 13, 0, 0, 0, time.UTC)
+
+	detector := CreateOvertakeDetector()
+	detector.ProcessEvent(Messages.Event{Type: Messages.Race, Status: Messages.Started})
+	for position, number := range []int{1, 16, 4, 81} {
+		detector.ProcessTiming(Messages.Timing{Timestamp: start, Number: number, Position: position + 1, Location: Messages.OnTrack})
+	}
+
+	// Car 1 pits and drops from first to fourth
+	pitStop := start.Add(20 * time.Second)
+	detector.ProcessTiming(Messages.Timing{Timestamp: pitStop, Number: 1, Position: 4, Location: Messages.Pitlane})
+	detector.ProcessTiming(Messages.Timing{Timestamp: pitStop, Number: 16, Position: 1, Location: Messages.OnTrack})
+	detector.ProcessTiming(Messages.Timing{Timestamp: pitStop, Number: 4, Position: 2, Location: Messages.OnTrack})
+	detector.ProcessTiming(Messages.Timing{Timestamp: pitStop, Number: 81, Position: 3, Location: Messages.OnTrack})
+
+	changes := detector.positionChanges()
+	if len(changes) != 3 {
+		t.Fatalf("expected 3 changes, got %v", changes)
+	}
+	for x, gainer := range []int{16, 4, 81} {
+		if changes[x].changeType != PitCycle || changes[x].gainer != gainer || changes[x].loser != 1 {
+			t.Errorf("expected car %d to gain a place from the pit cycle, got %+v", gainer, changes[x])
+		}
+	}
+
+	// Changes that aren't matched in time are forgotten
+	late := pitStop.Add(positionChangeWindow + time.Second)
+	detector.ProcessTiming(Messages.Timing{Timestamp: late, Number: 4, Position: 1, Location: Messages.OnTrack})
+	detector.ProcessTiming(Messages.Timing{Timestamp: late.Add(positionChangeWindow + time.Second), Number: 16, Position: 2, Location: Messages.OnTrack})
+	if changes = detector.positionChanges(); len(changes) != 3 {
+		t.Errorf("expected unmatched changes to be ignored, got %v", changes)
+	}
+}
@@ -0,0 +1,237 @@
+// F1Gopher - Copyright (C) 2025 f1gopher
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package panel
+
+import (
+	"fmt"
+	"image/color"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/AllenDang/giu"
+	"github.com/f1gopher/f1gopherlib"
+	"github.com/f1gopher/f1gopherlib/Messages"
+	"golang.org/x/image/colornames"
+)
+
+type overtakesDriver struct {
+	name  string
+	color color.RGBA
+}
+
+type overtakeTotals struct {
+	driverNumber int
+	made         int
+	lost         int
+	// Positions gained or lost that weren't from on track overtakes
+	otherGained int
+	otherLost   int
+}
+
+type overtakes struct {
+	detector *overtakeDetector
+	focus    *DriverFocus
+
+	drivers     map[int]overtakesDriver
+	driversLock sync.Mutex
+	timezone    *time.Location
+
+	showOnlyOvertakes bool
+}
+
+func CreateOvertakes(detector *overtakeDetector, focus *DriverFocus) Panel {
+	return &overtakes{
+		detector:          detector,
+		focus:             focus,
+		drivers:           map[int]overtakesDriver{},
+		showOnlyOvertakes: false,
+	}
+}
+
+func (o *overtakes) ProcessEventTime(data Messages.EventTime) {}
+func (o *overtakes) ProcessWeather(data Messages.Weather)     {}
+func (o *overtakes) ProcessRadio(data Messages.Radio)         {}
+func (o *overtakes) ProcessTelemetry(data Messages.Telemetry) {}
+func (o *overtakes) Close()                                   {}
+
+// The detector is shared with the track map but only fed from here
+
+func (o *overtakes) ProcessLocation(data Messages.Location) {
+	o.detector.ProcessLocation(data)
+}
+
+func (o *overtakes) ProcessTiming(data Messages.Timing) {
+	o.detector.ProcessTiming(data)
+}
+
+func (o *overtakes) ProcessEvent(data Messages.Event) {
+	o.detector.ProcessEvent(data)
+}
+
+func (o *overtakes) ProcessRaceControlMessages(data Messages.RaceControlMessage) {
+	o.detector.ProcessRaceControlMessages(data)
+}
+
+func (o *overtakes) Type() Type { return Overtakes }
+
+func (o *overtakes) Init(dataSrc f1gopherlib.F1GopherLib, config PanelConfig) {
+	o.timezone = dataSrc.CircuitTimezone()
+	o.detector.Reset()
+
+	o.driversLock.Lock()
+	o.drivers = map[int]overtakesDriver{}
+	o.driversLock.Unlock()
+}
+
+func (o *overtakes) ProcessDrivers(data Messages.Drivers) {
+	o.driversLock.Lock()
+	defer o.driversLock.Unlock()
+
+	for _, driver := range data.Drivers {
+		o.drivers[driver.Number] = overtakesDriver{name: driver.ShortName, color: driver.Color}
+	}
+}
+
+func (o *overtakes) driver(driverNumber int) overtakesDriver {
+	o.driversLock.Lock()
+	defer o.driversLock.Unlock()
+
+	driver, exists := o.drivers[driverNumber]
+	if !exists {
+		return overtakesDriver{name: fmt.Sprintf("%d", driverNumber), color: colornames.White}
+	}
+	return driver
+}
+
+func (o *overtakes) Draw(width int, height int) []giu.Widget {
+	changes := o.detector.positionChanges()
+
+	// Newest first
+	feed := make([]*giu.TableRowWidget, 0, len(changes))
+	totals := map[int]*overtakeTotals{}
+	total := func(driverNumber int) *overtakeTotals {
+		if _, exists := totals[driverNumber]; !exists {
+			totals[driverNumber] = &overtakeTotals{driverNumber: driverNumber}
+		}
+		return totals[driverNumber]
+	}
+
+	for x := len(changes) - 1; x >= 0; x-- {
+		change := changes[x]
+
+		if change.changeType == OnTrackOvertake {
+			total(change.gainer).made++
+			total(change.loser).lost++
+		} else {
+			total(change.gainer).otherGained++
+			total(change.loser).otherLost++
+		}
+
+		if o.showOnlyOvertakes && change.changeType != OnTrackOvertake {
+			continue
+		}
+
+		gainer := o.driver(change.gainer)
+		loser := o.driver(change.loser)
+
+		typeColor := colornames.White
+		switch change.changeType {
+		case OnTrackOvertake:
+			typeColor = colornames.Green
+		case PitCycle:
+			typeColor = colornames.Yellow
+		case Retirement:
+			typeColor = colornames.Red
+		case Penalty:
+			typeColor = colornames.Orange
+		}
+
+		feed = append(feed, giu.TableRow(
+			giu.Label(change.timestamp.In(o.timezone).Format("15:04:05")),
+			giu.Label(fmt.Sprintf("%d", change.lap)),
+			giu.Style().SetColor(giu.StyleColorText, typeColor).To(giu.Label(change.changeType.String())),
+			giu.Style().SetColor(giu.StyleColorText, gainer.color).To(giu.Label(gainer.name)),
+			giu.Label(fmt.Sprintf("P%d", change.position)),
+			giu.Style().SetColor(giu.StyleColorText, loser.color).To(giu.Label(loser.name)),
+		))
+	}
+
+	sortedTotals := make([]*overtakeTotals, 0, len(totals))
+	for _, t := range totals {
+		sortedTotals = append(sortedTotals, t)
+	}
+	sort.Slice(sortedTotals, func(i, j int) bool {
+		if sortedTotals[i].made != sortedTotals[j].made {
+			return sortedTotals[i].made > sortedTotals[j].made
+		}
+		return sortedTotals[i].driverNumber < sortedTotals[j].driverNumber
+	})
+
+	totalRows := make([]*giu.TableRowWidget, 0, len(sortedTotals))
+	for _, t := range sortedTotals {
+		driver := o.driver(t.driverNumber)
+		driverNumber := t.driverNumber
+
+		row := giu.TableRow(
+			giu.Selectable(driver.name).
+				Flags(giu.SelectableFlagsSpanAllColumns).
+				OnClick(func() { o.focus.Select(driverNumber) }),
+			giu.Label(fmt.Sprintf("%d", t.made)),
+			giu.Label(fmt.Sprintf("%d", t.lost)),
+			giu.Label(fmt.Sprintf("%+d", t.made-t.lost)),
+			giu.Label(fmt.Sprintf("%d", t.otherGained)),
+			giu.Label(fmt.Sprintf("%d", t.otherLost)),
+		)
+		if focusColor, focused := o.focus.highlight(driverNumber); focused {
+			row = row.BgColor(focusColor)
+		}
+		totalRows = append(totalRows, row)
+	}
+
+	tableHeight := float32(height - 40)
+	feedWidth := float32(width-24) * 0.55
+
+	return []giu.Widget{
+		giu.Checkbox("Only Overtakes", &o.showOnlyOvertakes),
+		giu.Row(
+			giu.Child().Size(feedWidth, tableHeight).Layout(
+				giu.Table().FastMode(true).Flags(giu.TableFlagsResizable|giu.TableFlagsSizingFixedSame|giu.TableFlagsScrollY).
+					Columns(
+						giu.TableColumn("Time").InnerWidthOrWeight(60),
+						giu.TableColumn("Lap").InnerWidthOrWeight(30),
+						giu.TableColumn("Type").InnerWidthOrWeight(70),
+						giu.TableColumn("Drv").InnerWidthOrWeight(35),
+						giu.TableColumn("For").InnerWidthOrWeight(35),
+						giu.TableColumn("Passed").InnerWidthOrWeight(45),
+					).
+					Rows(feed...),
+			),
+			giu.Child().Size(float32(width-24)-feedWidth, tableHeight).Layout(
+				giu.Table().FastMode(true).Flags(giu.TableFlagsResizable|giu.TableFlagsSizingFixedSame|giu.TableFlagsScrollY).
+					Columns(
+						giu.TableColumn("Drv").InnerWidthOrWeight(35),
+						giu.TableColumn("Made").InnerWidthOrWeight(40),
+						giu.TableColumn("Lost").InnerWidthOrWeight(40),
+						giu.TableColumn("Net").InnerWidthOrWeight(35),
+						giu.TableColumn("Other +").InnerWidthOrWeight(50),
+						giu.TableColumn("Other -").InnerWidthOrWeight(50),
+					).
+					Rows(totalRows...),
+			),
+		),
+	}
+}
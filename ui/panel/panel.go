@@ -37,6 +37,20 @@ const (
 	Catching
 	QualifyingImproving
 	CircleMap
+	LapTimeScatter
+	DriverDashboard
+	Championship
+	PitStopLog
+	Overtakes
+	Battles
+	LongRuns
+	LapAnalysis
+	TrackLimits
+	Stewards
+	AlertRules
+	Webhooks
+	MQTT
+	OSC
 )
 
 func (t Type) String() string {
@@ -54,6 +68,20 @@ func (t Type) String() string {
 		"Catching",
 		"QualifyingImproving",
 		"CircleMap",
+		"LapTimeScatter",
+		"DriverDashboard",
+		"Championship",
+		"PitStopLog",
+		"Overtakes",
+		"Battles",
+		"LongRuns",
+		"LapAnalysis",
+		"TrackLimits",
+		"Stewards",
+		"AlertRules",
+		"Webhooks",
+		"MQTT",
+		"OSC",
 	}[t]
 }
 
@@ -0,0 +1,370 @@
+// F1Gopher - Copyright (C) 2025 f1gopher
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package panel
+
+import (
+	"encoding/csv"
+	"fmt"
+	"image/color"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/AllenDang/giu"
+	"github.com/f1gopher/f1gopherlib"
+	"github.com/f1gopher/f1gopherlib/Messages"
+	"golang.org/x/image/colornames"
+)
+
+type pitStopLogDriver struct {
+	name      string
+	team      string
+	color     color.RGBA
+	tire      Messages.TireType
+	position  int
+	stopsSeen int
+}
+
+type pitStopEntry struct {
+	driverNumber   int
+	name           string
+	team           string
+	color          color.RGBA
+	lap            int
+	pitlaneEntry   time.Time
+	pitlaneTime    time.Duration
+	compoundBefore Messages.TireType
+	compoundAfter  Messages.TireType
+	positionBefore int
+	positionAfter  int
+	rejoined       bool
+}
+
+type pitStopLog struct {
+	dataSrc f1gopherlib.F1GopherLib
+
+	drivers map[int]*pitStopLogDriver
+	stops   []*pitStopEntry
+	lock    sync.Mutex
+
+	// The log is exported with the session's data or to the working directory if the session isn't being saved
+	exportFolder string
+	exportStatus string
+}
+
+func CreatePitStopLog() Panel {
+	return &pitStopLog{
+		drivers: map[int]*pitStopLogDriver{},
+	}
+}
+
+func (p *pitStopLog) ProcessEventTime(data Messages.EventTime)                    {}
+func (p *pitStopLog) ProcessEvent(data Messages.Event)                            {}
+func (p *pitStopLog) ProcessRaceControlMessages(data Messages.RaceControlMessage) {}
+func (p *pitStopLog) ProcessWeather(data Messages.Weather)                        {}
+func (p *pitStopLog) ProcessRadio(data Messages.Radio)                            {}
+func (p *pitStopLog) ProcessLocation(data Messages.Location)                      {}
+func (p *pitStopLog) ProcessTelemetry(data Messages.Telemetry)                    {}
+func (p *pitStopLog) Close()                                                      {}
+
+func (p *pitStopLog) Type() Type { return PitStopLog }
+
+func (p *pitStopLog) Init(dataSrc f1gopherlib.F1GopherLib, config PanelConfig) {
+	p.dataSrc = dataSrc
+	p.exportFolder = config.SessionDataFolder()
+	p.exportStatus = ""
+
+	p.lock.Lock()
+	p.drivers = map[int]*pitStopLogDriver{}
+	p.stops = nil
+	p.lock.Unlock()
+}
+
+func (p *pitStopLog) ProcessDrivers(data Messages.Drivers) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	for _, driver := range data.Drivers {
+		p.drivers[driver.Number] = &pitStopLogDriver{
+			name:     driver.ShortName,
+			team:     driver.Team,
+			color:    driver.Color,
+			position: driver.StartPosition,
+		}
+	}
+}
+
+func (p *pitStopLog) ProcessTiming(data Messages.Timing) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	driver, exists := p.drivers[data.Number]
+	if !exists {
+		return
+	}
+
+	// A new stop is added when the driver enters the pitlane, the tire and position are from before they came in
+	for ; driver.stopsSeen < len(data.PitStopTimes); driver.stopsSeen++ {
+		stop := data.PitStopTimes[driver.stopsSeen]
+		p.stops = append(p.stops, &pitStopEntry{
+			driverNumber:   data.Number,
+			name:           driver.name,
+			team:           driver.team,
+			color:          driver.color,
+			lap:            stop.Lap,
+			pitlaneEntry:   stop.PitlaneEntry,
+			compoundBefore: driver.tire,
+			positionBefore: driver.position,
+		})
+	}
+
+	for _, stop := range p.stops {
+		if stop.driverNumber != data.Number || stop.rejoined {
+			continue
+		}
+
+		for _, pitStop := range data.PitStopTimes {
+			if pitStop.PitlaneEntry.Equal(stop.pitlaneEntry) {
+				stop.pitlaneTime = pitStop.PitlaneTime
+			}
+		}
+
+		// The position settles and the new tire is known during the out lap, the stop is finished once back on track
+		switch data.Location {
+		case Messages.OutLap:
+			stop.compoundAfter = data.Tire
+			stop.positionAfter = data.Position
+		case Messages.OnTrack:
+			stop.compoundAfter = data.Tire
+			stop.positionAfter = data.Position
+			stop.rejoined = true
+		case Messages.Stopped:
+			stop.rejoined = true
+		}
+	}
+
+	driver.tire = data.Tire
+	if data.Location != Messages.Pitlane {
+		driver.position = data.Position
+	}
+}
+
+// rankedStops returns a copy of the stops and the rank of each by pitlane time
+func (p *pitStopLog) rankedStops() ([]pitStopEntry, map[*pitStopEntry]int) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	stops := make([]pitStopEntry, 0, len(p.stops))
+	for _, stop := range p.stops {
+		stops = append(stops, *stop)
+	}
+
+	ranks := map[*pitStopEntry]int{}
+	fastest := make([]*pitStopEntry, 0, len(stops))
+	for x := range stops {
+		if stops[x].pitlaneTime > 0 {
+			fastest = append(fastest, &stops[x])
+		}
+	}
+	sort.SliceStable(fastest, func(i, j int) bool { return fastest[i].pitlaneTime < fastest[j].pitlaneTime })
+	for x := range fastest {
+		ranks[fastest[x]] = x + 1
+	}
+
+	return stops, ranks
+}
+
+func (p *pitStopLog) Draw(width int, height int) []giu.Widget {
+	stops, ranks := p.rankedStops()
+
+	rows := make([]*giu.TableRowWidget, 0, len(stops))
+	for x := range stops {
+		stop := &stops[x]
+
+		rank := ""
+		rankColor := colornames.White
+		if r, exists := ranks[stop]; exists {
+			rank = fmt.Sprintf("%d", r)
+			if r == 1 {
+				rankColor = purpleColor
+			}
+		}
+
+		positionAfter := ""
+		positionColor := colornames.White
+		if stop.positionAfter > 0 {
+			positionAfter = fmt.Sprintf("%d", stop.positionAfter)
+			if stop.positionAfter > stop.positionBefore {
+				positionColor = colornames.Red
+			} else if stop.positionAfter < stop.positionBefore {
+				positionColor = colornames.Green
+			}
+		}
+
+		rows = append(rows, giu.TableRow(
+			giu.Style().SetColor(giu.StyleColorText, stop.color).To(giu.Label(stop.name)),
+			giu.Label(fmt.Sprintf("%d", stop.lap)),
+			giu.Label(fmtDuration(stop.pitlaneTime)),
+			giu.Style().SetColor(giu.StyleColorText, tireColor(stop.compoundBefore)).To(giu.Label(stop.compoundBefore.String())),
+			giu.Style().SetColor(giu.StyleColorText, tireColor(stop.compoundAfter)).To(giu.Label(stop.compoundAfter.String())),
+			giu.Label(fmt.Sprintf("%d", stop.positionBefore)),
+			giu.Style().SetColor(giu.StyleColorText, positionColor).To(giu.Label(positionAfter)),
+			giu.Style().SetColor(giu.StyleColorText, rankColor).To(giu.Label(rank)),
+		))
+	}
+
+	tableHeight := float32(height - 40)
+	logWidth := float32(width-24) * 0.6
+
+	toolbar := []giu.Widget{giu.Button("Export CSV").OnClick(p.export)}
+	if p.exportStatus != "" {
+		toolbar = append(toolbar, giu.Label(p.exportStatus))
+	}
+
+	return []giu.Widget{
+		giu.Row(toolbar...),
+		giu.Row(
+			giu.Child().Size(logWidth, tableHeight).Layout(
+				giu.Table().FastMode(true).Flags(giu.TableFlagsResizable|giu.TableFlagsSizingFixedSame|giu.TableFlagsScrollY).
+					Columns(
+						giu.TableColumn("Drv").InnerWidthOrWeight(35),
+						giu.TableColumn("Lap").InnerWidthOrWeight(30),
+						giu.TableColumn("Pitlane").InnerWidthOrWeight(timeWidth),
+						giu.TableColumn("Tire In").InnerWidthOrWeight(60),
+						giu.TableColumn("Tire Out").InnerWidthOrWeight(60),
+						giu.TableColumn("Pos In").InnerWidthOrWeight(45),
+						giu.TableColumn("Pos Out").InnerWidthOrWeight(45),
+						giu.TableColumn("Rank").InnerWidthOrWeight(35),
+					).
+					Rows(rows...),
+			),
+			giu.Child().Size(float32(width-24)-logWidth, tableHeight).Layout(
+				giu.Label("Fastest Stop per Team"),
+				p.teamSummary(stops),
+			),
+		),
+	}
+}
+
+func (p *pitStopLog) teamSummary(stops []pitStopEntry) giu.Widget {
+	fastest := map[string]*pitStopEntry{}
+	count := map[string]int{}
+	for x := range stops {
+		stop := &stops[x]
+		count[stop.team]++
+		if stop.pitlaneTime == 0 {
+			continue
+		}
+		if current, exists := fastest[stop.team]; !exists || stop.pitlaneTime < current.pitlaneTime {
+			fastest[stop.team] = stop
+		}
+	}
+
+	teams := make([]*pitStopEntry, 0, len(fastest))
+	for _, stop := range fastest {
+		teams = append(teams, stop)
+	}
+	sort.Slice(teams, func(i, j int) bool { return teams[i].pitlaneTime < teams[j].pitlaneTime })
+
+	rows := make([]*giu.TableRowWidget, 0, len(teams))
+	for _, stop := range teams {
+		rows = append(rows, giu.TableRow(
+			giu.Style().SetColor(giu.StyleColorText, stop.color).To(giu.Label(stop.team)),
+			giu.Label(stop.name),
+			giu.Label(fmt.Sprintf("%d", stop.lap)),
+			giu.Label(fmtDuration(stop.pitlaneTime)),
+			giu.Label(fmt.Sprintf("%d", count[stop.team])),
+		))
+	}
+
+	return giu.Table().FastMode(true).Flags(giu.TableFlagsResizable|giu.TableFlagsSizingFixedSame|giu.TableFlagsScrollY).
+		Columns(
+			giu.TableColumn("Team").InnerWidthOrWeight(120),
+			giu.TableColumn("Drv").InnerWidthOrWeight(35),
+			giu.TableColumn("Lap").InnerWidthOrWeight(30),
+			giu.TableColumn("Pitlane").InnerWidthOrWeight(timeWidth),
+			giu.TableColumn("Stops").InnerWidthOrWeight(40),
+		).
+		Rows(rows...)
+}
+
+func (p *pitStopLog) export() {
+	file, err := p.exportCSV()
+	switch {
+	case err != nil:
+		p.exportStatus = fmt.Sprintf("Export failed: %v", err)
+	case file == "":
+		p.exportStatus = "Nothing to export"
+	default:
+		p.exportStatus = fmt.Sprintf("Saved %s", file)
+	}
+}
+
+// exportCSV saves the pit stop log to a CSV file named after the session in the export folder, replacing any earlier
+// export for the session. Returns the file or empty if there are no stops.
+func (p *pitStopLog) exportCSV() (string, error) {
+	stops, ranks := p.rankedStops()
+	if len(stops) == 0 {
+		return "", nil
+	}
+
+	folder := p.exportFolder
+	if folder == "" {
+		folder = "."
+	}
+	if err := os.MkdirAll(folder, 0755); err != nil {
+		return "", err
+	}
+	file := filepath.Join(folder, fmt.Sprintf("%s %s Pit Stops.csv", p.dataSrc.Name(), p.dataSrc.Session().String()))
+
+	f, err := os.Create(file)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	w.Write([]string{"Driver", "Team", "Lap", "Pitlane Entry", "Pitlane Time", "Tire Before", "Tire After", "Position Before", "Position After", "Rank"})
+	for x := range stops {
+		stop := &stops[x]
+
+		rank := ""
+		if r, exists := ranks[stop]; exists {
+			rank = fmt.Sprintf("%d", r)
+		}
+
+		w.Write([]string{
+			stop.name,
+			stop.team,
+			fmt.Sprintf("%d", stop.lap),
+			stop.pitlaneEntry.In(p.dataSrc.CircuitTimezone()).Format("15:04:05"),
+			fmtDuration(stop.pitlaneTime),
+			stop.compoundBefore.String(),
+			stop.compoundAfter.String(),
+			fmt.Sprintf("%d", stop.positionBefore),
+			fmt.Sprintf("%d", stop.positionAfter),
+			rank,
+		})
+	}
+	w.Flush()
+
+	if err = w.Error(); err != nil {
+		return "", err
+	}
+	return file, nil
+}
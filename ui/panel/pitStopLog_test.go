@@ -0,0 +1,121 @@
+package panel
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/f1gopher/f1gopherlib"
+	"github.com/f1gopher/f1gopherlib/Messages"
+)
+
+type pitStopSource struct {
+	f1gopherlib.F1GopherLib
+}
+
+func (p *pitStopSource) Name() string                    { return "British Grand Prix" }
+func (p *pitStopSource) Session() Messages.SessionType   { return Messages.RaceSession }
+func (p *pitStopSource) CircuitTimezone() *time.Location { return time.UTC }
+
+// pitStopTimings returns the timing updates for a driver making a stop on the lap. The pitlane time arrives after the
+// car has entered the pitlane and the final position once it is back on track.
+func pitStopTimings(number int, lap int, entry time.Time, pitlaneTime time.Duration, positionBefore int,
+	outLapPosition int, positionAfter int) []Messages.Timing {
+
+	stop := Messages.PitStop{Lap: lap, PitlaneEntry: entry}
+	timed := stop
+	timed.PitlaneTime = pitlaneTime
+	return []Messages.Timing{
+		{Number: number, Lap: lap, Position: positionBefore, Location: Messages.OnTrack, Tire: Messages.Medium},
+		{Number: number, Lap: lap, Position: positionBefore + 2, Location: Messages.Pitlane, Tire: Messages.Medium,
+			PitStopTimes: []Messages.PitStop{stop}},
+		{Number: number, Lap: lap, Position: positionBefore + 2, Location: Messages.Pitlane, Tire: Messages.Hard,
+			PitStopTimes: []Messages.PitStop{timed}},
+		{Number: number, Lap: lap + 1, Position: outLapPosition, Location: Messages.OutLap, Tire: Messages.Hard,
+			PitStopTimes: []Messages.PitStop{timed}},
+		{Number: number, Lap: lap + 1, Position: positionAfter, Location: Messages.OnTrack, Tire: Messages.Hard,
+			PitStopTimes: []Messages.PitStop{timed}},
+		// Later position changes aren't part of the stop
+		{Number: number, Lap: lap + 5, Position: positionAfter + 1, Location: Messages.OnTrack, Tire: Messages.Hard,
+			PitStopTimes: []Messages.PitStop{timed}},
+	}
+}
+
+func TestPitStopLog(t *testing.T) {
+	p := CreatePitStopLog().(*pitStopLog)
+	p.ProcessDrivers(Messages.Drivers{Drivers: []Messages.DriverInfo{
+		{Number: 1, ShortName: "VER", Team: "Red Bull Racing", StartPosition: 1},
+		{Number: 11, ShortName: "PER", Team: "Red Bull Racing", StartPosition: 3},
+		{Number: 16, ShortName: "LEC", Team: "Ferrari", StartPosition: 2},
+	}})
+
+	start := time.Date(2024, 7, 7, 15, 0, 0, 0, time.UTC)
+	timings := pitStopTimings(1, 20, start, 21500*time.Millisecond, 1, 4, 2)
+	timings = append(timings, pitStopTimings(16, 22, start.Add(3*time.Minute), 20900*time.Millisecond, 2, 3, 3)...)
+	// Still in the pitlane so there is no time or position after yet
+	timings = append(timings,
+		Messages.Timing{Number: 11, Lap: 25, Position: 3, Location: Messages.OnTrack, Tire: Messages.Soft},
+		Messages.Timing{Number: 11, Lap: 25, Position: 5, Location: Messages.Pitlane, Tire: Messages.Soft,
+			PitStopTimes: []Messages.PitStop{{Lap: 25, PitlaneEntry: start.Add(8 * time.Minute)}}})
+	for _, timing := range timings {
+		p.ProcessTiming(timing)
+	}
+
+	expected := []struct {
+		stop pitStopEntry
+		rank int
+	}{
+		{stop: pitStopEntry{driverNumber: 1, lap: 20, pitlaneTime: 21500 * time.Millisecond, compoundBefore: Messages.Medium,
+			compoundAfter: Messages.Hard, positionBefore: 1, positionAfter: 2, rejoined: true}, rank: 2},
+		{stop: pitStopEntry{driverNumber: 16, lap: 22, pitlaneTime: 20900 * time.Millisecond, compoundBefore: Messages.Medium,
+			compoundAfter: Messages.Hard, positionBefore: 2, positionAfter: 3, rejoined: true}, rank: 1},
+		{stop: pitStopEntry{driverNumber: 11, lap: 25, compoundBefore: Messages.Soft, positionBefore: 3}},
+	}
+
+	stops, ranks := p.rankedStops()
+	if len(stops) != len(expected) {
+		t.Fatalf("expected %d stops, got %d", len(expected), len(stops))
+	}
+	for x := range stops {
+		stop := &stops[x]
+		want := expected[x].stop
+		if stop.driverNumber != want.driverNumber || stop.lap != want.lap || stop.pitlaneTime != want.pitlaneTime ||
+			stop.compoundBefore != want.compoundBefore || stop.compoundAfter != want.compoundAfter ||
+			stop.positionBefore != want.positionBefore || stop.positionAfter != want.positionAfter ||
+			stop.rejoined != want.rejoined {
+			t.Errorf("expected stop %d to be %+v, got %+v", x, want, *stop)
+		}
+		if ranks[stop] != expected[x].rank {
+			t.Errorf("expected car %d's stop to rank %d, got %d", stop.driverNumber, expected[x].rank, ranks[stop])
+		}
+	}
+
+	// The export is kept with the session's data
+	p.dataSrc = &pitStopSource{}
+	p.exportFolder = filepath.Join(t.TempDir(), "2024", "2024-07-07_British Grand Prix", "Race")
+	file, err := p.exportCSV()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if file != filepath.Join(p.exportFolder, "British Grand Prix Race Pit Stops.csv") {
+		t.Errorf("expected the export in the session folder, got %s", file)
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != len(expected)+1 {
+		t.Fatalf("expected a header and %d stops, got %v", len(expected), records)
+	}
+	if records[2][0] != "LEC" || records[2][3] != "15:03:00" || records[2][9] != "1" {
+		t.Errorf("expected LEC's stop to be the fastest, got %v", records[2])
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"image"
 	"image/color"
 	"image/draw"
+	"sync/atomic"
 
 	"github.com/AllenDang/giu"
 	"github.com/ungerik/go-cairo"
@@ -38,6 +39,8 @@ type plot struct {
 
 	redrawForeground bool
 	redrawBackground bool
+	// Increased whenever a redraw is requested so copies of the plot know when they are out of date
+	version atomic.Uint64
 
 	drawBackground func(*cairo.Surface)
 	drawForeground func(*cairo.Surface)
@@ -81,10 +84,12 @@ func (p *plot) draw(width int, height int) *giu.ImageWidget {
 
 func (p *plot) refreshForeground() {
 	p.redrawForeground = true
+	p.version.Add(1)
 }
 
 func (p *plot) refreshBackground() {
 	p.redrawBackground = true
+	p.version.Add(1)
 }
 
 func (p *plot) redraw(width int, height int) {
@@ -153,3 +158,7 @@ func (p *plot) redraw(width int, height int) {
 func floatColor(color color.RGBA) (float64, float64, float64, float64) {
 	return float64(color.R) / 255.0, float64(color.G) / 255.0, float64(color.B) / 255.0, float64(color.A) / 255.0
 }
+
+func toRGBA(c color.Color) color.RGBA {
+	return color.RGBAModel.Convert(c).(color.RGBA)
+}
@@ -16,8 +16,10 @@
 package panel
 
 import (
+	"f1gopher/raceControl"
 	"fmt"
 	"runtime"
+	"sort"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -41,22 +43,38 @@ func flagCharacter() string {
 
 type raceControlMessages struct {
 	dataSrc        f1gopherlib.F1GopherLib
-	rcMessages     []Messages.RaceControlMessage
+	rcMessages     []raceControl.Message
 	rcMessagesLock sync.Mutex
 	dataChanged    atomic.Bool
 	scrollToBottom *ScrollToBottomWidget
 
+	// Filters
+	categories     *rcmCategorySelectWidget
+	search         string
+	driverNames    []string
+	driverNumbers  []int
+	selectedDriver int32
+
 	cachedUI []giu.Widget
 }
 
 func CreateRaceControlMessages() Panel {
-	return &raceControlMessages{
-		rcMessages:     make([]Messages.RaceControlMessage, 0),
+	panel := &raceControlMessages{
+		rcMessages:     make([]raceControl.Message, 0),
 		scrollToBottom: &ScrollToBottomWidget{id: "scroll to bottom"},
+		driverNames:    []string{"All Drivers"},
+		driverNumbers:  []int{NoDriver},
+	}
+	panel.categories = &rcmCategorySelectWidget{
+		shown:    map[raceControl.Category]bool{},
+		onChange: func() { panel.dataChanged.Store(true) },
 	}
+	for _, category := range raceControl.Categories() {
+		panel.categories.shown[category] = true
+	}
+	return panel
 }
 
-func (r *raceControlMessages) ProcessDrivers(data Messages.Drivers)     {}
 func (r *raceControlMessages) ProcessTiming(data Messages.Timing)       {}
 func (r *raceControlMessages) ProcessEventTime(data Messages.EventTime) {}
 func (r *raceControlMessages) ProcessEvent(data Messages.Event)         {}
@@ -72,27 +90,80 @@ func (r *raceControlMessages) Init(dataSrc f1gopherlib.F1GopherLib, config Panel
 	r.dataSrc = dataSrc
 
 	// Clear previous session data
-	r.rcMessages = make([]Messages.RaceControlMessage, 0)
+	r.rcMessagesLock.Lock()
+	r.rcMessages = make([]raceControl.Message, 0)
+	r.driverNames = []string{"All Drivers"}
+	r.driverNumbers = []int{NoDriver}
+	r.selectedDriver = 0
+	r.rcMessagesLock.Unlock()
 	r.cachedUI = make([]giu.Widget, 0)
 }
 
+func (r *raceControlMessages) ProcessDrivers(data Messages.Drivers) {
+	drivers := make([]Messages.DriverInfo, len(data.Drivers))
+	copy(drivers, data.Drivers)
+	sort.Slice(drivers, func(i, j int) bool { return drivers[i].ShortName < drivers[j].ShortName })
+
+	r.rcMessagesLock.Lock()
+	r.driverNames = []string{"All Drivers"}
+	r.driverNumbers = []int{NoDriver}
+	for _, driver := range drivers {
+		r.driverNames = append(r.driverNames, driver.ShortName)
+		r.driverNumbers = append(r.driverNumbers, driver.Number)
+	}
+	r.selectedDriver = 0
+	r.rcMessagesLock.Unlock()
+}
+
 func (r *raceControlMessages) ProcessRaceControlMessages(data Messages.RaceControlMessage) {
 	r.rcMessagesLock.Lock()
-	r.rcMessages = append(r.rcMessages, data)
+	r.rcMessages = append(r.rcMessages, raceControl.Parse(data))
 	r.rcMessagesLock.Unlock()
 	r.dataChanged.Store(true)
 }
 
 func (r *raceControlMessages) Draw(width int, height int) []giu.Widget {
+	r.rcMessagesLock.Lock()
+	driverNames := r.driverNames
+	r.rcMessagesLock.Unlock()
+
+	filters := giu.Row(
+		r.categories,
+		giu.Combo("Driver", driverNames[r.selectedDriver], driverNames, &r.selectedDriver).
+			OnChange(func() { r.dataChanged.Store(true) }).
+			Size(100),
+		giu.InputText(&r.search).Hint("Search").Size(150).OnChange(func() { r.dataChanged.Store(true) }),
+	)
+
 	if r.dataChanged.CompareAndSwap(true, false) {
 		r.dataChanged.Store(false)
 		r.cachedUI = r.formatMessages()
 
 		// The first time we redraw after a new message scroll to the bottom
-		return append(r.cachedUI, r.scrollToBottom)
+		return []giu.Widget{
+			filters,
+			giu.Child().Size(float32(width-16), float32(height-40)).Layout(append(r.cachedUI, r.scrollToBottom)...),
+		}
 	}
 
-	return r.cachedUI
+	return []giu.Widget{
+		filters,
+		giu.Child().Size(float32(width-16), float32(height-40)).Layout(r.cachedUI...),
+	}
+}
+
+// shown returns true if the message passes the category, driver and search filters. Caller must hold the messages lock.
+func (r *raceControlMessages) shown(msg *raceControl.Message) bool {
+	if !r.categories.isShown(msg.Category) {
+		return false
+	}
+
+	if driverNumber := r.driverNumbers[r.selectedDriver]; driverNumber != NoDriver && !msg.MentionsCar(driverNumber) {
+		return false
+	}
+
+	search := strings.TrimSpace(r.search)
+	return search == "" || strings.Contains(strings.ToUpper(msg.Msg), strings.ToUpper(search))
 }
 
 func (r *raceControlMessages) formatMessages() []giu.Widget {
@@ -101,6 +172,10 @@ func (r *raceControlMessages) formatMessages() []giu.Widget {
 	r.rcMessagesLock.Lock()
 	if len(r.rcMessages) > 0 {
 		for x := range r.rcMessages {
+			if !r.shown(&r.rcMessages[x]) {
+				continue
+			}
+
 			prefix := ""
 			color := colornames.White
 
@@ -144,6 +219,7 @@ func (r *raceControlMessages) formatMessages() []giu.Widget {
 							r.rcMessages[x].Timestamp.In(r.dataSrc.CircuitTimezone()).
 								Format("15:04:05"), r.rcMessages[x].Msg)).Wrapped(true))
 			}
+			msgs = append(msgs, giu.Tooltip(rcmDetails(&r.rcMessages[x])))
 		}
 	}
 	r.rcMessagesLock.Unlock()
@@ -151,6 +227,78 @@ func (r *raceControlMessages) formatMessages() []giu.Widget {
 	return msgs
 }
 
+// rcmDetails describes what was found in the message, like "Penalties - Cars 1, 44 - Turn 4 - Lap 7"
+func rcmDetails(msg *raceControl.Message) string {
+	details := []string{msg.Category.String()}
+
+	numbers := func(singular string, plural string, values []int) string {
+		text := make([]string, 0, len(values))
+		for _, value := range values {
+			text = append(text, fmt.Sprintf("%d", value))
+		}
+		if len(values) == 1 {
+			return singular + " " + text[0]
+		}
+		return plural + " " + strings.Join(text, ", ")
+	}
+
+	if len(msg.Cars) > 0 {
+		details = append(details, numbers("Car", "Cars", msg.Cars))
+	}
+	if len(msg.Turns) > 0 {
+		details = append(details, numbers("Turn", "Turns", msg.Turns))
+	}
+	if msg.Lap > 0 {
+		details = append(details, fmt.Sprintf("Lap %d", msg.Lap))
+	}
+	return strings.Join(details, " - ")
+}
+
+type rcmCategorySelectWidget struct {
+	shown     map[raceControl.Category]bool
+	shownLock sync.Mutex
+	onChange  func()
+}
+
+func (c *rcmCategorySelectWidget) isShown(category raceControl.Category) bool {
+	c.shownLock.Lock()
+	defer c.shownLock.Unlock()
+
+	return c.shown[category]
+}
+
+func (c *rcmCategorySelectWidget) Build() {
+	categories := raceControl.Categories()
+
+	c.shownLock.Lock()
+	visibleCount := 0
+	for _, category := range categories {
+		if c.shown[category] {
+			visibleCount++
+		}
+	}
+
+	changed := false
+	imgui.PushItemWidth(130)
+	if imgui.BeginCombo("Categories", fmt.Sprintf("%d of %d", visibleCount, len(categories))) {
+		for _, category := range categories {
+			shown := c.shown[category]
+			if imgui.Checkbox(category.String(), &shown) {
+				c.shown[category] = shown
+				changed = true
+			}
+		}
+
+		imgui.EndCombo()
+	}
+	imgui.PopItemWidth()
+	c.shownLock.Unlock()
+
+	if changed {
+		c.onChange()
+	}
+}
+
 type ScrollToBottomWidget struct {
 	id string
 }
@@ -16,12 +16,18 @@
 package panel
 
 import (
+	"fmt"
+	"image/color"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
 	"github.com/AllenDang/giu"
 	"github.com/f1gopher/f1gopherlib"
 	"github.com/f1gopher/f1gopherlib/Messages"
 	"github.com/ungerik/go-cairo"
-	"image/color"
-	"sort"
 )
 
 type info struct {
@@ -29,28 +35,61 @@ type info struct {
 	number    int
 	name      string
 	positions []int
+	gaps      []time.Duration
+	tires     []Messages.TireType
+
+	pitstops int
+	pitLaps  []int
+	// A car can retire on lap zero so the lap isn't enough to say if it has retired
+	retired    bool
+	retiredLap int
 }
 
+type lapNeutralisation int
+
+const (
+	notNeutralised lapNeutralisation = iota
+	virtualSafetyCarLap
+	safetyCarLap
+	redFlagLap
+)
+
 type racePosition struct {
 	driverData  map[int]*info
 	orderedData []*info
 	totalLaps   int
+	dataLock    sync.Mutex
+
+	// Safety car/red flag state for each lap, index is the lap number
+	neutralisedLaps []lapNeutralisation
+
+	focus           *DriverFocus
+	drawnFocus      int
+	drawnComparison int
 
-	plot         *plot
-	yAxisPos     float64
-	firstDriverY float64
-	xGap         float64
-	yGap         float64
-	endXPos      float64
+	chart *chart
+	plot  *plot
 }
 
-func CreateRacePosition() Panel {
+func CreateRacePosition(focus *DriverFocus) Panel {
 	panel := &racePosition{
 		driverData:  map[int]*info{},
 		orderedData: []*info{},
 		totalLaps:   0,
+		focus:       focus,
 	}
-	panel.plot = createPlot(panel.drawBackground, panel.drawForeground)
+	panel.chart = createChart("Race Position")
+	panel.chart.prepare = panel.prepareChart
+	panel.chart.drawBackground = panel.drawBackground
+	panel.chart.drawForeground = panel.drawForeground
+	panel.chart.hover = panel.hover
+	panel.chart.click = panel.click
+	panel.chart.x.majorTick = 1.0
+	panel.chart.y[0].majorTick = 1.0
+	panel.chart.y[0].inverted = true
+	panel.chart.y[0].format = panel.startingDriverName
+	panel.chart.y[0].labelColor = panel.startingDriverColor
+	panel.plot = panel.chart.plot
 
 	return panel
 }
@@ -67,19 +106,25 @@ func (r *racePosition) Type() Type { return RacePosition }
 
 func (r *racePosition) Init(dataSrc f1gopherlib.F1GopherLib, config PanelConfig) {
 	// Clear previous session data
+	r.dataLock.Lock()
 	r.driverData = map[int]*info{}
 	r.orderedData = []*info{}
+	r.neutralisedLaps = []lapNeutralisation{}
+	r.dataLock.Unlock()
 	r.totalLaps = 0
-	r.plot.reset()
+	r.chart.reset()
 }
 
 func (r *racePosition) ProcessDrivers(data Messages.Drivers) {
+	r.dataLock.Lock()
 	for x := range data.Drivers {
 		driverInfo := &info{
 			color:     data.Drivers[x].Color,
 			number:    data.Drivers[x].Number,
 			name:      data.Drivers[x].ShortName,
 			positions: []int{data.Drivers[x].StartPosition},
+			gaps:      []time.Duration{0},
+			tires:     []Messages.TireType{Messages.Unknown},
 		}
 
 		r.driverData[data.Drivers[x].Number] = driverInfo
@@ -89,6 +134,7 @@ func (r *racePosition) ProcessDrivers(data Messages.Drivers) {
 	sort.Slice(r.orderedData, func(i, j int) bool {
 		return r.orderedData[i].positions[0] < r.orderedData[j].positions[0]
 	})
+	r.dataLock.Unlock()
 
 	r.plot.refreshBackground()
 }
@@ -98,89 +144,264 @@ func (r *racePosition) ProcessEvent(data Messages.Event) {
 		r.totalLaps = data.TotalLaps
 		r.plot.refreshBackground()
 	}
+
+	if data.CurrentLap <= 0 {
+		return
+	}
+
+	state := notNeutralised
+	switch {
+	case data.TrackStatus == Messages.RedFlag:
+		state = redFlagLap
+	case data.SafetyCar == Messages.SafetyCar || data.SafetyCar == Messages.SafetyCarEnding:
+		state = safetyCarLap
+	case data.SafetyCar == Messages.VirtualSafetyCar || data.SafetyCar == Messages.VirtualSafetyCarEnding:
+		state = virtualSafetyCarLap
+	}
+
+	r.dataLock.Lock()
+	for len(r.neutralisedLaps) <= data.CurrentLap {
+		r.neutralisedLaps = append(r.neutralisedLaps, notNeutralised)
+	}
+
+	// Keep the most severe state seen during the lap
+	if state > r.neutralisedLaps[data.CurrentLap] {
+		r.neutralisedLaps[data.CurrentLap] = state
+		r.dataLock.Unlock()
+		r.plot.refreshBackground()
+		return
+	}
+	r.dataLock.Unlock()
 }
 
 func (r *racePosition) ProcessTiming(data Messages.Timing) {
+	r.dataLock.Lock()
+	defer r.dataLock.Unlock()
+
 	driverInfo, exists := r.driverData[data.Number]
 
 	if !exists {
 		return
 	}
 
+	if data.Pitstops > driverInfo.pitstops {
+		driverInfo.pitstops = data.Pitstops
+		driverInfo.pitLaps = append(driverInfo.pitLaps, data.Lap)
+		r.plot.refreshForeground()
+	}
+
+	if !driverInfo.retired && (data.Location == Messages.Stopped || data.Location == Messages.OutOfRace) {
+		driverInfo.retired = true
+		driverInfo.retiredLap = len(driverInfo.positions) - 1
+		r.plot.refreshForeground()
+	}
+
 	count := len(driverInfo.positions)
 	if count == data.Lap {
 		driverInfo.positions = append(driverInfo.positions, data.Position)
+		driverInfo.gaps = append(driverInfo.gaps, data.GapToLeader)
+		driverInfo.tires = append(driverInfo.tires, data.Tire)
 		r.plot.refreshForeground()
 	}
 }
 
+func (r *racePosition) RenderChart(width int, height int, svg bool) ([]byte, error) {
+	return r.chart.render(width, height, svg)
+}
+
 func (r *racePosition) Draw(width int, height int) []giu.Widget {
-	return []giu.Widget{
-		r.plot.draw(width-16, height-16),
+	// Redraw when the focused drivers change so they are highlighted
+	if r.focus.changedSince(&r.drawnFocus, &r.drawnComparison) {
+		r.chart.refreshForeground()
 	}
+
+	return append([]giu.Widget{giu.Row(r.chart.toolbar()...)}, r.chart.draw(width-16, height-38)...)
+}
+
+// driverAt returns the driver who was in the position on the given lap. Caller must hold the data lock.
+func (r *racePosition) driverAt(lap int, position int) *info {
+	if lap < 0 {
+		return nil
+	}
+
+	for _, driver := range r.orderedData {
+		if lap < len(driver.positions) && driver.positions[lap] == position {
+			return driver
+		}
+	}
+
+	return nil
+}
+
+// pointAt returns the lap and position for a pixel location on the chart
+func (r *racePosition) pointAt(xPos float64, yPos float64) (lap int, position int) {
+	return int(math.Round(r.chart.xValue(xPos))), int(math.Round(r.chart.yValue(0, yPos)))
+}
+
+// hover shows details for the driver under the mouse
+func (r *racePosition) hover(xPos float64, yPos float64) string {
+	lap, position := r.pointAt(xPos, yPos)
+
+	r.dataLock.Lock()
+	defer r.dataLock.Unlock()
+
+	driver := r.driverAt(lap, position)
+	if driver == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%s\nLap: %d\nPosition: %d\nGap: %s\nTire: %s",
+		driver.name,
+		lap,
+		position,
+		strings.TrimSpace(fmtDuration(driver.gaps[lap])),
+		driver.tires[lap].String())
+}
+
+// click focuses the driver under the mouse
+func (r *racePosition) click(xPos float64, yPos float64) {
+	lap, position := r.pointAt(xPos, yPos)
+
+	r.dataLock.Lock()
+	driver := r.driverAt(lap, position)
+	r.dataLock.Unlock()
+
+	if driver != nil {
+		r.focus.Select(driver.number)
+	}
+}
+
+// startingDriverName labels the Y axis with the driver who started in each position
+func (r *racePosition) startingDriverName(value float64) string {
+	r.dataLock.Lock()
+	defer r.dataLock.Unlock()
+
+	index := int(math.Round(value)) - 1
+	if index < 0 || index >= len(r.orderedData) {
+		return ""
+	}
+	return r.orderedData[index].name
+}
+
+func (r *racePosition) startingDriverColor(value float64) (color.RGBA, bool) {
+	r.dataLock.Lock()
+	defer r.dataLock.Unlock()
+
+	index := int(math.Round(value)) - 1
+	if index < 0 || index >= len(r.orderedData) {
+		return color.RGBA{}, false
+	}
+	return r.orderedData[index].color, true
+}
+
+func (r *racePosition) prepareChart() {
+	r.dataLock.Lock()
+	defer r.dataLock.Unlock()
+
+	if len(r.orderedData) == 0 {
+		r.chart.message = "Waiting for data..."
+		return
+	}
+
+	r.chart.message = ""
+	r.chart.setXRange(0, float64(max(r.totalLaps, 1)))
+	r.chart.setYRange(0, 0.5, float64(len(r.orderedData))+0.5)
 }
 
 func (r *racePosition) drawBackground(dc *cairo.Surface) {
-	width := float64(dc.GetWidth())
-	height := float64(dc.GetHeight())
-
-	// Leave border of 20px all around the chart
-	margin := 10.0
-	// X location for Y axis
-	r.yAxisPos = margin + 30
-	// X pos end of X axis location
-	r.endXPos = width - margin
-	r.xGap = (r.endXPos - r.yAxisPos) / float64(r.totalLaps+1)
-
-	// Black background
-	dc.SetSourceRGB(0.0, 0.0, 0.0)
-	dc.Rectangle(0, 0, width, height)
-	dc.Fill()
-	dc.Stroke()
-
-	// X & Y Axis lines
-	dc.SetSourceRGB(1.0, 1.0, 1.0)
-	dc.MoveTo(r.yAxisPos, margin)
-	dc.LineTo(r.yAxisPos, height-margin)
-	dc.LineTo(width-margin, height-margin)
-	dc.Stroke()
-
-	// Driver names for Y axis
-	numDrivers := len(r.orderedData)
-	yAxisLength := height - margin - margin
-	r.yGap = yAxisLength / float64(numDrivers)
-	r.firstDriverY = margin + r.yGap/2
-	currentYPos := r.firstDriverY + 2 // add half of text height to centre label
-
-	for x := range r.orderedData {
-		dc.SetSourceRGBA(floatColor(r.orderedData[x].color))
-		dc.MoveTo(margin, currentYPos)
-		dc.ShowText(r.orderedData[x].name)
-		currentYPos += r.yGap
+	r.dataLock.Lock()
+	defer r.dataLock.Unlock()
+
+	top := r.chart.y[0].start
+	bottom := r.chart.y[0].end
+
+	// Shade the laps run under the safety car or red flag
+	for lap, state := range r.neutralisedLaps {
+		switch state {
+		case notNeutralised:
+			continue
+		case virtualSafetyCarLap:
+			dc.SetSourceRGBA(1.0, 1.0, 0.0, 0.15)
+		case safetyCarLap:
+			dc.SetSourceRGBA(1.0, 0.65, 0.0, 0.25)
+		case redFlagLap:
+			dc.SetSourceRGBA(1.0, 0.0, 0.0, 0.3)
+		}
+
+		start := r.chart.xPos(float64(lap - 1))
+		dc.Rectangle(start, top, r.chart.xPos(float64(lap))-start, bottom-top)
+		dc.Fill()
 	}
 }
 
 func (r *racePosition) drawForeground(dc *cairo.Surface) {
-	currentStartYPos := r.firstDriverY
+	r.dataLock.Lock()
+	defer r.dataLock.Unlock()
+
+	highlighted := func(driverNumber int) bool {
+		return driverNumber == r.drawnFocus || driverNumber == r.drawnComparison
+	}
 
-	// Draw a position line for each driver
-	for x := range r.orderedData {
-		currentXPos := r.yAxisPos
+	// Draw a position line for each driver, the highlighted drivers are drawn last so they are on top
+	drivers := append([]*info{}, r.orderedData...)
+	sort.SliceStable(drivers, func(i, j int) bool {
+		return !highlighted(drivers[i].number) && highlighted(drivers[j].number)
+	})
+
+	for x := range drivers {
+		driver := drivers[x]
 
 		// If not enough positions then draw nothing
-		if len(r.orderedData[x].positions) <= 1 {
+		if len(driver.positions) <= 1 {
 			continue
 		}
 
-		// Draw line from start position to current position
-		dc.SetSourceRGBA(floatColor(r.orderedData[x].color))
-		dc.MoveTo(currentXPos, currentStartYPos)
-		currentStartYPos += r.yGap
+		red, green, blue, alpha := floatColor(driver.color)
+		lineWidth := 2.0
+		if r.drawnFocus != NoDriver || r.drawnComparison != NoDriver {
+			if highlighted(driver.number) {
+				lineWidth = 4.0
+			} else {
+				alpha = 0.2
+			}
+		}
 
-		for _, pos := range r.orderedData[x].positions {
-			currentXPos += r.xGap
-			dc.LineTo(currentXPos, r.firstDriverY+(float64(pos-1)*r.yGap))
+		// Draw line from start position to current position
+		dc.SetLineWidth(lineWidth)
+		dc.SetSourceRGBA(red, green, blue, alpha)
+		for lap, pos := range driver.positions {
+			dc.LineTo(r.chart.xPos(float64(lap)), r.chart.yPos(0, float64(pos)))
 		}
 		dc.Stroke()
+		dc.SetLineWidth(2.0)
+
+		// Pitstop markers
+		for _, lap := range driver.pitLaps {
+			if lap >= len(driver.positions) {
+				continue
+			}
+
+			xPos := r.chart.xPos(float64(lap))
+			yPos := r.chart.yPos(0, float64(driver.positions[lap]))
+			dc.NewPath()
+			dc.Arc(xPos, yPos, 4, 0, 2*math.Pi)
+			dc.Fill()
+			dc.SetSourceRGBA(1.0, 1.0, 1.0, alpha)
+			dc.MoveTo(xPos-3, yPos-6)
+			dc.ShowText("P")
+			dc.SetSourceRGBA(red, green, blue, alpha)
+		}
+
+		// Retirement marker
+		if driver.retired && driver.retiredLap < len(driver.positions) {
+			xPos := r.chart.xPos(float64(driver.retiredLap))
+			yPos := r.chart.yPos(0, float64(driver.positions[driver.retiredLap]))
+			dc.SetSourceRGBA(1.0, 0.0, 0.0, math.Max(alpha, 0.5))
+			dc.MoveTo(xPos-5, yPos-5)
+			dc.LineTo(xPos+5, yPos+5)
+			dc.MoveTo(xPos+5, yPos-5)
+			dc.LineTo(xPos-5, yPos+5)
+			dc.Stroke()
+		}
 	}
 }
@@ -0,0 +1,259 @@
+// F1Gopher - Copyright (C) 2025 f1gopher
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package panel
+
+import (
+	"f1gopher/raceControl"
+	"fmt"
+	"image/color"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AllenDang/giu"
+	"github.com/f1gopher/f1gopherlib"
+	"github.com/f1gopher/f1gopherlib/Messages"
+	"golang.org/x/image/colornames"
+)
+
+type stewards struct {
+	focus         *DriverFocus
+	notifications *Notifications
+
+	incidents   raceControl.IncidentTracker
+	drivers     map[int]overtakesDriver
+	timing      map[int]Messages.Timing
+	isRace      bool
+	pitlaneTime time.Duration
+	now         time.Time
+	timezone    *time.Location
+	lock        sync.Mutex
+}
+
+func CreateStewards(focus *DriverFocus, notifications *Notifications) Panel {
+	return &stewards{
+		focus:         focus,
+		notifications: notifications,
+		drivers:       map[int]overtakesDriver{},
+		timing:        map[int]Messages.Timing{},
+	}
+}
+
+func (s *stewards) ProcessEvent(data Messages.Event)         {}
+func (s *stewards) ProcessWeather(data Messages.Weather)     {}
+func (s *stewards) ProcessRadio(data Messages.Radio)         {}
+func (s *stewards) ProcessLocation(data Messages.Location)   {}
+func (s *stewards) ProcessTelemetry(data Messages.Telemetry) {}
+func (s *stewards) Close()                                   {}
+
+func (s *stewards) Type() Type { return Stewards }
+
+func (s *stewards) Init(dataSrc f1gopherlib.F1GopherLib, config PanelConfig) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.incidents = raceControl.IncidentTracker{}
+	s.drivers = map[int]overtakesDriver{}
+	s.timing = map[int]Messages.Timing{}
+	s.isRace = dataSrc.Session() == Messages.RaceSession || dataSrc.Session() == Messages.SprintSession
+	s.pitlaneTime = dataSrc.TimeLostInPitlane()
+	s.now = time.Time{}
+	s.timezone = dataSrc.CircuitTimezone()
+}
+
+func (s *stewards) ProcessDrivers(data Messages.Drivers) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, driver := range data.Drivers {
+		s.drivers[driver.Number] = overtakesDriver{name: driver.ShortName, color: driver.Color}
+	}
+}
+
+func (s *stewards) ProcessEventTime(data Messages.EventTime) {
+	s.lock.Lock()
+	s.now = data.Timestamp
+	s.lock.Unlock()
+}
+
+func (s *stewards) ProcessTiming(data Messages.Timing) {
+	s.lock.Lock()
+	s.timing[data.Number] = data
+	s.lock.Unlock()
+}
+
+func (s *stewards) ProcessRaceControlMessages(data Messages.RaceControlMessage) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	incident, isStewards := s.incidents.Process(raceControl.Parse(data))
+	if !isStewards || incident.State != raceControl.Penalised || incident.PenaltyServed || !s.isRace {
+		return
+	}
+
+	// Let the user know when a penalty would change the running order
+	penalty := incident.PenaltyTime
+	if incident.PitPenalty {
+		penalty += s.pitlaneTime
+	}
+	if penalty == 0 {
+		return
+	}
+
+	for _, driverNumber := range incident.Penalised {
+		current := s.timing[driverNumber].Position
+		position, known := positionAfterPenalty(s.timing, driverNumber, penalty)
+		if !known || position == current {
+			continue
+		}
+
+		s.notifications.Notify(
+			fmt.Sprintf("%s %s - P%d to P%d", s.driver(driverNumber).name, incident.Decision, current, position),
+			colornames.Orange)
+	}
+}
+
+// positionAfterPenalty returns where the car would be if the penalty was added to its race time now. Cars that have
+// been lapped don't have a gap to the leader so aren't included.
+func positionAfterPenalty(timing map[int]Messages.Timing, driverNumber int, penalty time.Duration) (int, bool) {
+	car, exists := timing[driverNumber]
+	if !exists || car.Position == 0 || (car.Position > 1 && car.GapToLeader <= 0) {
+		return 0, false
+	}
+
+	position := car.Position
+	for _, other := range timing {
+		if other.Position <= car.Position || other.GapToLeader <= 0 ||
+			other.Location == Messages.Stopped || other.Location == Messages.OutOfRace {
+			continue
+		}
+
+		if other.GapToLeader-car.GapToLeader < penalty {
+			position++
+		}
+	}
+	return position, true
+}
+
+// driver returns the name and color for the car number. Caller must hold the lock.
+func (s *stewards) driver(driverNumber int) overtakesDriver {
+	driver, exists := s.drivers[driverNumber]
+	if !exists {
+		return overtakesDriver{name: fmt.Sprintf("%d", driverNumber), color: colornames.White}
+	}
+	return driver
+}
+
+func incidentStateColor(state raceControl.IncidentState) color.RGBA {
+	switch state {
+	case raceControl.UnderInvestigation:
+		return colornames.Orange
+	case raceControl.InvestigatedAfterSession:
+		return colornames.Yellow
+	case raceControl.NoFurtherAction:
+		return colornames.Green
+	case raceControl.Penalised:
+		return colornames.Red
+	}
+	return colornames.White
+}
+
+func (s *stewards) Draw(width int, height int) []giu.Widget {
+	s.lock.Lock()
+	incidents := s.incidents.All()
+	now := s.now
+	timezone := s.timezone
+
+	// Open incidents oldest first then the decisions newest first
+	sort.SliceStable(incidents, func(i, j int) bool {
+		a, b := incidents[i], incidents[j]
+		if a.State.Decided() != b.State.Decided() {
+			return !a.State.Decided()
+		}
+		if a.State.Decided() {
+			return a.Decided.After(b.Decided)
+		}
+		return a.Opened.Before(b.Opened)
+	})
+
+	rows := make([]*giu.TableRowWidget, 0, len(incidents))
+	openCount := 0
+	for x := range incidents {
+		incident := &incidents[x]
+		if !incident.State.Decided() {
+			openCount++
+		}
+
+		names := make([]string, 0, len(incident.Cars))
+		for _, driverNumber := range incident.Cars {
+			names = append(names, s.driver(driverNumber).name)
+		}
+
+		decision := incident.Decision
+		if incident.PenaltyServed {
+			decision += " (Served)"
+		}
+
+		history := make([]string, 0, len(incident.Messages))
+		for _, msg := range incident.Messages {
+			history = append(history, fmt.Sprintf("%s - %s", msg.Timestamp.In(timezone).Format("15:04:05"), msg.Msg))
+		}
+
+		focusDriver := NoDriver
+		if len(incident.Cars) > 0 {
+			focusDriver = incident.Cars[0]
+		}
+
+		row := giu.TableRow(
+			giu.Selectable(incident.Opened.In(timezone).Format("15:04:05")).
+				Flags(giu.SelectableFlagsSpanAllColumns).
+				OnClick(func() { s.focus.Select(focusDriver) }),
+			giu.Tooltip(strings.Join(history, "\n")),
+			giu.Label(strings.Join(names, ", ")),
+			giu.Label(incident.Description),
+			giu.Label(incident.Reason),
+			giu.Style().SetColor(giu.StyleColorText, incidentStateColor(incident.State)).To(giu.Label(incident.State.String())),
+			giu.Label(decision),
+			giu.Label(incident.Elapsed(now).Truncate(time.Second).String()),
+		)
+		for _, driverNumber := range incident.Cars {
+			if focusColor, focused := s.focus.highlight(driverNumber); focused {
+				row = row.BgColor(focusColor)
+				break
+			}
+		}
+		rows = append(rows, row)
+	}
+	s.lock.Unlock()
+
+	return []giu.Widget{
+		giu.Labelf("Open: %d  Decided: %d", openCount, len(incidents)-openCount),
+		giu.Table().FastMode(true).
+			Flags(giu.TableFlagsResizable|giu.TableFlagsSizingFixedSame|giu.TableFlagsScrollY).
+			Size(float32(width-16), float32(height-40)).
+			Columns(
+				giu.TableColumn("Time").InnerWidthOrWeight(60),
+				giu.TableColumn("Cars").InnerWidthOrWeight(70),
+				giu.TableColumn("Incident").InnerWidthOrWeight(300),
+				giu.TableColumn("Reason").InnerWidthOrWeight(200),
+				giu.TableColumn("State").InnerWidthOrWeight(120),
+				giu.TableColumn("Decision").InnerWidthOrWeight(180),
+				giu.TableColumn("Elapsed").InnerWidthOrWeight(60),
+			).
+			Rows(rows...),
+	}
+}
@@ -28,12 +28,25 @@ import (
 	"github.com/hajimehoshi/go-mp3"
 )
 
+// RadioControls lets the remote control mute the team radio and other panels play a drivers messages
+type RadioControls interface {
+	IsMuted() bool
+	SetMuted(muted bool)
+	// DriverMessages returns every message from the driver this session
+	DriverMessages(driver string) []Messages.Radio
+	// Play plays the message next, even if the radio is muted
+	Play(msg Messages.Radio)
+}
+
 type teamRadio struct {
 	audioPlayer *oto.Context
+	audio       *audioOutput
 	exitSession atomic.Bool
 	wg          sync.WaitGroup
 
 	radioMsgs     []Messages.Radio
+	requested     []Messages.Radio
+	history       []Messages.Radio
 	radioMsgsLock sync.Mutex
 	radioName     string
 
@@ -42,8 +55,8 @@ type teamRadio struct {
 
 const noRadioMessage = "<no one>"
 
-func CreateTeamRadio() Panel {
-	return &teamRadio{}
+func CreateTeamRadio(audio *audioOutput) Panel {
+	return &teamRadio{audio: audio}
 }
 
 func (t *teamRadio) ProcessDrivers(data Messages.Drivers)                        {}
@@ -60,7 +73,11 @@ func (t *teamRadio) Type() Type { return TeamRadio }
 func (t *teamRadio) Init(dataSrc f1gopherlib.F1GopherLib, config PanelConfig) {
 	// Clear previous session data
 	t.radioName = noRadioMessage
+	t.radioMsgsLock.Lock()
 	t.radioMsgs = make([]Messages.Radio, 0)
+	t.requested = make([]Messages.Radio, 0)
+	t.history = make([]Messages.Radio, 0)
+	t.radioMsgsLock.Unlock()
 	t.exitSession.Store(false)
 	t.isMuted = true
 
@@ -68,8 +85,8 @@ func (t *teamRadio) Init(dataSrc f1gopherlib.F1GopherLib, config PanelConfig) {
 	var err error
 	var ready chan struct{}
 	otoConfig := &oto.NewContextOptions{
-		SampleRate:   48000,
-		ChannelCount: 2,
+		SampleRate:   audioSampleRate,
+		ChannelCount: audioChannels,
 	}
 	t.audioPlayer, ready, err = oto.NewContext(otoConfig)
 	if err != nil {
@@ -78,18 +95,50 @@ func (t *teamRadio) Init(dataSrc f1gopherlib.F1GopherLib, config PanelConfig) {
 	}
 	<-ready
 
+	// The audio context is shared with the alerts
+	t.audio.set(t.audioPlayer)
+
 	go t.playTeamRadio()
 }
 
 func (t *teamRadio) ProcessRadio(data Messages.Radio) {
 	t.radioMsgsLock.Lock()
 	t.radioMsgs = append(t.radioMsgs, data)
+	t.history = append(t.history, data)
+	t.radioMsgsLock.Unlock()
+}
+
+func (t *teamRadio) DriverMessages(driver string) []Messages.Radio {
+	t.radioMsgsLock.Lock()
+	defer t.radioMsgsLock.Unlock()
+
+	messages := []Messages.Radio{}
+	for _, msg := range t.history {
+		if msg.Driver == driver {
+			messages = append(messages, msg)
+		}
+	}
+	return messages
+}
+
+func (t *teamRadio) Play(msg Messages.Radio) {
+	t.radioMsgsLock.Lock()
+	t.requested = append(t.requested, msg)
 	t.radioMsgsLock.Unlock()
 }
 
+func (t *teamRadio) IsMuted() bool {
+	return t.isMuted
+}
+
+func (t *teamRadio) SetMuted(muted bool) {
+	t.isMuted = muted
+}
+
 func (t *teamRadio) Close() {
 	// Tell audio player to pause and then wait for it to finish
 	t.exitSession.Store(true)
+	t.audio.set(nil)
 	t.audioPlayer.Suspend()
 	t.wg.Wait()
 	t.audioPlayer = nil
@@ -115,14 +164,9 @@ func (t *teamRadio) playTeamRadio() {
 
 	for !t.exitSession.Load() {
 
-		if len(t.radioMsgs) > 0 {
-			t.radioMsgsLock.Lock()
-			currentMsg := t.radioMsgs[0]
-			t.radioMsgs = t.radioMsgs[1:]
-			t.radioMsgsLock.Unlock()
-
-			// If we aren't muted then play the current message
-			if !t.isMuted && t.play(currentMsg) {
+		if currentMsg, requested, exists := t.next(); exists {
+			// If we aren't muted then play the current message, requested messages are always played
+			if (requested || !t.isMuted) && t.play(currentMsg) {
 				return
 			}
 		}
@@ -131,6 +175,24 @@ func (t *teamRadio) playTeamRadio() {
 	}
 }
 
+// next returns the next message to play, messages requested by other panels go first
+func (t *teamRadio) next() (msg Messages.Radio, requested bool, exists bool) {
+	t.radioMsgsLock.Lock()
+	defer t.radioMsgsLock.Unlock()
+
+	if len(t.requested) > 0 {
+		msg = t.requested[0]
+		t.requested = t.requested[1:]
+		return msg, true, true
+	}
+	if len(t.radioMsgs) > 0 {
+		msg = t.radioMsgs[0]
+		t.radioMsgs = t.radioMsgs[1:]
+		return msg, false, true
+	}
+	return msg, false, false
+}
+
 func (t *teamRadio) play(currentMsg Messages.Radio) bool {
 	// Handle any dodgy mp3 data that has been corrupted by just ignoring the error and not falling over
 	defer func() {
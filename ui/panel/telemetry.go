@@ -16,13 +16,16 @@
 package panel
 
 import (
+	"f1gopher/regulations"
 	"fmt"
 	"github.com/AllenDang/giu"
 	"github.com/f1gopher/f1gopherlib"
 	"github.com/f1gopher/f1gopherlib/Messages"
 	"github.com/ungerik/go-cairo"
 	"image/color"
+	"math"
 	"sort"
+	"strings"
 	"time"
 )
 
@@ -35,11 +38,9 @@ type telemetryInfo struct {
 type channelConfig struct {
 	name    string
 	enabled bool
+	// Not sent for this session so can't be selected
+	unavailable bool
 
-	top                float64
-	bottom             float64
-	gap                float64
-	height             float64
 	maxValue           float64
 	majorTickIncrement float64
 
@@ -53,11 +54,14 @@ type channelConfig struct {
 
 type telemetry struct {
 	dataSrc f1gopherlib.F1GopherLib
+	sources *TelemetrySources
 
 	data                 map[int]*telemetryInfo
 	driverNames          []string
 	selectedDriver       int32
 	selectedDriverNumber int
+	focus                *DriverFocus
+	pinned               bool
 
 	rpm      *circularBuffer[int16]
 	speed    *circularBuffer[float32]
@@ -72,22 +76,19 @@ type telemetry struct {
 
 	channelSelect *channelDisplaySelectWidget
 
-	plot         *plot
-	yAxisPos     float64
-	xGap         float64
-	endXPos      float64
-	yBottom      float64
-	xWidth       float64
-	summaryWidth float64
+	chart *chart
+	plot  *plot
 
 	channels []channelConfig
 }
 
-func CreateTelemetry() Panel {
+func CreateTelemetry(focus *DriverFocus, sources *TelemetrySources) Panel {
 	const bufferSize = 150
 
 	panel := &telemetry{
-		data: map[int]*telemetryInfo{},
+		data:    map[int]*telemetryInfo{},
+		focus:   focus,
+		sources: sources,
 		channelSelect: &channelDisplaySelectWidget{
 			id: "telemetryChannelSelect",
 		},
@@ -99,7 +100,15 @@ func CreateTelemetry() Panel {
 		drs:      createCircularBuffer[bool](bufferSize),
 		time:     createCircularBuffer[time.Time](bufferSize),
 	}
-	panel.plot = createPlot(panel.drawBackground, panel.drawForeground)
+	panel.chart = createChart("Telemetry")
+	panel.chart.prepare = panel.prepareChart
+	panel.chart.drawForeground = panel.drawForeground
+	panel.chart.drawOverlay = panel.drawSummary
+	panel.chart.hover = panel.hover
+	panel.chart.rightMargin = 60
+	panel.chart.x.majorTick = 10.0
+	panel.chart.x.format = panel.formatTime
+	panel.plot = panel.chart.plot
 	panel.channels = []channelConfig{
 		{
 			name:               "Brake",
@@ -200,8 +209,17 @@ func (t *telemetry) Init(dataSrc f1gopherlib.F1GopherLib, config PanelConfig) {
 	t.driverNames = nil
 	t.selectedDriver = NothingSelected
 	t.selectedDriverNumber = NothingSelected
+	t.pinned = false
 
-	t.plot.reset()
+	// The DRS channel isn't used once DRS is replaced by the overtake mode
+	hasDRS := regulations.OvertakeAidForSeason(dataSrc.SessionStart().Year()) == regulations.DRS
+	for x := range t.channels {
+		if t.channels[x].name == "DRS" {
+			t.channels[x].unavailable = !hasDRS
+		}
+	}
+
+	t.chart.reset()
 }
 
 func (t *telemetry) Close() {
@@ -241,218 +259,194 @@ func (t *telemetry) ProcessTelemetry(data Messages.Telemetry) {
 	t.drs.add(data.DRS)
 	t.time.add(data.Timestamp)
 
-	if t.time.count() == 1 {
-		t.plot.refreshBackground()
-	} else {
-		t.plot.refreshForeground()
-	}
+	// The time axis moves with every sample
+	t.plot.refreshBackground()
 }
 
 func (t *telemetry) ProcessEventTime(data Messages.EventTime) {
 	t.currentTime = data.Timestamp
 }
 
+func (t *telemetry) RenderChart(width int, height int, svg bool) ([]byte, error) {
+	return t.chart.render(width, height, svg)
+}
+
 func (t *telemetry) Draw(width int, height int) []giu.Widget {
+	// Follow the focused driver unless pinned to a driver
+	if focused := t.focus.Focused(); !t.pinned && focused != NoDriver && focused != t.selectedDriverNumber {
+		t.selectDriver(focused)
+	}
+
 	driverName := "<none>"
 	if t.selectedDriver != NothingSelected {
 		driverName = t.driverNames[t.selectedDriver]
 	}
 
-	return []giu.Widget{
-		giu.Row(
+	return append([]giu.Widget{
+		giu.Row(append([]giu.Widget{
 			giu.Combo("Driver", driverName, t.driverNames, &t.selectedDriver).OnChange(func() {
 				for num, driver := range t.data {
 					if driver.name == t.driverNames[t.selectedDriver] {
-						t.selectedDriverNumber = num
-
-						// Request data from newly selected driver only
-						t.dataSrc.SelectTelemetrySources([]int{num})
-
-						// Clea existing data
-						t.rpm.reset()
-						t.speed.reset()
-						t.gear.reset()
-						t.throttle.reset()
-						t.brake.reset()
-						t.drs.reset()
-						t.time.reset()
-
-						t.plot.refreshBackground()
+						// Choosing a driver stops following the focused driver
+						t.pinned = true
+						t.selectDriver(num)
 						break
 					}
 				}
 			}).Size(100),
+			giu.Checkbox("Pin", &t.pinned),
 			t.channelSelect,
-		),
-		t.plot.draw(width-16, height-38),
-	}
+		}, t.chart.toolbar()...)...),
+	}, t.chart.draw(width-16, height-38)...)
 }
 
-func (t *telemetry) drawBackground(dc *cairo.Surface) {
-	width := float64(dc.GetWidth())
-	height := float64(dc.GetHeight())
-
-	// If no driver selected then draw nothing
-	if t.selectedDriver == NothingSelected {
-		// Black background
-		dc.SetSourceRGB(0.0, 0.0, 0.0)
-		dc.Rectangle(0, 0, width, height)
-		dc.Fill()
-		dc.Stroke()
-
-		dc.SetSourceRGB(1.0, 1.0, 1.0)
-		dc.MoveTo((width/2)-50, height/2)
-		dc.ShowText("Select Driver")
+func (t *telemetry) selectDriver(driverNumber int) {
+	driver, exists := t.data[driverNumber]
+	if !exists {
 		return
 	}
 
-	// Leave border all around the chart
-	margin := 10.0
-	t.yBottom = height - margin - 10
-	yAxisLength := t.yBottom - margin
-
-	// Update top and bottom positions for each channel
-	enabledChannels := 0.0
-	for x := range t.channels {
-		if !t.channels[x].enabled {
-			continue
+	for x := range t.driverNames {
+		if t.driverNames[x] == driver.name {
+			t.selectedDriver = int32(x)
+			break
 		}
-
-		enabledChannels++
 	}
+	t.selectedDriverNumber = driverNumber
 
-	top := margin
-	for x := range t.channels {
-		if !t.channels[x].enabled {
-			continue
-		}
+	// Request data from newly selected driver only, other panels may still want telemetry for their drivers
+	t.sources.selectDrivers(Telemetry, driverNumber)
+
+	// Clear existing data
+	t.rpm.reset()
+	t.speed.reset()
+	t.gear.reset()
+	t.throttle.reset()
+	t.brake.reset()
+	t.drs.reset()
+	t.time.reset()
 
-		t.channels[x].top = top
+	t.plot.refreshBackground()
+}
 
-		// More accurate to just make the last one match the yBottom
-		if x == len(t.channels)-1 {
-			t.channels[x].bottom = t.yBottom
-		} else {
-			plotHeight := (yAxisLength - (7 * enabledChannels)) * (1.0 / enabledChannels)
-			t.channels[x].bottom = (t.channels[x].top + plotHeight) - 1
-		}
+// timeValue converts a time to a value for the X axis
+func timeValue(value time.Time) float64 {
+	return float64(value.UnixNano()) / float64(time.Second)
+}
 
-		top = t.channels[x].bottom + 7
+func (t *telemetry) formatTime(value float64) string {
+	seconds, fraction := math.Modf(value)
+	return time.Unix(int64(seconds), int64(fraction*float64(time.Second))).In(t.circuitTimezone).Format("15:04:05")
+}
 
-		t.channels[x].gap = (t.channels[x].bottom - t.channels[x].top) / t.channels[x].maxValue
+func (t *telemetry) prepareChart() {
+	// If no driver selected then draw nothing
+	if t.selectedDriver == NothingSelected {
+		t.chart.message = "Select Driver"
+		return
 	}
+	t.chart.message = ""
 
-	t.summaryWidth = 60
-	// X location for Y axis
-	t.yAxisPos = margin + 40
-	// X pos end of X axis location
-	t.endXPos = width - margin - t.summaryWidth
-	t.xWidth = t.endXPos - t.yAxisPos
-
-	// Black background
-	dc.SetSourceRGB(0.0, 0.0, 0.0)
-	dc.Rectangle(0, 0, width, height)
-	dc.Fill()
-	dc.Stroke()
-
-	// X Axis line
-	dc.SetSourceRGB(1.0, 1.0, 1.0)
-	dc.MoveTo(t.yAxisPos, t.yBottom)
-	dc.LineTo(t.endXPos, t.yBottom)
-	dc.Stroke()
+	// Show the last two minutes of data
+	const xAxisSecondsLength = 120.0
+	end := timeValue(t.currentTime)
+	t.chart.setXRange(end-xAxisSecondsLength, end)
 
+	// One Y axis for each enabled channel
+	t.chart.y = []*chartAxis{}
 	for x := range t.channels {
-		if !t.channels[x].enabled {
+		if !t.channels[x].enabled || t.channels[x].unavailable {
 			continue
 		}
 
-		dc.SetSourceRGB(t.channels[x].colorR, t.channels[x].colorG, t.channels[x].colorB)
-
-		drawYAxis(
-			dc,
-			t.yAxisPos,
-			t.channels[x].top,
-			t.channels[x].bottom,
-			t.channels[x].bottom,
-			0.0,
-			t.channels[x].gap,
-			t.channels[x].majorTickIncrement)
+		channel := &t.channels[x]
+		axis := &chartAxis{
+			name:      channel.name,
+			color:     color.RGBA{R: uint8(channel.colorR * 255), G: uint8(channel.colorG * 255), B: uint8(channel.colorB * 255), A: 255},
+			majorTick: channel.majorTickIncrement,
+			format:    func(v float64) string { return fmt.Sprintf("%.0f", v) },
+		}
+		t.chart.y = append(t.chart.y, axis)
+		t.chart.setYRange(len(t.chart.y)-1, 0, channel.maxValue)
+	}
+
+	if len(t.chart.y) == 0 {
+		t.chart.message = "Select Channels"
 	}
 }
 
+// enabledChannels returns the channels in the same order as the chart Y axes
+func (t *telemetry) enabledChannels() []channelConfig {
+	result := []channelConfig{}
+	for _, channel := range t.channels {
+		if channel.enabled && !channel.unavailable {
+			result = append(result, channel)
+		}
+	}
+	return result
+}
+
 func (t *telemetry) drawForeground(dc *cairo.Surface) {
 	// If no driver selected do nothing
 	if t.selectedDriver == NothingSelected || t.time.count() == 0 {
 		return
 	}
 
-	xAxisSecondsLength := 120.0
-	xPixelsPerSecond := t.xWidth / xAxisSecondsLength
-
-	// End is a whole seconds
-	endTime := t.currentTime.Round(time.Second)
-	startTime := endTime.Add(-(time.Second * time.Duration(xAxisSecondsLength)))
-	startOffset := xPixelsPerSecond * endTime.Sub(t.currentTime).Seconds()
-
-	// Draw X axis marks and values
-	dc.SetSourceRGB(1.0, 1.0, 1.0)
-	currentTime := startTime
-	xPos := t.yAxisPos + startOffset
-	for xPos < t.endXPos {
-		if currentTime.Second()%10 != 0 {
-			xPos += xPixelsPerSecond
-			currentTime = currentTime.Add(time.Second)
-			continue
+	// Draw data channel
+	for axis, channel := range t.enabledChannels() {
+		if axis >= len(t.chart.y) {
+			break
 		}
 
-		dc.MoveTo(xPos, t.yBottom)
-		dc.LineTo(xPos, t.yBottom+5.0)
+		dc.SetSourceRGB(channel.colorR, channel.colorG, channel.colorB)
 
-		dc.MoveTo(xPos-20, t.yBottom+15)
-		dc.ShowText(currentTime.In(t.circuitTimezone).Format("15:04:05"))
+		// Data line
+		for x := 0; x < t.time.count(); x++ {
+			dc.LineTo(t.chart.xPos(timeValue(t.time.get(x))), t.chart.yPos(axis, channel.value(x)))
+		}
+		dc.Stroke()
+	}
+}
 
-		xPos += xPixelsPerSecond
-		currentTime = currentTime.Add(time.Second)
+// drawSummary shows the latest value for each channel to the right of the chart
+func (t *telemetry) drawSummary(dc *cairo.Surface) {
+	if t.time.count() == 0 {
+		return
 	}
-	dc.Stroke()
 
-	// Draw data channel
 	last := t.time.count() - 1
-	for _, channel := range t.channels {
-		if !channel.enabled {
-			continue
+	for axis, channel := range t.enabledChannels() {
+		if axis >= len(t.chart.y) {
+			break
 		}
 
 		dc.SetSourceRGB(channel.colorR, channel.colorG, channel.colorB)
+		center := t.chart.y[axis].start + ((t.chart.y[axis].end - t.chart.y[axis].start) / 2)
+		dc.MoveTo(t.chart.x.end+5, center-7)
+		dc.ShowText(channel.name)
+		dc.MoveTo(t.chart.x.end+5, center+7)
+		dc.ShowText(channel.summaryValue(last))
+		dc.Stroke()
+	}
+}
 
-		currentTime = startTime
-		xPos = t.yAxisPos + startOffset
-		first := true
+// hover shows the value of every channel for the sample closest to the mouse
+func (t *telemetry) hover(xPos float64, yPos float64) string {
+	if t.time.count() == 0 {
+		return ""
+	}
 
-		// Data line
-		for x := 0; x < t.time.count(); x++ {
-			// Don't draw every point. At most one per pixel
-			if t.time.get(x).Before(currentTime) {
-				continue
-			}
-
-			if first {
-				dc.MoveTo(xPos, channel.bottom-channel.gap*channel.value(x))
-				first = false
-			}
-
-			dc.LineTo(xPos, channel.bottom-channel.gap*channel.value(x))
-			currentTime = currentTime.Add(time.Second * 1.0)
-			xPos += xPixelsPerSecond
+	target := t.chart.xValue(xPos)
+	closest := 0
+	for x := 1; x < t.time.count(); x++ {
+		if math.Abs(timeValue(t.time.get(x))-target) < math.Abs(timeValue(t.time.get(closest))-target) {
+			closest = x
 		}
+	}
 
-		// Summary
-		center := channel.top + ((channel.bottom - channel.top) / 2)
-		dc.MoveTo(t.endXPos+5, center-7)
-		dc.ShowText(channel.name)
-		dc.MoveTo(t.endXPos+5, center+7)
-		dc.ShowText(channel.summaryValue(last))
-
-		dc.Stroke()
+	lines := []string{t.time.get(closest).In(t.circuitTimezone).Format("15:04:05.000")}
+	for _, channel := range t.enabledChannels() {
+		lines = append(lines, fmt.Sprintf("%s: %s", channel.name, channel.summaryValue(closest)))
 	}
+	return strings.Join(lines, "\n")
 }
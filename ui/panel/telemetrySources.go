@@ -0,0 +1,67 @@
+// F1Gopher - Copyright (C) 2025 f1gopher
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package panel
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/f1gopher/f1gopherlib"
+)
+
+// TelemetrySources combines the drivers each panel wants telemetry for. The data source only sends telemetry for the
+// last list of drivers it was given so panels selecting their drivers directly would replace each other's selection.
+type TelemetrySources struct {
+	dataSrc  f1gopherlib.F1GopherLib
+	selected map[Type][]int
+	lock     sync.Mutex
+}
+
+func CreateTelemetrySources() *TelemetrySources {
+	return &TelemetrySources{selected: map[Type][]int{}}
+}
+
+// Reset forgets the selected drivers for a new session
+func (t *TelemetrySources) Reset(dataSrc f1gopherlib.F1GopherLib) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.dataSrc = dataSrc
+	t.selected = map[Type][]int{}
+}
+
+// selectDrivers replaces the drivers the panel wants telemetry for and requests telemetry for the drivers every
+// panel wants
+func (t *TelemetrySources) selectDrivers(panelType Type, drivers ...int) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.selected[panelType] = drivers
+
+	all := []int{}
+	for _, selected := range t.selected {
+		for _, driver := range selected {
+			if !slices.Contains(all, driver) {
+				all = append(all, driver)
+			}
+		}
+	}
+	slices.Sort(all)
+
+	if t.dataSrc != nil {
+		t.dataSrc.SelectTelemetrySources(all)
+	}
+}
@@ -29,6 +29,9 @@ func (c *channelDisplaySelectWidget) Build() {
 	imgui.PushItemWidth(100)
 	if imgui.BeginCombo("Channels", "Select") {
 		for x := range *c.channels {
+			if (*c.channels)[x].unavailable {
+				continue
+			}
 			if imgui.Checkbox((*c.channels)[x].name, &(*c.channels)[x].enabled) {
 				c.plot.refreshBackground()
 			}
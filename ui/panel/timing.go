@@ -16,6 +16,7 @@
 package panel
 
 import (
+	"f1gopher/regulations"
 	"fmt"
 	"image/color"
 	"sort"
@@ -51,11 +52,18 @@ type timing struct {
 	isRaceSession       bool
 	isSprintRaceSession bool
 	config              PanelConfig
-	eventHasDRS         bool
+	overtakeAid         regulations.OvertakeAid
+	season              int
+	qualifyingType      regulations.QualifyingType
 
 	lastPitLossColor map[int]color.RGBA
 	lastPitLossValue map[int]string
 
+	focus       *DriverFocus
+	battles     *battleDetector
+	lapping     *lappingPredictor
+	trackLimits *trackLimitsTracker
+
 	table *giu.TableWidget
 }
 
@@ -66,22 +74,36 @@ var dropZoneBackground = color.RGBA{R: 83, G: 84, B: 78, A: 255}
 var defaultBackgroundColor = color.RGBA{R: 0, G: 0, B: 0, A: 0}
 var altDefaultBackgroundColor = color.RGBA{R: 55, G: 55, B: 55, A: 255}
 
-func CreateTiming() Panel {
+func CreateTiming(focus *DriverFocus, battles *battleDetector, lapping *lappingPredictor, trackLimits *trackLimitsTracker) Panel {
 	return &timing{
 		data:             make(map[int]Messages.Timing),
 		lastPitLossColor: make(map[int]color.RGBA),
 		lastPitLossValue: make(map[int]string),
+		focus:            focus,
+		battles:          battles,
+		lapping:          lapping,
+		trackLimits:      trackLimits,
 	}
 }
 
-func (t *timing) ProcessDrivers(data Messages.Drivers)                        {}
-func (t *timing) ProcessEventTime(data Messages.EventTime)                    {}
-func (t *timing) ProcessRaceControlMessages(data Messages.RaceControlMessage) {}
-func (t *timing) ProcessWeather(data Messages.Weather)                        {}
-func (t *timing) ProcessRadio(data Messages.Radio)                            {}
-func (t *timing) ProcessLocation(data Messages.Location)                      {}
-func (t *timing) ProcessTelemetry(data Messages.Telemetry)                    {}
-func (t *timing) Close()                                                      {}
+func (t *timing) ProcessDrivers(data Messages.Drivers)     {}
+func (t *timing) ProcessEventTime(data Messages.EventTime) {}
+func (t *timing) ProcessWeather(data Messages.Weather)     {}
+func (t *timing) ProcessRadio(data Messages.Radio)         {}
+func (t *timing) ProcessTelemetry(data Messages.Telemetry) {}
+func (t *timing) Close()                                   {}
+
+// The lapping predictor is shared with the track map and the track limits tracker is shared with the track limits,
+// improving and lap analysis panels but both are only fed from here
+
+func (t *timing) ProcessRaceControlMessages(data Messages.RaceControlMessage) {
+	t.lapping.ProcessRaceControlMessages(data)
+	t.trackLimits.ProcessRaceControlMessages(data)
+}
+
+func (t *timing) ProcessLocation(data Messages.Location) {
+	t.lapping.ProcessLocation(data)
+}
 
 func (t *timing) Type() Type { return Timing }
 
@@ -106,7 +128,11 @@ func (t *timing) Init(dataSrc f1gopherlib.F1GopherLib, config PanelConfig) {
 	t.isRaceSession = dataSrc.Session() == Messages.RaceSession
 	t.isSprintRaceSession = dataSrc.Session() == Messages.SprintSession
 	t.config = config
-	t.eventHasDRS = dataSrc.SessionStart().Year() <= 2025
+	t.season = dataSrc.SessionStart().Year()
+	t.qualifyingType = regulations.QualifyingSessionType(dataSrc.SessionStart())
+	t.overtakeAid = regulations.OvertakeAidForSeason(t.season)
+	t.lapping.Reset()
+	t.trackLimits.Init(dataSrc.SessionStart().Year(), dataSrc.Session())
 
 	t.table = giu.Table().FastMode(true).Flags(giu.TableFlagsResizable | giu.TableFlagsSizingFixedSame)
 	columns := []*giu.TableColumnWidget{
@@ -115,19 +141,30 @@ func (t *timing) Init(dataSrc f1gopherlib.F1GopherLib, config PanelConfig) {
 		giu.TableColumn("Segment").InnerWidthOrWeight(240),
 		giu.TableColumn("Fastest").InnerWidthOrWeight(timeWidth),
 		giu.TableColumn("Gap").InnerWidthOrWeight(timeWidth),
+	}
+
+	// Brackets beside the gaps for the cars in a battle
+	if t.gapToInfront {
+		columns = append(columns, giu.TableColumn("").InnerWidthOrWeight(15))
+	}
+
+	columns = append(columns, []*giu.TableColumnWidget{
 		giu.TableColumn("S1").InnerWidthOrWeight(timeWidth),
 		giu.TableColumn("S2").InnerWidthOrWeight(timeWidth),
 		giu.TableColumn("S3").InnerWidthOrWeight(timeWidth),
 		giu.TableColumn("Last Lap").InnerWidthOrWeight(timeWidth),
-	}
+	}...)
 
-	if t.eventHasDRS {
-		columns = append(columns, giu.TableColumn("DRS").InnerWidthOrWeight(50))
-	}
+	columns = append(columns, giu.TableColumn(t.overtakeAid.String()).InnerWidthOrWeight(55))
 
 	columns = append(columns, giu.TableColumn("Tire").InnerWidthOrWeight(50))
 	columns = append(columns, giu.TableColumn("Lap").InnerWidthOrWeight(30))
 
+	// Predicted lap the leader will lap the car on
+	if t.gapToInfront {
+		columns = append(columns, giu.TableColumn("Lapped").InnerWidthOrWeight(50))
+	}
+
 	if t.isRaceSession {
 		columns = append(columns, []*giu.TableColumnWidget{
 			giu.TableColumn("Pits").InnerWidthOrWeight(30),
@@ -159,12 +196,16 @@ func (t *timing) ProcessTiming(data Messages.Timing) {
 	t.dataLock.Lock()
 	t.data[data.Number] = data
 	t.dataLock.Unlock()
+
+	t.lapping.ProcessTiming(data)
 }
 
 func (t *timing) ProcessEvent(data Messages.Event) {
 	t.eventLock.Lock()
 	t.event = data
 	t.eventLock.Unlock()
+
+	t.lapping.ProcessEvent(data)
 }
 
 func (t *timing) Draw(width int, height int) []giu.Widget {
@@ -180,20 +221,37 @@ func (t *timing) Draw(width int, height int) []giu.Widget {
 	sector2Segments := t.event.Sector2Segments
 	t.eventLock.Unlock()
 
+	qualifying := regulations.QualifyingRules(t.season, t.qualifyingType, len(drivers))
+	qualifyingSegment := qualifying.Segment(t.event.Type)
+
+	// Laps slower than 107% of the fastest in the first segment may not be allowed to start the race
+	var cutoff107 time.Duration
+	if qualifyingSegment == 1 && len(drivers) > 0 {
+		cutoff107 = qualifying.Cutoff107(drivers[0].FastestLap)
+	}
+
+	brackets := t.battles.driverBrackets()
+	lapping := t.lapping.predictions()
+	trackLimitsSummaries := t.trackLimits.summaries()
+
 	// Driver rows
 	var rows []*giu.TableRowWidget
 	for x := range drivers {
-		// DRS
-		drs := "Closed"
-		if drivers[x].DRSOpen {
-			drs = "Open"
+		// DRS or overtake mode, highlighted if close enough to the car infront to use it (or anywhere for DRS when not
+		// a race session). Only the DRS state is sent so for overtake mode show if the driver can use it.
+		overtake := ""
+		if t.overtakeAid.ReportsState() {
+			overtake = "Closed"
+			if drivers[x].DRSOpen {
+				overtake = "Open"
+			}
 		}
-		// Can use DRS if a race session and close enough to the car infront or if not a race session
-		drsColor := colornames.White
-		if t.event.DRSEnabled != Messages.DRSDisabled &&
-			((t.event.Type != Messages.Race && t.event.Type != Messages.Sprint) ||
-				(drivers[x].TimeDiffToPositionAhead > 0 && drivers[x].TimeDiffToPositionAhead < time.Second)) {
-			drsColor = colornames.Green
+		overtakeColor := colornames.White
+		if t.overtakeAid.Eligible(t.event, drivers[x].TimeDiffToPositionAhead) {
+			overtakeColor = colornames.Green
+			if !t.overtakeAid.ReportsState() {
+				overtake = "Eligible"
+			}
 		}
 
 		// Speed Trap
@@ -234,33 +292,71 @@ func (t *timing) Draw(width int, height int) []giu.Widget {
 			}
 		}
 
+		lapColor := fastestLapColor(drivers[x].OverallFastestLap)
+		if cutoff107 > 0 && drivers[x].FastestLap > cutoff107 {
+			lapColor = colornames.Red
+		}
+
+		// Clicking anywhere on the row focuses the driver, shift click compares against them
+		driverNumber := drivers[x].Number
 		widgets := []giu.Widget{
-			giu.Label(fmt.Sprintf("%d", drivers[x].Position)),
+			giu.Selectable(fmt.Sprintf("%d", drivers[x].Position)).
+				Flags(giu.SelectableFlagsSpanAllColumns).
+				OnClick(func() { t.focus.Select(driverNumber) }),
 			giu.Style().SetColor(giu.StyleColorText, drivers[x].Color).To(
 				giu.Label(drivers[x].ShortName)),
 
 			giu.Style().SetStyleFloat(giu.StyleVarItemSpacing, 0).To(giu.Row(segments...)),
 
-			giu.Style().SetColor(giu.StyleColorText, fastestLapColor(drivers[x].OverallFastestLap)).To(
+			giu.Style().SetColor(giu.StyleColorText, lapColor).To(
 				giu.Label(fmtDuration(drivers[x].FastestLap))),
 			giu.Label(fmtDuration(gap)),
+		}
+
+		if t.gapToInfront {
+			bracket, inBattle := brackets[driverNumber]
+			if inBattle {
+				widgets = append(widgets, giu.Style().SetColor(giu.StyleColorText, bracket.color).To(giu.Label(bracket.bracket)))
+				if bracket.isNew {
+					widgets = append(widgets, giu.Tooltip("New battle"))
+				}
+			} else {
+				widgets = append(widgets, giu.Label(""))
+			}
+		}
+
+		widgets = append(widgets, []giu.Widget{
 			giu.Style().SetColor(giu.StyleColorText, timeColor(drivers[x].Sector1PersonalFastest, drivers[x].Sector1OverallFastest)).To(
 				giu.Label(fmtDuration(drivers[x].Sector1))),
 			giu.Style().SetColor(giu.StyleColorText, timeColor(drivers[x].Sector2PersonalFastest, drivers[x].Sector2OverallFastest)).To(
 				giu.Label(fmtDuration(drivers[x].Sector2))),
 			giu.Style().SetColor(giu.StyleColorText, timeColor(drivers[x].Sector3PersonalFastest, drivers[x].Sector3OverallFastest)).To(
 				giu.Label(fmtDuration(drivers[x].Sector3))),
-			giu.Style().SetColor(giu.StyleColorText, timeColor(drivers[x].LastLapPersonalFastest, drivers[x].LastLapOverallFastest)).To(
-				giu.Label(fmtDuration(drivers[x].LastLap))),
-		}
+		}...)
 
-		if t.eventHasDRS {
-			widgets = append(widgets, giu.Style().SetColor(giu.StyleColorText, drsColor).To(giu.Label(drs)))
+		// Laps deleted by race control don't count
+		if deleted, isDeleted := t.trackLimits.deletedLap(driverNumber, drivers[x].Lap-1, drivers[x].LastLap); isDeleted {
+			tooltip := "Deleted"
+			if deleted.turn > 0 {
+				tooltip = fmt.Sprintf("Deleted - track limits at turn %d", deleted.turn)
+			}
+			widgets = append(widgets,
+				giu.Style().SetColor(giu.StyleColorText, colornames.Red).To(giu.Label(fmtDuration(drivers[x].LastLap))),
+				giu.Tooltip(tooltip))
+		} else {
+			widgets = append(widgets, giu.Style().SetColor(giu.StyleColorText, timeColor(drivers[x].LastLapPersonalFastest, drivers[x].LastLapOverallFastest)).To(
+				giu.Label(fmtDuration(drivers[x].LastLap))))
 		}
 
+		widgets = append(widgets, giu.Style().SetColor(giu.StyleColorText, overtakeColor).To(giu.Label(overtake)))
+
 		widgets = append(widgets, giu.Style().SetColor(giu.StyleColorText, tireColor(drivers[x].Tire)).To(giu.Label(drivers[x].Tire.String())))
 		widgets = append(widgets, giu.Label(fmt.Sprintf("%d", drivers[x].LapsOnTire)))
 
+		if t.gapToInfront {
+			widgets = append(widgets, lappedWidgets(lapping[driverNumber])...)
+		}
+
 		trackLimits := ""
 		timePenalty := ""
 		postPenaltyPos := ""
@@ -268,6 +364,12 @@ func (t *timing) Draw(width int, height int) []giu.Widget {
 		if drivers[x].TrackLimitsWarnings > 0 {
 			trackLimits = fmt.Sprintf("%d", drivers[x].TrackLimitsWarnings)
 		}
+
+		// Highlight drivers whose next track limits offence will be a penalty
+		trackLimitsColor := colornames.White
+		if t.trackLimits.oneFromPenalty(trackLimitsSummaries[driverNumber]) {
+			trackLimitsColor = colornames.Orange
+		}
 		if drivers[x].TimePenaltySeconds > 0 {
 			timePenalty = fmt.Sprintf("%ds", drivers[x].TimePenaltySeconds)
 		}
@@ -334,7 +436,7 @@ func (t *timing) Draw(width int, height int) []giu.Widget {
 				giu.Label(lastPitlaneTime),
 				giu.Style().SetColor(giu.StyleColorText, positionColor).To(
 					giu.Label(potentialPositionChange)),
-				giu.Label(trackLimits),
+				giu.Style().SetColor(giu.StyleColorText, trackLimitsColor).To(giu.Label(trackLimits)),
 				giu.Label(timePenalty),
 				giu.Label(postPenaltyPos),
 			}...)
@@ -342,7 +444,7 @@ func (t *timing) Draw(width int, height int) []giu.Widget {
 			widgets = append(widgets, []giu.Widget{
 				giu.Label(fmt.Sprintf("%d", drivers[x].Pitstops)),
 				giu.Label(lastPitlaneTime),
-				giu.Label(trackLimits),
+				giu.Style().SetColor(giu.StyleColorText, trackLimitsColor).To(giu.Label(trackLimits)),
 				giu.Label(timePenalty),
 				giu.Label(postPenaltyPos),
 			}...)
@@ -357,22 +459,12 @@ func (t *timing) Draw(width int, height int) []giu.Widget {
 
 		// For qualifying show which drivers are out or in the drop zone by changing the background color
 		backgroundColor := defaultBackgroundColor
-		if t.event.Type == Messages.Qualifying1 {
-			if drivers[x].Position > 15 {
-				backgroundColor = dropZoneBackground
-			}
-
-		} else if t.event.Type == Messages.Qualifying2 {
-			if drivers[x].Position > 15 {
+		if qualifyingSegment > 0 {
+			if qualifying.KnockedOut(qualifyingSegment, drivers[x].Position) {
 				backgroundColor = outBackground
-			} else if drivers[x].Position > 10 {
+			} else if qualifying.InDropZone(qualifyingSegment, drivers[x].Position) {
 				backgroundColor = dropZoneBackground
 			}
-
-		} else if t.event.Type == Messages.Qualifying3 {
-			if drivers[x].Position > 10 {
-				backgroundColor = outBackground
-			}
 		} else {
 			// When not a qualifying session alternate the row background color to make things more readable
 			if drivers[x].Position%2 == 0 {
@@ -380,6 +472,10 @@ func (t *timing) Draw(width int, height int) []giu.Widget {
 			}
 		}
 
+		if focusColor, focused := t.focus.highlight(driverNumber); focused {
+			backgroundColor = focusColor
+		}
+
 		rows = append(rows, giu.TableRow(widgets...).BgColor(backgroundColor))
 	}
 
@@ -420,6 +516,13 @@ func (t *timing) Draw(width int, height int) []giu.Widget {
 		giu.Style().SetStyleFloat(giu.StyleVarItemSpacing, 0).To(giu.Row(trackSegments...)),
 		giu.Label(""),
 		giu.Label("Session:"),
+	}
+
+	if t.gapToInfront {
+		rowWidgets = append(rowWidgets, giu.Label(""))
+	}
+
+	rowWidgets = append(rowWidgets, []giu.Widget{
 		giu.Style().SetColor(giu.StyleColorText, purpleColor).To(giu.Label(fmtDuration(t.fastestSector1))),
 		giu.Tooltip(t.fastestSector1Driver),
 		giu.Style().SetColor(giu.StyleColorText, purpleColor).To(giu.Label(fmtDuration(t.fastestSector2))),
@@ -430,13 +533,12 @@ func (t *timing) Draw(width int, height int) []giu.Widget {
 		giu.Label(""),
 		giu.Label(""),
 		giu.Label(""),
-	}
+	}...)
 
 	if t.isRaceSession || t.isSprintRaceSession {
-		if t.eventHasDRS {
-			rowWidgets = append(rowWidgets, giu.Label(""))
-		}
 		rowWidgets = append(rowWidgets, []giu.Widget{
+			giu.Label(""),
+			giu.Label(""),
 			giu.Label(""),
 			giu.Label(""),
 			giu.Style().SetColor(giu.StyleColorText, purpleColor).To(giu.Label(fmt.Sprintf("%d", t.fastestSpeedTrap))),
@@ -526,3 +628,30 @@ func (t *timing) fmtGapDuration(d time.Duration) string {
 
 	return fmt.Sprintf("%02d:%02d.%01d", minutes, seconds, milliseconds)
 }
+
+// lappedWidgets shows the lap the leader is predicted to lap the car on, or that the car is being shown blue flags
+func lappedWidgets(prediction lappingPrediction) []giu.Widget {
+	tooltip := ""
+	if prediction.blueFlagLap > 0 {
+		tooltip = fmt.Sprintf("First blue flag on lap %d", prediction.blueFlagLap)
+		if prediction.predictedLap > 0 {
+			tooltip += fmt.Sprintf(" (predicted lap %d)", prediction.predictedLap)
+		}
+	}
+
+	var widget giu.Widget
+	switch {
+	case prediction.blueFlag:
+		widget = giu.Style().SetColor(giu.StyleColorText, colornames.Dodgerblue).To(giu.Label("Blue"))
+	case prediction.leaderLap > 0:
+		widget = giu.Label(fmt.Sprintf("L%d", prediction.leaderLap))
+		tooltip = fmt.Sprintf("Leader catches in %.1f laps", prediction.lapsToCatch)
+	default:
+		widget = giu.Label("")
+	}
+
+	if tooltip == "" {
+		return []giu.Widget{widget}
+	}
+	return []giu.Widget{widget, giu.Tooltip(tooltip)}
+}
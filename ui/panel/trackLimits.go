@@ -0,0 +1,235 @@
+// F1Gopher - Copyright (C) 2025 f1gopher
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package panel
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AllenDang/giu"
+	"github.com/f1gopher/f1gopherlib"
+	"github.com/f1gopher/f1gopherlib/Messages"
+	"golang.org/x/image/colornames"
+)
+
+type trackLimits struct {
+	tracker *trackLimitsTracker
+	focus   *DriverFocus
+
+	drivers     map[int]overtakesDriver
+	driversLock sync.Mutex
+	timezone    *time.Location
+}
+
+func CreateTrackLimits(tracker *trackLimitsTracker, focus *DriverFocus) Panel {
+	return &trackLimits{
+		tracker: tracker,
+		focus:   focus,
+		drivers: map[int]overtakesDriver{},
+	}
+}
+
+func (t *trackLimits) ProcessEventTime(data Messages.EventTime)                    {}
+func (t *trackLimits) ProcessTiming(data Messages.Timing)                          {}
+func (t *trackLimits) ProcessEvent(data Messages.Event)                            {}
+func (t *trackLimits) ProcessWeather(data Messages.Weather)                        {}
+func (t *trackLimits) ProcessRadio(data Messages.Radio)                            {}
+func (t *trackLimits) ProcessLocation(data Messages.Location)                      {}
+func (t *trackLimits) ProcessTelemetry(data Messages.Telemetry)                    {}
+func (t *trackLimits) ProcessRaceControlMessages(data Messages.RaceControlMessage) {}
+func (t *trackLimits) Close()                                                      {}
+
+func (t *trackLimits) Type() Type { return TrackLimits }
+
+func (t *trackLimits) Init(dataSrc f1gopherlib.F1GopherLib, config PanelConfig) {
+	// The tracker is fed and reset by the timing table
+	t.timezone = dataSrc.CircuitTimezone()
+
+	t.driversLock.Lock()
+	t.drivers = map[int]overtakesDriver{}
+	t.driversLock.Unlock()
+}
+
+func (t *trackLimits) ProcessDrivers(data Messages.Drivers) {
+	t.driversLock.Lock()
+	defer t.driversLock.Unlock()
+
+	for _, driver := range data.Drivers {
+		t.drivers[driver.Number] = overtakesDriver{name: driver.ShortName, color: driver.Color}
+	}
+}
+
+func (t *trackLimits) driver(driverNumber int) overtakesDriver {
+	t.driversLock.Lock()
+	defer t.driversLock.Unlock()
+
+	driver, exists := t.drivers[driverNumber]
+	if !exists {
+		return overtakesDriver{name: fmt.Sprintf("%d", driverNumber), color: colornames.White}
+	}
+	return driver
+}
+
+func (t *trackLimits) Draw(width int, height int) []giu.Widget {
+	records := t.tracker.allRecords()
+	summaries := t.tracker.summaries()
+
+	// Newest first
+	log := make([]*giu.TableRowWidget, 0, len(records))
+	for x := len(records) - 1; x >= 0; x-- {
+		record := &records[x]
+		driver := t.driver(record.driverNumber)
+
+		lap := ""
+		if record.lap > 0 {
+			lap = fmt.Sprintf("%d", record.lap)
+		}
+		turn := ""
+		if record.turn > 0 {
+			turn = fmt.Sprintf("%d", record.turn)
+		}
+
+		eventColor := colornames.White
+		switch {
+		case record.event == LapDeleted && record.reinstated:
+			eventColor = colornames.Gray
+		case record.event == LapDeleted:
+			eventColor = colornames.Red
+		case record.event == LapReinstated:
+			eventColor = colornames.Green
+		case record.event == BlackAndWhiteFlag:
+			eventColor = colornames.Orange
+		case record.event == TrackLimitsPenalty:
+			eventColor = colornames.Red
+		}
+
+		event := record.event.String()
+		if record.event == LapDeleted && !record.trackLimits {
+			event += " (Other)"
+		}
+
+		log = append(log, giu.TableRow(
+			giu.Label(record.timestamp.In(t.timezone).Format("15:04:05")),
+			giu.Style().SetColor(giu.StyleColorText, driver.color).To(giu.Label(driver.name)),
+			giu.Label(lap),
+			giu.Label(turn),
+			giu.Label(fmtDuration(record.lapTime)),
+			giu.Style().SetColor(giu.StyleColorText, eventColor).To(giu.Label(event)),
+		))
+	}
+
+	driverNumbers := make([]int, 0, len(summaries))
+	for driverNumber := range summaries {
+		driverNumbers = append(driverNumbers, driverNumber)
+	}
+	sort.Slice(driverNumbers, func(i, j int) bool {
+		a, b := summaries[driverNumbers[i]], summaries[driverNumbers[j]]
+		if a.deleted != b.deleted {
+			return a.deleted > b.deleted
+		}
+		return driverNumbers[i] < driverNumbers[j]
+	})
+
+	totals := make([]*giu.TableRowWidget, 0, len(driverNumbers))
+	for _, driverNumber := range driverNumbers {
+		summary := summaries[driverNumber]
+		driver := t.driver(driverNumber)
+
+		status := ""
+		statusColor := colornames.White
+		if t.tracker.oneFromPenalty(summary) {
+			status = "Next is a penalty"
+			statusColor = colornames.Orange
+		}
+		if summary.blackAndWhite {
+			status = strings.TrimSpace("B&W " + status)
+		}
+
+		row := giu.TableRow(
+			giu.Selectable(driver.name).
+				Flags(giu.SelectableFlagsSpanAllColumns).
+				OnClick(func() { t.focus.Select(driverNumber) }),
+			giu.Label(fmt.Sprintf("%d", summary.deleted)),
+			giu.Label(fmt.Sprintf("%d", summary.reinstated)),
+			giu.Label(fmt.Sprintf("%d", summary.penalties)),
+			giu.Label(trackLimitsTurns(summary.turns)),
+			giu.Style().SetColor(giu.StyleColorText, statusColor).To(giu.Label(status)),
+		)
+		if focusColor, focused := t.focus.highlight(driverNumber); focused {
+			row = row.BgColor(focusColor)
+		}
+		totals = append(totals, row)
+	}
+
+	tableHeight := float32(height - 16)
+	totalsWidth := float32(width-24) * 0.5
+
+	return []giu.Widget{
+		giu.Row(
+			giu.Child().Size(totalsWidth, tableHeight).Layout(
+				giu.Table().FastMode(true).Flags(giu.TableFlagsResizable|giu.TableFlagsSizingFixedSame|giu.TableFlagsScrollY).
+					Columns(
+						giu.TableColumn("Drv").InnerWidthOrWeight(35),
+						giu.TableColumn("Deleted").InnerWidthOrWeight(50),
+						giu.TableColumn("Reinstated").InnerWidthOrWeight(65),
+						giu.TableColumn("Penalties").InnerWidthOrWeight(60),
+						giu.TableColumn("Turns").InnerWidthOrWeight(100),
+						giu.TableColumn("Status").InnerWidthOrWeight(130),
+					).
+					Rows(totals...),
+			),
+			giu.Child().Size(float32(width-24)-totalsWidth, tableHeight).Layout(
+				giu.Table().FastMode(true).Flags(giu.TableFlagsResizable|giu.TableFlagsSizingFixedSame|giu.TableFlagsScrollY).
+					Columns(
+						giu.TableColumn("Time").InnerWidthOrWeight(60),
+						giu.TableColumn("Drv").InnerWidthOrWeight(35),
+						giu.TableColumn("Lap").InnerWidthOrWeight(30),
+						giu.TableColumn("Turn").InnerWidthOrWeight(35),
+						giu.TableColumn("Lap Time").InnerWidthOrWeight(timeWidth),
+						giu.TableColumn("Event").InnerWidthOrWeight(100),
+					).
+					Rows(log...),
+			),
+		),
+	}
+}
+
+// trackLimitsTurns lists the turns with the most deleted laps first, like "T4 x2, T10"
+func trackLimitsTurns(turns map[int]int) string {
+	sorted := make([]int, 0, len(turns))
+	for turn := range turns {
+		sorted = append(sorted, turn)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if turns[sorted[i]] != turns[sorted[j]] {
+			return turns[sorted[i]] > turns[sorted[j]]
+		}
+		return sorted[i] < sorted[j]
+	})
+
+	result := make([]string, 0, len(sorted))
+	for _, turn := range sorted {
+		if turns[turn] > 1 {
+			result = append(result, fmt.Sprintf("T%d x%d", turn, turns[turn]))
+		} else {
+			result = append(result, fmt.Sprintf("T%d", turn))
+		}
+	}
+	return strings.Join(result, ", ")
+}
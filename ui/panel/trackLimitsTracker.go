@@ -0,0 +1,255 @@
+// F1Gopher - Copyright (C) 2025 f1gopher
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package panel
+
+import (
+	"f1gopher/raceControl"
+	"f1gopher/regulations"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/f1gopher/f1gopherlib/Messages"
+)
+
+// Matches "CAR 4 (NOR) TIME 1:27.529 DELETED - TRACK LIMITS AT TURN 1 LAP 2 15:11:38" and "CAR 16 (LEC) LAP DELETED..."
+var rcmDeletedLapRegex = regexp.MustCompile(`^CAR (\d+) \([A-Z]{3}\) (?:TIME (\d+:\d+\.\d+) )?(?:LAP )?(DELETED|REINSTATED)`)
+
+type trackLimitsEvent int
+
+const (
+	LapDeleted trackLimitsEvent = iota
+	LapReinstated
+	BlackAndWhiteFlag
+	TrackLimitsPenalty
+)
+
+func (t trackLimitsEvent) String() string {
+	return [...]string{"Deleted", "Reinstated", "Black & White", "Penalty"}[t]
+}
+
+// trackLimitsRecord is a race control decision about a drivers lap or track limits. The lap, turn and lap time are
+// zero if the message didn't include them.
+type trackLimitsRecord struct {
+	timestamp    time.Time
+	event        trackLimitsEvent
+	driverNumber int
+	lap          int
+	turn         int
+	lapTime      time.Duration
+	// Laps can be deleted for other reasons, like not slowing for yellow flags
+	trackLimits bool
+	reinstated  bool
+}
+
+// matches returns true if the record is for the drivers lap, by lap time when the message had one
+func (t *trackLimitsRecord) matches(driverNumber int, lap int, lapTime time.Duration) bool {
+	if t.driverNumber != driverNumber {
+		return false
+	}
+	if t.lapTime != 0 && lapTime != 0 {
+		return (t.lapTime - lapTime).Abs() < time.Millisecond
+	}
+	return t.lap != 0 && t.lap == lap
+}
+
+type trackLimitsSummary struct {
+	deleted       int
+	reinstated    int
+	blackAndWhite bool
+	penalties     int
+	// Number of deleted laps at each turn
+	turns map[int]int
+}
+
+// trackLimitsTracker turns the race control messages about deleted laps and track limits into records. It is fed by
+// the timing table and shared with the track limits panel and the panels that mark deleted laps.
+type trackLimitsTracker struct {
+	penaltyAfter int
+	records      []*trackLimitsRecord
+	// Incremented whenever the records change
+	updates int
+	lock    sync.Mutex
+}
+
+func CreateTrackLimitsTracker() *trackLimitsTracker {
+	return &trackLimitsTracker{}
+}
+
+func (t *trackLimitsTracker) Init(season int, session Messages.SessionType) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.penaltyAfter = regulations.TrackLimitsPenaltyAfter(season, session)
+	t.records = nil
+	t.updates = 0
+}
+
+func (t *trackLimitsTracker) ProcessRaceControlMessages(data Messages.RaceControlMessage) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	parsed := raceControl.Parse(data)
+	turn := 0
+	if len(parsed.Turns) > 0 {
+		turn = parsed.Turns[0]
+	}
+
+	if matches := rcmDeletedLapRegex.FindStringSubmatch(data.Msg); matches != nil {
+		record := &trackLimitsRecord{
+			timestamp:   data.Timestamp,
+			event:       LapDeleted,
+			lap:         parsed.Lap,
+			turn:        turn,
+			trackLimits: parsed.ForTrackLimits(),
+		}
+		record.driverNumber, _ = strconv.Atoi(matches[1])
+		if matches[2] != "" {
+			record.lapTime = parseLapTime(matches[2])
+		}
+
+		if matches[3] == "REINSTATED" {
+			record.event = LapReinstated
+			for _, existing := range t.records {
+				if existing.event == LapDeleted && existing.matches(record.driverNumber, record.lap, record.lapTime) {
+					existing.reinstated = true
+					record.trackLimits = existing.trackLimits
+					record.turn = existing.turn
+				}
+			}
+		}
+
+		t.records = append(t.records, record)
+		t.updates++
+		return
+	}
+
+	if !parsed.ForTrackLimits() {
+		return
+	}
+
+	var event trackLimitsEvent
+	switch {
+	case parsed.Category == raceControl.Penalties && !parsed.PenaltyServed():
+		event = TrackLimitsPenalty
+	case parsed.Category == raceControl.TrackLimits &&
+		(data.Flag == Messages.BlackAndWhite || strings.Contains(data.Msg, "BLACK AND WHITE")):
+		event = BlackAndWhiteFlag
+	default:
+		return
+	}
+
+	for _, driverNumber := range parsed.Cars {
+		t.records = append(t.records, &trackLimitsRecord{
+			timestamp:    data.Timestamp,
+			event:        event,
+			driverNumber: driverNumber,
+			turn:         turn,
+			trackLimits:  true,
+		})
+		t.updates++
+	}
+}
+
+// updateCount returns a number that changes whenever the records change
+func (t *trackLimitsTracker) updateCount() int {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return t.updates
+}
+
+// parseLapTime converts a lap time in the form 1:27.529 to a duration
+func parseLapTime(value string) time.Duration {
+	minutes, seconds, found := strings.Cut(value, ":")
+	if !found {
+		return 0
+	}
+
+	duration, err := time.ParseDuration(fmt.Sprintf("%sm%ss", minutes, seconds))
+	if err != nil {
+		return 0
+	}
+	return duration
+}
+
+// allRecords returns a copy of the records in the order they happened
+func (t *trackLimitsTracker) allRecords() []trackLimitsRecord {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	result := make([]trackLimitsRecord, 0, len(t.records))
+	for _, record := range t.records {
+		result = append(result, *record)
+	}
+	return result
+}
+
+// deletedLap returns the record if the drivers lap has been deleted and not reinstated
+func (t *trackLimitsTracker) deletedLap(driverNumber int, lap int, lapTime time.Duration) (trackLimitsRecord, bool) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	for _, record := range t.records {
+		if record.event == LapDeleted && !record.reinstated && record.matches(driverNumber, lap, lapTime) {
+			return *record, true
+		}
+	}
+	return trackLimitsRecord{}, false
+}
+
+// summaries returns the totals for each driver who has any records
+func (t *trackLimitsTracker) summaries() map[int]*trackLimitsSummary {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	result := map[int]*trackLimitsSummary{}
+	for _, record := range t.records {
+		summary, exists := result[record.driverNumber]
+		if !exists {
+			summary = &trackLimitsSummary{turns: map[int]int{}}
+			result[record.driverNumber] = summary
+		}
+
+		switch record.event {
+		case LapDeleted:
+			if record.reinstated {
+				summary.reinstated++
+			} else if record.trackLimits {
+				summary.deleted++
+				if record.turn > 0 {
+					summary.turns[record.turn]++
+				}
+			}
+		case BlackAndWhiteFlag:
+			summary.blackAndWhite = true
+		case TrackLimitsPenalty:
+			summary.penalties++
+		}
+	}
+	return result
+}
+
+// oneFromPenalty returns true if the next track limits offence for the driver will be a penalty
+func (t *trackLimitsTracker) oneFromPenalty(summary *trackLimitsSummary) bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return t.penaltyAfter > 0 && summary != nil && summary.deleted >= t.penaltyAfter
+}
@@ -0,0 +1,172 @@
+package panel
+
+import (
+	"maps"
+	"testing"
+	"time"
+
+	"github.com/f1gopher/f1gopherlib/Messages"
+)
+
+func TestTrackLimitsTracker(t *testing.T) {
+	const deletedNOR = "CAR 4 (NOR) TIME 1:27.529 DELETED - TRACK LIMITS AT TURN 1 LAP 2 15:11:38"
+	norLapTime := 87529 * time.Millisecond
+
+	tests := []struct {
+		name     string
+		session  Messages.SessionType
+		messages []string
+		flag     Messages.FlagState
+		driver   int
+		expected trackLimitsSummary
+		// The lap checked for being deleted
+		lap            int
+		lapTime        time.Duration
+		lapDeleted     bool
+		oneFromPenalty bool
+	}{
+		{
+			name:       "deleted for track limits",
+			session:    Messages.RaceSession,
+			messages:   []string{deletedNOR},
+			driver:     4,
+			expected:   trackLimitsSummary{deleted: 1, turns: map[int]int{1: 1}},
+			lap:        2,
+			lapTime:    norLapTime,
+			lapDeleted: true,
+		},
+		{
+			name:     "reinstated by lap time",
+			session:  Messages.QualifyingSession,
+			messages: []string{deletedNOR, "CAR 4 (NOR) TIME 1:27.529 REINSTATED 15:16:02"},
+			driver:   4,
+			expected: trackLimitsSummary{reinstated: 1, turns: map[int]int{}},
+			lap:      2,
+			lapTime:  norLapTime,
+		},
+		{
+			name:       "a different lap reinstated",
+			session:    Messages.QualifyingSession,
+			messages:   []string{deletedNOR, "CAR 4 (NOR) TIME 1:26.911 REINSTATED 15:16:02"},
+			driver:     4,
+			expected:   trackLimitsSummary{deleted: 1, turns: map[int]int{1: 1}},
+			lap:        2,
+			lapTime:    norLapTime,
+			lapDeleted: true,
+		},
+		{
+			name:    "reinstated by lap number",
+			session: Messages.RaceSession,
+			messages: []string{
+				"CAR 16 (LEC) LAP DELETED - TRACK LIMITS AT TURN 10 LAP 5 14:22:41",
+				"CAR 16 (LEC) LAP REINSTATED - LAP 5 14:31:09",
+			},
+			driver:   16,
+			expected: trackLimitsSummary{reinstated: 1, turns: map[int]int{}},
+			lap:      5,
+			lapTime:  91 * time.Second,
+		},
+		{
+			name:       "deleted for another reason",
+			session:    Messages.QualifyingSession,
+			messages:   []string{"CAR 16 (LEC) TIME 1:31.002 DELETED - FAILING TO SLOW UNDER DOUBLE YELLOW FLAGS"},
+			driver:     16,
+			expected:   trackLimitsSummary{turns: map[int]int{}},
+			lapTime:    91002 * time.Millisecond,
+			lapDeleted: true,
+		},
+		{
+			name:     "black and white flag",
+			session:  Messages.RaceSession,
+			messages: []string{"BLACK AND WHITE FLAG FOR CAR 11 (PER) - TRACK LIMITS"},
+			flag:     Messages.BlackAndWhite,
+			driver:   11,
+			expected: trackLimitsSummary{blackAndWhite: true, turns: map[int]int{}},
+		},
+		{
+			name:     "penalty",
+			session:  Messages.RaceSession,
+			messages: []string{"FIA STEWARDS: 5 SECOND TIME PENALTY FOR CAR 11 (PER) - TRACK LIMITS"},
+			driver:   11,
+			expected: trackLimitsSummary{penalties: 1, turns: map[int]int{}},
+		},
+		{
+			name:     "served penalty",
+			session:  Messages.RaceSession,
+			messages: []string{"FIA STEWARDS: 5 SECOND TIME PENALTY SERVED BY CAR 11 (PER) - TRACK LIMITS"},
+			driver:   11,
+		},
+		{
+			name:     "investigation",
+			session:  Messages.RaceSession,
+			messages: []string{"FIA STEWARDS: TRACK LIMITS AT TURN 4 BY CAR 11 (PER) UNDER INVESTIGATION"},
+			driver:   11,
+		},
+		{
+			name:    "one from a penalty",
+			session: Messages.RaceSession,
+			messages: []string{
+				deletedNOR,
+				"CAR 4 (NOR) TIME 1:27.802 DELETED - TRACK LIMITS AT TURN 1 LAP 9 15:23:10",
+				"CAR 4 (NOR) TIME 1:28.114 DELETED - TRACK LIMITS AT TURN 4 LAP 14 15:30:52",
+			},
+			driver:         4,
+			expected:       trackLimitsSummary{deleted: 3, turns: map[int]int{1: 2, 4: 1}},
+			oneFromPenalty: true,
+		},
+		{
+			name:    "reinstated lap doesn't count towards a penalty",
+			session: Messages.RaceSession,
+			messages: []string{
+				deletedNOR,
+				"CAR 4 (NOR) TIME 1:27.802 DELETED - TRACK LIMITS AT TURN 1 LAP 9 15:23:10",
+				"CAR 4 (NOR) TIME 1:28.114 DELETED - TRACK LIMITS AT TURN 4 LAP 14 15:30:52",
+				"CAR 4 (NOR) TIME 1:28.114 REINSTATED 15:34:20",
+			},
+			driver:   4,
+			expected: trackLimitsSummary{deleted: 2, reinstated: 1, turns: map[int]int{1: 2}},
+		},
+		{
+			name:    "no penalty in qualifying",
+			session: Messages.QualifyingSession,
+			messages: []string{
+				deletedNOR,
+				"CAR 4 (NOR) TIME 1:27.802 DELETED - TRACK LIMITS AT TURN 1 LAP 9 15:23:10",
+				"CAR 4 (NOR) TIME 1:28.114 DELETED - TRACK LIMITS AT TURN 4 LAP 14 15:30:52",
+			},
+			driver:   4,
+			expected: trackLimitsSummary{deleted: 3, turns: map[int]int{1: 2, 4: 1}},
+		},
+	}
+
+	start := time.Date(2024, 6, 30, 15, 0, 0, 0, time.UTC)
+	for _, test := range tests {
+		tracker := CreateTrackLimitsTracker()
+		tracker.Init(2024, test.session)
+		for x, msg := range test.messages {
+			tracker.ProcessRaceControlMessages(Messages.RaceControlMessage{
+				Timestamp: start.Add(time.Duration(x) * time.Minute),
+				Msg:       msg,
+				Flag:      test.flag,
+			})
+		}
+
+		summary := tracker.summaries()[test.driver]
+		actual := trackLimitsSummary{}
+		if summary != nil {
+			actual = *summary
+		}
+		if actual.deleted != test.expected.deleted || actual.reinstated != test.expected.reinstated ||
+			actual.blackAndWhite != test.expected.blackAndWhite || actual.penalties != test.expected.penalties ||
+			!maps.Equal(actual.turns, test.expected.turns) {
+			t.Errorf("%s: expected %+v, got %+v", test.name, test.expected, actual)
+		}
+
+		if _, deleted := tracker.deletedLap(test.driver, test.lap, test.lapTime); deleted != test.lapDeleted {
+			t.Errorf("%s: expected the lap deleted to be %t", test.name, test.lapDeleted)
+		}
+		if tracker.oneFromPenalty(summary) != test.oneFromPenalty {
+			t.Errorf("%s: expected one from a penalty to be %t", test.name, test.oneFromPenalty)
+		}
+	}
+}
@@ -16,6 +16,7 @@
 package panel
 
 import (
+	"fmt"
 	"image"
 	"image/color"
 	"image/draw"
@@ -23,6 +24,7 @@ import (
 	"sort"
 	"sync"
 
+	"github.com/AllenDang/cimgui-go/imgui"
 	"github.com/AllenDang/giu"
 	"github.com/f1gopher/f1gopherlib"
 	"github.com/f1gopher/f1gopherlib/Messages"
@@ -37,7 +39,18 @@ type trackMapInfo struct {
 }
 
 type trackMap struct {
-	mapStore *trackMapStore
+	mapStore  *trackMapStore
+	focus     *DriverFocus
+	overtakes *overtakeDetector
+	lapping   *lappingPredictor
+
+	// Where each car was last drawn on the map so they can be clicked on
+	carScreenPositions map[int]image.Point
+
+	// Maps embedded in other panels share the store but don't feed it data
+	updatesStore bool
+	// When set only this driver is drawn
+	onlyDriver func() int
 
 	driverData          map[int]trackMapInfo
 	driverPositions     map[int]Messages.Location
@@ -45,6 +58,8 @@ type trackMap struct {
 	event               Messages.Event
 	eventLock           sync.Mutex
 	showStoppedCars     bool
+	showOvertakes       bool
+	showLapping         bool
 
 	trackTexture       *giu.Texture
 	trackTextureWidth  float32
@@ -56,12 +71,31 @@ type trackMap struct {
 
 const safetyCarDriverNum = 127
 
-func CreateTrackMap(trackMaps *trackMapStore) Panel {
+func CreateTrackMap(trackMaps *trackMapStore, focus *DriverFocus, overtakes *overtakeDetector, lapping *lappingPredictor) Panel {
+	return &trackMap{
+		mapStore:           trackMaps,
+		focus:              focus,
+		overtakes:          overtakes,
+		lapping:            lapping,
+		showLapping:        true,
+		carScreenPositions: map[int]image.Point{},
+		driverPositions:    map[int]Messages.Location{},
+		driverData:         map[int]trackMapInfo{},
+		showStoppedCars:    true,
+		updatesStore:       true,
+	}
+}
+
+// createDriverTrackMap creates a map that only shows a single driver for embedding in other panels
+func createDriverTrackMap(trackMaps *trackMapStore, focus *DriverFocus, driver func() int) *trackMap {
 	return &trackMap{
-		mapStore:        trackMaps,
-		driverPositions: map[int]Messages.Location{},
-		driverData:      map[int]trackMapInfo{},
-		showStoppedCars: true,
+		mapStore:           trackMaps,
+		focus:              focus,
+		carScreenPositions: map[int]image.Point{},
+		driverPositions:    map[int]Messages.Location{},
+		driverData:         map[int]trackMapInfo{},
+		showStoppedCars:    true,
+		onlyDriver:         driver,
 	}
 }
 
@@ -78,6 +112,7 @@ func (t *trackMap) Init(dataSrc f1gopherlib.F1GopherLib, config PanelConfig) {
 	// Clear previous session data
 	t.driverPositions = map[int]Messages.Location{}
 	t.driverData = map[int]trackMapInfo{}
+	t.carScreenPositions = map[int]image.Point{}
 	if t.mapGc != nil {
 		t.mapGc.Destroy()
 		t.mapGc = nil
@@ -85,7 +120,9 @@ func (t *trackMap) Init(dataSrc f1gopherlib.F1GopherLib, config PanelConfig) {
 	t.currentWidth = 0
 	t.currentHeight = 0
 
-	t.mapStore.SelectTrack(dataSrc.Track(), dataSrc.TrackYear())
+	if t.updatesStore {
+		t.mapStore.SelectTrack(dataSrc.Track(), dataSrc.TrackYear())
+	}
 }
 
 func (t *trackMap) ProcessDrivers(data Messages.Drivers) {
@@ -102,11 +139,15 @@ func (t *trackMap) ProcessLocation(data Messages.Location) {
 	t.driverPositions[data.DriverNumber] = data
 	t.driverPositionsLock.Unlock()
 
-	t.mapStore.ProcessLocation(data)
+	if t.updatesStore {
+		t.mapStore.ProcessLocation(data)
+	}
 }
 
 func (t *trackMap) ProcessTiming(data Messages.Timing) {
-	t.mapStore.ProcessTiming(data)
+	if t.updatesStore {
+		t.mapStore.ProcessTiming(data)
+	}
 
 	// Update the driver stopped state if it has changce
 	driverData := t.driverData[data.Number]
@@ -117,7 +158,9 @@ func (t *trackMap) ProcessTiming(data Messages.Timing) {
 }
 
 func (t *trackMap) ProcessEvent(data Messages.Event) {
-	t.mapStore.ProcessEvent(data)
+	if t.updatesStore {
+		t.mapStore.ProcessEvent(data)
+	}
 
 	t.eventLock.Lock()
 	t.event = data
@@ -137,12 +180,19 @@ func (t *trackMap) Draw(width int, height int) []giu.Widget {
 
 	t.redraw(width, height, cars)
 
+	options := []giu.Widget{giu.Checkbox("Show Stopped Cars", &t.showStoppedCars)}
+	if t.overtakes != nil {
+		options = append(options, giu.Checkbox("Show Overtakes", &t.showOvertakes))
+	}
+	if t.lapping != nil {
+		options = append(options, giu.Checkbox("Show Lapping", &t.showLapping))
+	}
+
 	if t.trackTexture != nil {
 		return []giu.Widget{
 			giu.Image(t.trackTexture).Size(t.trackTextureWidth, t.trackTextureHeight),
-			giu.Row(
-				giu.Checkbox("Show Stopped Cars", &t.showStoppedCars),
-			),
+			giu.Custom(t.handleClick),
+			giu.Row(options...),
 		}
 	}
 
@@ -155,9 +205,7 @@ func (t *trackMap) Draw(width int, height int) []giu.Widget {
 			offset := int(textWidth / 2)
 			canvas.AddText(pos.Add(image.Pt((width/2)-offset, height/2)), colornames.Yellow, "Building Map...")
 		}),
-		giu.Row(
-			giu.Checkbox("Show Stopped Cars", &t.showStoppedCars),
-		),
+		giu.Row(options...),
 	}
 }
 
@@ -203,19 +251,57 @@ func (t *trackMap) redraw(width int, height int, cars []Messages.Location) {
 
 		s := math.Sin(rotation)
 		c := math.Cos(rotation)
-
-		for _, car := range cars {
-			x := car.X
-			y := car.Y
-
+		toMap := func(x float64, y float64) (float64, float64) {
 			xVal := -(x - float64(xOffset))
 			yVal := y - float64(yOffset)
 
-			x = xVal*c - yVal*s
-			y = xVal*s + yVal*c
+			return (xVal*c - yVal*s) / scaling, (xVal*s + yVal*c) / scaling
+		}
+
+		// Heatmap of where the overtakes happened, overlapping circles get brighter
+		if t.showOvertakes && t.overtakes != nil {
+			t.mapGc.SetSourceRGBA(1.0, 0.4, 0.0, 0.3)
+			for _, overtake := range t.overtakes.overtakeLocations() {
+				x, y := toMap(overtake.x, overtake.y)
+				t.mapGc.NewPath()
+				t.mapGc.Arc(x, y, 8, 0, 2*math.Pi)
+				t.mapGc.Fill()
+			}
+		}
+
+		// Where the leader is predicted to lap the cars behind
+		if t.showLapping && t.lapping != nil {
+			for driverNumber, prediction := range t.lapping.predictions() {
+				if !prediction.hasLocation {
+					continue
+				}
+
+				x, y := toMap(prediction.location.x, prediction.location.y)
+				t.mapGc.SetSourceRGBA(0.12, 0.56, 1.0, 1.0)
+				t.mapGc.NewPath()
+				t.mapGc.Arc(x, y, 6, 0, 2*math.Pi)
+				t.mapGc.Stroke()
 
-			x = x / scaling
-			y = y / scaling
+				t.mapGc.MoveTo(x+float64(8), y+10)
+				t.mapGc.ShowText(fmt.Sprintf("%s L%d", t.driverData[driverNumber].name, prediction.leaderLap))
+				t.mapGc.Stroke()
+			}
+		}
+
+		t.carScreenPositions = map[int]image.Point{}
+		focused := t.focus.Focused()
+		comparison := t.focus.Comparison()
+		onlyDriver := NoDriver
+		if t.onlyDriver != nil {
+			onlyDriver = t.onlyDriver()
+		}
+
+		for _, car := range cars {
+			if onlyDriver != NoDriver && car.DriverNumber != onlyDriver {
+				continue
+			}
+
+			x, y := toMap(car.X, car.Y)
 
 			driverInfo, exists := t.driverData[car.DriverNumber]
 
@@ -242,6 +328,22 @@ func (t *trackMap) redraw(width int, height int, cars []Messages.Location) {
 				driverName = "SC"
 			}
 
+			t.carScreenPositions[car.DriverNumber] = image.Pt(int(x)+displayWidth/2, int(y)+displayHeight/2)
+
+			// Ring around the focused and comparison drivers
+			if car.DriverNumber == focused || car.DriverNumber == comparison {
+				ringColor := focusedBackground
+				if car.DriverNumber == comparison {
+					ringColor = comparisonBackground
+				}
+				t.mapGc.SetSourceRGBA(floatColor(ringColor))
+				t.mapGc.SetLineWidth(3)
+				t.mapGc.NewPath()
+				t.mapGc.Arc(x, y, 11, 0, 2*math.Pi)
+				t.mapGc.Stroke()
+				t.mapGc.SetLineWidth(2)
+			}
+
 			// Draw marker
 			t.mapGc.SetSourceRGBA(float64(driverColor.R)/255.0, float64(driverColor.G)/255.0, float64(driverColor.B)/255.0, 1.0)
 			t.mapGc.Rectangle(x-5, y-5, 10, 10)
@@ -280,3 +382,33 @@ func (t *trackMap) redraw(width int, height int, cars []Messages.Location) {
 		})
 	}
 }
+
+// handleClick focuses the car closest to where the map was clicked
+func (t *trackMap) handleClick() {
+	if !imgui.IsItemClicked() {
+		return
+	}
+
+	mouse := imgui.MousePos()
+	origin := imgui.ItemRectMin()
+	click := image.Pt(int(mouse.X-origin.X), int(mouse.Y-origin.Y))
+
+	// Only select cars close to the click
+	closest := 15.0
+	selected := NoDriver
+	for driverNumber, pos := range t.carScreenPositions {
+		if _, isDriver := t.driverData[driverNumber]; !isDriver {
+			continue
+		}
+
+		distance := math.Hypot(float64(pos.X-click.X), float64(pos.Y-click.Y))
+		if distance < closest {
+			closest = distance
+			selected = driverNumber
+		}
+	}
+
+	if selected != NoDriver {
+		t.focus.Select(selected)
+	}
+}
@@ -0,0 +1,279 @@
+// F1Gopher - Copyright (C) 2025 f1gopher
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package panel
+
+import (
+	"f1gopher/webhooks"
+	"fmt"
+	"sync"
+
+	"github.com/AllenDang/cimgui-go/imgui"
+	"github.com/AllenDang/giu"
+	"github.com/f1gopher/f1gopherlib"
+	"github.com/f1gopher/f1gopherlib/Messages"
+	"golang.org/x/image/colornames"
+)
+
+type webhookSettings struct {
+	config PanelConfig
+
+	// Replaced for each session so used by the data and UI threads
+	sink     *webhooks.Sink
+	sinkLock sync.Mutex
+
+	// Only used by the UI thread
+	hooks       []webhooks.Hook
+	deleteIndex int
+	status      string
+}
+
+func CreateWebhooks() Panel {
+	return &webhookSettings{
+		sink:        webhooks.NewSink(nil),
+		deleteIndex: -1,
+	}
+}
+
+func (w *webhookSettings) ProcessWeather(data Messages.Weather)     {}
+func (w *webhookSettings) ProcessRadio(data Messages.Radio)         {}
+func (w *webhookSettings) ProcessLocation(data Messages.Location)   {}
+func (w *webhookSettings) ProcessTelemetry(data Messages.Telemetry) {}
+func (w *webhookSettings) ProcessEventTime(data Messages.EventTime) {}
+
+func (w *webhookSettings) Type() Type { return Webhooks }
+
+func (w *webhookSettings) Init(dataSrc f1gopherlib.F1GopherLib, config PanelConfig) {
+	w.config = config
+	w.hooks = config.Webhooks()
+	w.deleteIndex = -1
+	w.status = ""
+
+	sink := webhooks.NewSink(w.hooks)
+	sink.Reset(dataSrc.Name(), dataSrc.Session())
+
+	w.sinkLock.Lock()
+	previous := w.sink
+	w.sink = sink
+	w.sinkLock.Unlock()
+
+	previous.Close()
+}
+
+func (w *webhookSettings) Close() {
+	// Drops anything still waiting to be sent
+	w.currentSink().Close()
+}
+
+func (w *webhookSettings) currentSink() *webhooks.Sink {
+	w.sinkLock.Lock()
+	defer w.sinkLock.Unlock()
+
+	return w.sink
+}
+
+func (w *webhookSettings) ProcessDrivers(data Messages.Drivers) {
+	w.currentSink().ProcessDrivers(data)
+}
+
+func (w *webhookSettings) ProcessTiming(data Messages.Timing) {
+	w.currentSink().ProcessTiming(data)
+}
+
+func (w *webhookSettings) ProcessEvent(data Messages.Event) {
+	w.currentSink().ProcessEvent(data)
+}
+
+func (w *webhookSettings) ProcessRaceControlMessages(data Messages.RaceControlMessage) {
+	w.currentSink().ProcessRaceControlMessage(data)
+}
+
+// applyHooks sends to the edited hooks from now on and saves them with the config
+func (w *webhookSettings) applyHooks() {
+	w.currentSink().SetHooks(w.hooks)
+
+	w.status = ""
+	if err := w.config.SetWebhooks(w.hooks); err != nil {
+		w.status = fmt.Sprintf("Failed to save the webhooks: %s", err)
+	}
+}
+
+// applyAfterEdit applies the hooks once the previous widget has finished being edited, so the text boxes don't
+// change the hooks and save the config for every key press
+func (w *webhookSettings) applyAfterEdit() giu.Widget {
+	return giu.Custom(func() {
+		if imgui.IsItemDeactivatedAfterEdit() {
+			w.applyHooks()
+		}
+	})
+}
+
+func (w *webhookSettings) Draw(width int, height int) []giu.Widget {
+	if w.config == nil {
+		return []giu.Widget{}
+	}
+
+	// Remove hooks after the UI has been built so the widgets don't point at moved hooks
+	if w.deleteIndex >= 0 && w.deleteIndex < len(w.hooks) {
+		w.hooks = append(w.hooks[:w.deleteIndex], w.hooks[w.deleteIndex+1:]...)
+		w.deleteIndex = -1
+		w.applyHooks()
+	}
+
+	sink := w.currentSink()
+
+	hookRows := make([]*giu.TableRowWidget, 0, len(w.hooks))
+	for x := range w.hooks {
+		hook := &w.hooks[x]
+
+		hookRows = append(hookRows, giu.TableRow(
+			giu.Checkbox(fmt.Sprintf("##enabled%d", x), &hook.Enabled).OnChange(w.applyHooks),
+			giu.Layout{
+				giu.InputText(&hook.Name).Label(fmt.Sprintf("##name%d", x)).Size(120),
+				w.applyAfterEdit(),
+			},
+			giu.Layout{
+				giu.InputText(&hook.URL).Label(fmt.Sprintf("##url%d", x)).Size(300),
+				w.applyAfterEdit(),
+			},
+			giu.Layout{
+				giu.InputText(&hook.Secret).Label(fmt.Sprintf("##secret%d", x)).Size(120).
+					Flags(giu.InputTextFlagsPassword),
+				w.applyAfterEdit(),
+			},
+			&webhookEventsWidget{id: fmt.Sprintf("##events%d", x), hook: hook, onChange: w.applyHooks},
+			giu.Row(
+				giu.Button(fmt.Sprintf("Test##%d", x)).OnClick(func() { sink.SendTest(*hook) }),
+				giu.Button(fmt.Sprintf("Delete##%d", x)).OnClick(func() { w.deleteIndex = x }),
+			),
+		))
+	}
+
+	// Newest first
+	results := sink.Results()
+	resultRows := make([]*giu.TableRowWidget, 0, len(results))
+	for x := len(results) - 1; x >= 0; x-- {
+		result := results[x]
+
+		outcome := giu.Style().SetColor(giu.StyleColorText, colornames.Green).To(
+			giu.Label(fmt.Sprintf("OK %d", result.StatusCode)))
+		if !result.Succeeded() {
+			outcome = giu.Style().SetColor(giu.StyleColorText, colornames.Red).To(
+				giu.Label(fmt.Sprintf("Failed after %d attempts: %s", result.Attempts, result.Err)).Wrapped(true))
+		}
+
+		resultRows = append(resultRows, giu.TableRow(
+			giu.Label(result.Timestamp.Format("15:04:05")),
+			giu.Label(result.Hook),
+			giu.Label(result.Event.String()),
+			outcome,
+		))
+	}
+
+	tableHeight := float32(height-70) / 2
+
+	return []giu.Widget{
+		giu.Row(
+			giu.Button("Add Webhook").OnClick(func() {
+				w.hooks = append(w.hooks, webhooks.NewHook())
+				w.applyHooks()
+			}),
+			giu.Style().SetColor(giu.StyleColorText, colornames.Red).To(giu.Label(w.status)),
+		),
+		giu.Table().Flags(giu.TableFlagsResizable|giu.TableFlagsSizingFixedSame|giu.TableFlagsScrollY).
+			Size(float32(width-16), tableHeight).
+			Columns(
+				giu.TableColumn("On").InnerWidthOrWeight(25),
+				giu.TableColumn("Name").InnerWidthOrWeight(125),
+				giu.TableColumn("URL").InnerWidthOrWeight(305),
+				giu.TableColumn("Secret").InnerWidthOrWeight(125),
+				giu.TableColumn("Events").InnerWidthOrWeight(125),
+				giu.TableColumn("").InnerWidthOrWeight(110),
+			).
+			Rows(hookRows...),
+		giu.Label("Deliveries"),
+		giu.Table().FastMode(true).Flags(giu.TableFlagsResizable|giu.TableFlagsSizingFixedSame|giu.TableFlagsScrollY).
+			Size(float32(width-16), tableHeight).
+			Columns(
+				giu.TableColumn("Time").InnerWidthOrWeight(60),
+				giu.TableColumn("Webhook").InnerWidthOrWeight(125),
+				giu.TableColumn("Event").InnerWidthOrWeight(100),
+				giu.TableColumn("Result").InnerWidthOrWeight(450),
+			).
+			Rows(resultRows...),
+	}
+}
+
+// webhookEventsWidget is a combo of checkboxes to pick the events sent to a hook, no events means all of them
+type webhookEventsWidget struct {
+	id       string
+	hook     *webhooks.Hook
+	onChange func()
+}
+
+func (e *webhookEventsWidget) Build() {
+	events := webhooks.EventTypes()
+
+	selected := map[webhooks.EventType]bool{}
+	for _, event := range events {
+		selected[event] = len(e.hook.Events) == 0
+	}
+	for _, event := range e.hook.Events {
+		selected[event] = true
+	}
+
+	preview := "All"
+	if len(e.hook.Events) > 0 && len(e.hook.Events) < len(events) {
+		preview = fmt.Sprintf("%d of %d", len(e.hook.Events), len(events))
+	}
+
+	changed := false
+	imgui.PushItemWidth(120)
+	if imgui.BeginCombo(e.id, preview) {
+		for _, event := range events {
+			wanted := selected[event]
+			if imgui.Checkbox(event.String(), &wanted) {
+				selected[event] = wanted
+				changed = true
+			}
+		}
+
+		imgui.EndCombo()
+	}
+	imgui.PopItemWidth()
+
+	if !changed {
+		return
+	}
+
+	wanted := []webhooks.EventType{}
+	for _, event := range events {
+		if selected[event] {
+			wanted = append(wanted, event)
+		}
+	}
+
+	// No events would mean all of them so keep at least one, the hook can be turned off instead
+	if len(wanted) == 0 {
+		return
+	}
+
+	// Store every event as all so new events are sent too
+	e.hook.Events = wanted
+	if len(wanted) == len(events) {
+		e.hook.Events = nil
+	}
+	e.onChange()
+}
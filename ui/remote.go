@@ -0,0 +1,187 @@
+// F1Gopher - Copyright (C) 2025 f1gopher
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package ui
+
+import (
+	"errors"
+	"f1gopher/remoteControl"
+	"time"
+
+	"github.com/f1gopher/f1gopherlib/Messages"
+)
+
+// remote runs the remote control requests against the session being shown, using the same actions as the buttons and
+// keyboard shortcuts. Requests arrive on the web server goroutines so everything is run on the UI thread.
+type remote struct {
+	manager *Manager
+}
+
+// session returns the session being shown, only call from the UI thread
+func (r *remote) session() (dataScreen, error) {
+	switch r.manager.view {
+	case Live:
+		return r.manager.live, nil
+	case Replay:
+		return r.manager.replay, nil
+	case DebugReplay:
+		return r.manager.debugReplay, nil
+	default:
+		return nil, remoteControl.ErrNoSession
+	}
+}
+
+// run calls the action on the current session on the UI thread
+func (r *remote) run(action func(session dataScreen)) error {
+	var err error
+	if queueErr := r.manager.onUIThread(func() {
+		var session dataScreen
+		if session, err = r.session(); err == nil {
+			action(session)
+		}
+	}); queueErr != nil {
+		return queueErr
+	}
+	return err
+}
+
+func (r *remote) State() remoteControl.State {
+	var state remoteControl.State
+	if err := r.manager.onUIThread(func() { state = r.state() }); err != nil {
+		return remoteControl.State{Panels: []remoteControl.Panel{}}
+	}
+	return state
+}
+
+func (r *remote) state() remoteControl.State {
+	state := remoteControl.State{
+		View:   r.manager.view.String(),
+		Panels: []remoteControl.Panel{},
+	}
+
+	session, err := r.session()
+	if err != nil {
+		return state
+	}
+
+	controls := session.sessionControls()
+	state.HasSession = true
+	state.Session = session.sessionName()
+	state.Paused = controls.IsPaused()
+	state.CanSkipToStart = controls.CanSkipToStart()
+	state.CircleMap = session.circleMapShown()
+	state.RadioMuted = session.radioControls().IsMuted()
+	state.Focused = session.focusedDriver()
+	state.Panels = session.panelStates()
+	return state
+}
+
+func (r *remote) SetPaused(paused bool) error {
+	return r.run(func(session dataScreen) { session.sessionControls().SetPaused(paused) })
+}
+
+// TogglePause reads and changes the state in one action so it can't be changed in between
+func (r *remote) TogglePause() error {
+	return r.run(func(session dataScreen) {
+		controls := session.sessionControls()
+		controls.SetPaused(!controls.IsPaused())
+	})
+}
+
+func (r *remote) Skip(duration time.Duration) error {
+	return r.run(func(session dataScreen) { session.sessionControls().Skip(duration) })
+}
+
+func (r *remote) SkipToStart() error {
+	return r.run(func(session dataScreen) { session.sessionControls().SkipToStart() })
+}
+
+func (r *remote) Back() error {
+	return r.run(func(session dataScreen) { session.sessionControls().Back() })
+}
+
+func (r *remote) TogglePanel(name string) error {
+	found := true
+	err := r.run(func(session dataScreen) { found = session.togglePanel(name) })
+	if err == nil && !found {
+		return remoteControl.ErrUnknownPanel
+	}
+	return err
+}
+
+func (r *remote) FocusDriver(driverNumber int) error {
+	return r.run(func(session dataScreen) { session.focusDriver(driverNumber) })
+}
+
+func (r *remote) ToggleTelemetry() error {
+	return r.run(func(session dataScreen) { session.toggleTelemetryView() })
+}
+
+func (r *remote) ToggleCircleMap() error {
+	return r.run(func(session dataScreen) { session.toggleCircleMap() })
+}
+
+func (r *remote) SetRadioMuted(muted bool) error {
+	return r.run(func(session dataScreen) { session.radioControls().SetMuted(muted) })
+}
+
+func (r *remote) ToggleRadio() error {
+	return r.run(func(session dataScreen) {
+		controls := session.radioControls()
+		controls.SetMuted(!controls.IsMuted())
+	})
+}
+
+func (r *remote) Replays() []remoteControl.Replay {
+	replays := make([]remoteControl.Replay, len(r.manager.history))
+	for x, session := range r.manager.history {
+		replays[x] = remoteControl.Replay{
+			Index:   x,
+			Name:    session.Name,
+			Session: session.Type.String(),
+			Year:    session.EventTime.Year(),
+			// Same as the replay menu, pre-season tests can't be replayed
+			Available: session.Type != Messages.PreSeasonSession,
+		}
+	}
+	return replays
+}
+
+func (r *remote) OpenReplay(index int) error {
+	if index < 0 || index >= len(r.manager.history) {
+		return remoteControl.ErrUnknownReplay
+	}
+
+	session := r.manager.history[index]
+	if session.Type == Messages.PreSeasonSession {
+		return remoteControl.ErrReplayUnavailable
+	}
+
+	var err error
+	if queueErr := r.manager.onUIThread(func() {
+		// Close the current session first, changing from a replay to a replay doesn't
+		if _, sessionErr := r.session(); sessionErr == nil {
+			r.manager.changeView(MainMenu, nil)
+		}
+		r.manager.changeView(Replay, &session)
+
+		if r.manager.view != Replay {
+			err = errors.New("starting the replay failed, see the log for details")
+		}
+	}); queueErr != nil {
+		return queueErr
+	}
+	return err
+}
@@ -17,6 +17,9 @@ package ui
 
 import (
 	"context"
+	"errors"
+	"f1gopher/remoteControl"
+	"f1gopher/ui/panel"
 	"f1gopher/ui/webTimingView"
 	"sync"
 	"time"
@@ -40,6 +43,10 @@ const (
 	Quit
 )
 
+func (s screen) String() string {
+	return [...]string{"Main Menu", "Replay Menu", "Live", "Replay", "Debug Replay", "Options Menu", "Quit"}[s]
+}
+
 type drawableScreen interface {
 	draw(width int, height int)
 }
@@ -51,6 +58,19 @@ type dataScreen interface {
 	close()
 	toggleTelemetryView()
 	toggleCircleMap()
+
+	// Used by the remote control
+	circleMapShown() bool
+	sessionName() string
+	sessionControls() panel.SessionControls
+	radioControls() panel.RadioControls
+	focusedDriver() int
+	focusDriver(driverNumber int)
+	togglePanel(name string) bool
+	panelStates() []remoteControl.Panel
+
+	// Used by the web timing server
+	chart(name string) (panel.ChartRenderer, bool)
 }
 
 type Manager struct {
@@ -59,6 +79,7 @@ type Manager struct {
 	view            screen
 	previousView    screen
 	currentSession  *f1gopherlib.RaceEvent
+	history         []f1gopherlib.RaceEvent
 	debugReplayFile string
 	config          config
 
@@ -71,11 +92,19 @@ type Manager struct {
 
 	webTiming *webTimingView.WebTiming
 
+	// Actions from other goroutines that have to run on the UI thread, run before the next frame is drawn
+	uiActions chan func()
+
 	shutdownWg  sync.WaitGroup
 	ctxShutdown context.CancelFunc
 	ctx         context.Context
 }
 
+// Longest to wait for the UI to run an action from another goroutine
+const uiActionTimeout = 5 * time.Second
+
+var errUIUnavailable = errors.New("the window isn't responding")
+
 const dataSources = parser.EventTime | parser.Timing | parser.Event | parser.RaceControl |
 	parser.TeamRadio | parser.Weather | parser.Location | parser.Telemetry | parser.Drivers
 
@@ -86,6 +115,7 @@ func Create(logger *zap.SugaredLogger, wnd *giu.MasterWindow, config config, aut
 		view:         MainMenu,
 		previousView: MainMenu,
 		config:       config,
+		uiActions:    make(chan func(), 16),
 	}
 
 	// Context to shutdown go routines
@@ -105,6 +135,7 @@ func Create(logger *zap.SugaredLogger, wnd *giu.MasterWindow, config config, aut
 	}
 	for _, x := range f1gopherlib.RaceHistory() {
 		r.history = append(r.history, x)
+		manager.history = append(manager.history, x)
 	}
 	manager.replayMenu = &r
 	manager.optionsMenu = &optionsMenu{
@@ -112,13 +143,16 @@ func Create(logger *zap.SugaredLogger, wnd *giu.MasterWindow, config config, aut
 		config:     &manager.config,
 	}
 
-	manager.webTiming = webTimingView.CreateWebTimingView(&manager.shutdownWg, manager.ctx, config.webTimingAddresses)
+	controller := &remote{manager: &manager}
+	manager.webTiming = webTimingView.CreateWebTimingView(
+		&manager.shutdownWg, manager.ctx, config.webTimingAddresses, controller, &webCharts{remote: controller})
+	manager.webTiming.SetRemoteControlToken(config.remoteControlToken)
 	if manager.config.webTimingViewEnabled {
 		manager.webTiming.Start()
 	}
 
-	manager.live = createDataView(manager.webTiming, manager.changeView, true)
-	manager.replay = createDataView(manager.webTiming, manager.changeView, false)
+	manager.live = createDataView(manager.webTiming, manager.changeView, true, manager.logger)
+	manager.replay = createDataView(manager.webTiming, manager.changeView, false, manager.logger)
 	manager.debugReplay = &debugReplayView{dataView{changeView: manager.changeView}}
 
 	// Redraw the main menu screen every second to update the countdown and current session UI
@@ -151,6 +185,8 @@ func Create(logger *zap.SugaredLogger, wnd *giu.MasterWindow, config config, aut
 }
 
 func (u *Manager) Loop() {
+	u.runQueuedActions()
+
 	width, height := u.wnd.GetSize()
 
 	switch u.view {
@@ -181,6 +217,64 @@ func (u *Manager) Loop() {
 	}
 }
 
+// onUIThread runs the action before the next frame is drawn and waits for it to finish. Anything running on another
+// goroutine, like the web server, uses this to read or change the UI state. If the UI doesn't get to the action in
+// time it is cancelled so it can't happen after the caller has been told it failed.
+func (u *Manager) onUIThread(action func()) error {
+	done := make(chan struct{})
+	var stateLock sync.Mutex
+	started := false
+	cancelled := false
+
+	select {
+	case u.uiActions <- func() {
+		stateLock.Lock()
+		if cancelled {
+			stateLock.Unlock()
+			return
+		}
+		started = true
+		stateLock.Unlock()
+
+		action()
+		close(done)
+	}:
+	case <-u.ctx.Done():
+		return errUIUnavailable
+	}
+
+	// Wake up the UI in case nothing else is causing it to redraw
+	giu.Update()
+
+	select {
+	case <-done:
+		return nil
+	case <-u.ctx.Done():
+	case <-time.After(uiActionTimeout):
+	}
+
+	stateLock.Lock()
+	defer stateLock.Unlock()
+	if started {
+		// Too late to cancel, the action is running so wait for it to finish
+		<-done
+		return nil
+	}
+	cancelled = true
+	return errUIUnavailable
+}
+
+func (u *Manager) runQueuedActions() {
+	for {
+		select {
+		case action := <-u.uiActions:
+			action()
+		default:
+			return
+		}
+	}
+}
+
 func (u *Manager) changeView(newView screen, info any) {
 	// If we are stopping a dataview then clear the web timing display
 	if u.view == Live && newView != Live {
@@ -198,8 +292,13 @@ func (u *Manager) changeView(newView screen, info any) {
 		u.webTiming.Pause()
 	}
 
-	// If we have edited the config then check if we need to enable/disable the web display
+	// If we have edited the config then save it and check if we need to enable/disable the web display
 	if u.view == OptionsMenu && newView != OptionsMenu {
+		if err := u.config.save(); err != nil {
+			u.logger.Errorln("Saving config", err)
+		}
+
+		u.webTiming.SetRemoteControlToken(u.config.remoteControlToken)
 		if u.config.webTimingViewEnabled {
 			u.webTiming.Start()
 		} else {
@@ -210,6 +309,7 @@ func (u *Manager) changeView(newView screen, info any) {
 	switch newView {
 	case Live:
 		u.currentSession = info.(*f1gopherlib.RaceEvent)
+		u.config.setSession(u.currentSession)
 		data, err := f1gopherlib.CreateLive(dataSources, "", u.config.sessionCache())
 		if err != nil {
 			u.logger.Errorln("Starting live session", err)
@@ -219,6 +319,7 @@ func (u *Manager) changeView(newView screen, info any) {
 
 	case Replay:
 		u.currentSession = info.(*f1gopherlib.RaceEvent)
+		u.config.setSession(u.currentSession)
 		data, err := f1gopherlib.CreateReplay(
 			dataSources,
 			*u.currentSession,
@@ -232,6 +333,8 @@ func (u *Manager) changeView(newView screen, info any) {
 
 	case DebugReplay:
 		u.debugReplayFile = info.(string)
+		// Debug replays aren't cached
+		u.config.setSession(nil)
 		data, err := f1gopherlib.CreateDebugReplay(dataSources, u.debugReplayFile, flowControl.Realtime)
 		if err != nil {
 			u.logger.Errorln("Starting debug replay session", err)
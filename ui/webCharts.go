@@ -0,0 +1,44 @@
+// F1Gopher - Copyright (C) 2025 f1gopher
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package ui
+
+import (
+	"f1gopher/ui/panel"
+	"f1gopher/ui/webTimingView"
+)
+
+// webCharts gives the web timing server the charts of the session being shown. The lookup happens on the UI thread,
+// drawing the chart doesn't because the charts lock themselves while drawing
+type webCharts struct {
+	remote *remote
+}
+
+func (w *webCharts) Chart(name string) (panel.ChartRenderer, error) {
+	var chart panel.ChartRenderer
+	exists := false
+	if err := w.remote.manager.onUIThread(func() {
+		if session, err := w.remote.session(); err == nil {
+			chart, exists = session.chart(name)
+		}
+	}); err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		return nil, webTimingView.ErrNoChart
+	}
+	return chart, nil
+}
@@ -0,0 +1,103 @@
+// F1Gopher - Copyright (C) 2025 f1gopher
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package webTimingView
+
+import (
+	"errors"
+	"f1gopher/ui/panel"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+const (
+	chartDefaultWidth  = 800
+	chartDefaultHeight = 400
+	chartMinSize       = 100
+	chartMaxWidth      = 2000
+	chartMaxHeight     = 1500
+)
+
+// ErrNoChart is returned when there isn't a session showing a chart with the name
+var ErrNoChart = errors.New("no chart with that name")
+
+// ChartSource finds the charts of the session being shown in the window
+type ChartSource interface {
+	Chart(name string) (panel.ChartRenderer, error)
+}
+
+// chartPanels are the charts shown on the web timing page
+var chartPanels = []panel.Type{panel.RacePosition, panel.GapperPlot, panel.Telemetry}
+
+func (w *WebTiming) addChartRoutes(router *mux.Router) {
+	router.HandleFunc("/chart/{name}.{format:png|svg}", w.chartHandler).Methods(http.MethodGet)
+}
+
+// chartHandler renders a chart at the size requested by the width and height query parameters
+func (w *WebTiming) chartHandler(writer http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	width, err := chartSize(r, "width", chartDefaultWidth, chartMaxWidth)
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+		return
+	}
+	height, err := chartSize(r, "height", chartDefaultHeight, chartMaxHeight)
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	chart, err := w.charts.Chart(vars["name"])
+	if errors.Is(err, ErrNoChart) {
+		http.Error(writer, err.Error(), http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(writer, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	svg := vars["format"] == "svg"
+	data, err := chart.RenderChart(width, height, svg)
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if svg {
+		writer.Header().Set("Content-Type", "image/svg+xml")
+	} else {
+		writer.Header().Set("Content-Type", "image/png")
+	}
+	// The chart changes with the session so always ask for the latest
+	writer.Header().Set("Cache-Control", "no-store")
+	writer.Write(data)
+}
+
+func chartSize(r *http.Request, name string, defaultValue int, maxValue int) (int, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	size, err := strconv.Atoi(value)
+	if err != nil || size < chartMinSize || size > maxValue {
+		return 0, fmt.Errorf("%s must be between %d and %d", name, chartMinSize, maxValue)
+	}
+	return size, nil
+}
@@ -0,0 +1,675 @@
+// F1Gopher - Copyright (C) 2025 f1gopher
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package webTimingView
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"f1gopher/outputFormat"
+	"f1gopher/ui/panel"
+	"fmt"
+	"html/template"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/f1gopher/f1gopherlib/Messages"
+	"github.com/gorilla/mux"
+)
+
+// How many race control messages the ticker overlay is sent
+const overlayRCMCount = 5
+
+// The overlay styles and script are inline in the page so only the ones with the page's nonce are allowed to run
+const overlayContentSecurityPolicy = "default-src 'none'; script-src 'nonce-%[1]s'; style-src 'nonce-%[1]s'; " +
+	"connect-src 'self'; base-uri 'none'; form-action 'none'; frame-ancestors 'none'"
+
+var (
+	hexColorRegex = regexp.MustCompile(`^[0-9a-fA-F]{3,8}$`)
+	fontRegex     = regexp.MustCompile(`^[A-Za-z0-9 ,-]+$`)
+)
+
+// BattleSource finds the battles of the session being shown in the window
+type BattleSource interface {
+	ClosestPair() (panel.BattlePair, bool)
+}
+
+// overlay is a page for a streaming software browser source. The page is sent the overlay's state as JSON whenever it
+// changes.
+type overlay struct {
+	name   string
+	title  string
+	body   template.HTML
+	style  template.CSS
+	script template.JS
+	state  func(w *WebTiming) any
+}
+
+// overlayFeed keeps the latest state of an overlay and pushes changes to the connected pages
+type overlayFeed struct {
+	latest      []byte
+	subscribers map[chan []byte]bool
+	lock        sync.Mutex
+}
+
+func (f *overlayFeed) publish(data []byte) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if bytes.Equal(data, f.latest) {
+		return
+	}
+	f.latest = data
+
+	for subscriber := range f.subscribers {
+		// Only the latest state matters so replace anything the page hasn't been sent yet
+		select {
+		case <-subscriber:
+		default:
+		}
+		subscriber <- data
+	}
+}
+
+func (f *overlayFeed) subscribe() (chan []byte, func()) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	subscriber := make(chan []byte, 1)
+	if f.latest != nil {
+		subscriber <- f.latest
+	}
+	f.subscribers[subscriber] = true
+
+	return subscriber, func() {
+		f.lock.Lock()
+		delete(f.subscribers, subscriber)
+		f.lock.Unlock()
+	}
+}
+
+// overlayTheme is the styling set by the query parameters, anything invalid keeps the default
+type overlayTheme struct {
+	Foreground template.CSS
+	Background template.CSS
+	Accent     template.CSS
+	Font       template.CSS
+	Size       template.CSS
+}
+
+func themeFromQuery(r *http.Request) overlayTheme {
+	query := r.URL.Query()
+
+	color := func(name string, defaultValue string) template.CSS {
+		if value := query.Get(name); hexColorRegex.MatchString(value) {
+			return template.CSS("#" + value)
+		}
+		return template.CSS(defaultValue)
+	}
+
+	theme := overlayTheme{
+		Foreground: color("fg", "#ffffff"),
+		Background: color("bg", "#15151ecc"),
+		Accent:     color("accent", "#e10600"),
+		Font:       "Arial, Helvetica, sans-serif",
+		Size:       "20px",
+	}
+	if font := query.Get("font"); fontRegex.MatchString(font) {
+		theme.Font = template.CSS(font)
+	}
+	if size, err := strconv.Atoi(query.Get("size")); err == nil && size >= 8 && size <= 200 {
+		theme.Size = template.CSS(fmt.Sprintf("%dpx", size))
+	}
+	return theme
+}
+
+var overlayPage = template.Must(template.New("overlay").Parse(`<!DOCTYPE html>
+<html>
+<head>
+	<meta charset="utf-8">
+	<title>F1Gopher {{.Title}}</title>
+	<style nonce="{{.Nonce}}">
+		:root {
+			--fg: {{.Theme.Foreground}};
+			--bg: {{.Theme.Background}};
+			--accent: {{.Theme.Accent}};
+			--font: {{.Theme.Font}};
+			--size: {{.Theme.Size}};
+		}
+		html, body { background: transparent; margin: 0; overflow: hidden; }
+		body { color: var(--fg); font-family: var(--font); font-size: var(--size); font-weight: bold; }
+		.hidden { display: none !important; }
+		{{.Style}}
+	</style>
+</head>
+<body data-rows="{{.Rows}}" data-gap="{{.Gap}}">
+	{{.Body}}
+	<script nonce="{{.Nonce}}">
+		function cell(className, text) {
+			const element = document.createElement("span");
+			element.className = className;
+			element.textContent = text;
+			return element;
+		}
+
+		{{.Script}}
+
+		const source = new EventSource({{.Events}});
+		source.onmessage = function(event) { render(JSON.parse(event.data)); };
+	</script>
+</body>
+</html>`))
+
+var overlays = []overlay{
+	{
+		name:  "tower",
+		title: "Timing Tower",
+		body: `<div id="tower">
+		<div id="title"></div>
+		<div id="rows"></div>
+	</div>`,
+		style: `#tower { display: inline-block; background: var(--bg); padding: 0.3em 0; min-width: 11em; }
+		#title { background: var(--accent); padding: 0.2em 0.5em; margin-bottom: 0.2em; }
+		.row { display: flex; align-items: center; padding: 0.1em 0.5em; }
+		.row.out, .row.pit { opacity: 0.5; }
+		.position { width: 1.6em; text-align: right; margin-right: 0.4em; }
+		.team { width: 0.25em; height: 1em; margin-right: 0.4em; }
+		.name { width: 3em; }
+		.gap { flex: 1; text-align: right; font-weight: normal; min-width: 5em; }
+		.tyre { width: 1em; margin-left: 0.4em; text-align: center; }`,
+		script: `const rows = Number(document.body.dataset.rows);
+		const showInterval = document.body.dataset.gap === "interval";
+
+		function render(state) {
+			document.getElementById("title").textContent = state.title;
+
+			const list = document.getElementById("rows");
+			list.replaceChildren();
+			for (const row of state.rows.slice(0, rows)) {
+				const line = document.createElement("div");
+				line.className = "row" + (row.out ? " out" : "") + (row.inPit ? " pit" : "");
+
+				const team = cell("team", "");
+				team.style.background = row.color;
+				const tyre = cell("tyre", row.tyre.charAt(0));
+				tyre.style.color = row.tyreColor;
+
+				line.append(cell("position", row.position), team, cell("name", row.name),
+					cell("gap", row.inPit ? "PIT" : (showInterval ? row.interval : row.gap)), tyre);
+				list.append(line);
+			}
+		}`,
+		state: func(w *WebTiming) any { return w.towerState() },
+	},
+	{
+		name:  "battle",
+		title: "Battle",
+		body: `<div id="battle" class="hidden">
+		<div id="heading"></div>
+		<div id="drivers"></div>
+	</div>`,
+		style: `#battle { position: absolute; left: 2em; bottom: 2em; background: var(--bg); min-width: 16em; }
+		#heading { background: var(--accent); padding: 0.2em 0.6em; }
+		#drivers { display: flex; align-items: center; padding: 0.3em 0.6em; gap: 0.6em; }
+		.driver { display: flex; align-items: center; gap: 0.3em; }
+		.team { width: 0.25em; height: 1.2em; }
+		.gap { font-weight: normal; }`,
+		script: `function render(state) {
+			document.getElementById("battle").classList.toggle("hidden", !state.active);
+			if (!state.active) {
+				return;
+			}
+
+			let heading = "BATTLE FOR P" + state.position;
+			if (state.trend !== "") {
+				heading += " - " + state.trend.toUpperCase();
+			}
+			document.getElementById("heading").textContent = heading;
+
+			const drivers = document.getElementById("drivers");
+			drivers.replaceChildren();
+			state.drivers.forEach(function(driver, index) {
+				if (index > 0) {
+					drivers.append(cell("gap", state.gap));
+				}
+				const element = document.createElement("span");
+				element.className = "driver";
+				const team = cell("team", "");
+				team.style.background = driver.color;
+				element.append(team, cell("name", driver.name));
+				drivers.append(element);
+			});
+		}`,
+		state: func(w *WebTiming) any { return w.battleState() },
+	},
+	{
+		name:  "flag",
+		title: "Flag",
+		body:  `<div id="banner" class="hidden"></div>`,
+		style: `#banner { text-align: center; padding: 0.3em 1em; letter-spacing: 0.1em; }`,
+		script: `function render(state) {
+			const banner = document.getElementById("banner");
+			banner.classList.toggle("hidden", !state.show);
+			banner.textContent = state.text;
+			banner.style.background = state.color;
+			banner.style.color = state.textColor;
+		}`,
+		state: func(w *WebTiming) any { return w.flagState() },
+	},
+	{
+		name:  "clock",
+		title: "Session Clock",
+		body: `<div id="clock">
+		<span id="session"></span>
+		<span id="remaining"></span>
+		<span id="lap"></span>
+	</div>`,
+		style: `#clock { display: inline-flex; background: var(--bg); }
+		#clock span { padding: 0.2em 0.6em; }
+		#session { background: var(--accent); }
+		#remaining { font-variant-numeric: tabular-nums; }
+		#remaining.stopped { opacity: 0.6; }`,
+		script: `function render(state) {
+			document.getElementById("session").textContent = state.session;
+			const remaining = document.getElementById("remaining");
+			remaining.textContent = state.remaining;
+			remaining.classList.toggle("stopped", !state.running);
+			const lap = document.getElementById("lap");
+			lap.textContent = state.lap;
+			lap.classList.toggle("hidden", state.lap === "");
+		}`,
+		state: func(w *WebTiming) any { return w.clockState() },
+	},
+	{
+		name:  "rcm",
+		title: "Race Control Ticker",
+		body: `<div id="ticker" class="hidden">
+		<span id="label">RACE CONTROL</span>
+		<span id="window"><span id="message"></span></span>
+	</div>`,
+		style: `#ticker { display: flex; background: var(--bg); white-space: nowrap; }
+		#label { background: var(--accent); padding: 0.2em 0.6em; }
+		#window { flex: 1; overflow: hidden; padding: 0.2em 0.6em; }
+		#message { display: inline-block; font-weight: normal; }
+		#message.scroll { animation: scroll 15s linear infinite; padding-left: 100%; }
+		@keyframes scroll { from { transform: translateX(0); } to { transform: translateX(-100%); } }`,
+		script: `function render(state) {
+			document.getElementById("ticker").classList.toggle("hidden", state.messages.length === 0);
+			if (state.messages.length === 0) {
+				return;
+			}
+
+			const latest = state.messages[0];
+			const message = document.getElementById("message");
+			message.textContent = latest.time + "  " + latest.text;
+			message.style.color = latest.color;
+			// Only scroll messages that don't fit
+			message.classList.remove("scroll");
+			if (message.scrollWidth > document.getElementById("window").clientWidth) {
+				message.classList.add("scroll");
+			}
+		}`,
+		state: func(w *WebTiming) any { return w.rcmState() },
+	},
+}
+
+func (w *WebTiming) addOverlayRoutes(router *mux.Router) {
+	for _, current := range overlays {
+		current := current
+		feed := w.overlayFeeds[current.name]
+
+		router.HandleFunc("/overlay/"+current.name, func(writer http.ResponseWriter, r *http.Request) {
+			rows, err := strconv.Atoi(r.URL.Query().Get("rows"))
+			if err != nil || rows <= 0 {
+				rows = 20
+			}
+			gap := "leader"
+			if r.URL.Query().Get("gap") == "interval" {
+				gap = "interval"
+			}
+
+			nonce, err := overlayNonce()
+			if err != nil {
+				http.Error(writer, err.Error(), http.StatusInternalServerError)
+				return
+			}
+
+			writer.Header().Set("Content-Type", "text/html; charset=utf-8")
+			writer.Header().Set("Content-Security-Policy", fmt.Sprintf(overlayContentSecurityPolicy, nonce))
+			writer.Header().Set("X-Content-Type-Options", "nosniff")
+			writer.Header().Set("Referrer-Policy", "no-referrer")
+			overlayPage.Execute(writer, map[string]any{
+				"Nonce":  nonce,
+				"Title":  current.title,
+				"Theme":  themeFromQuery(r),
+				"Style":  current.style,
+				"Body":   current.body,
+				"Script": current.script,
+				"Events": "/overlay/" + current.name + "/events",
+				"Rows":   rows,
+				"Gap":    gap,
+			})
+		})
+
+		router.HandleFunc("/overlay/"+current.name+"/events", func(writer http.ResponseWriter, r *http.Request) {
+			w.streamOverlay(writer, r, feed)
+		})
+	}
+}
+
+// overlayNonce returns a new random value for each page so the inline style and script can be allowed without
+// allowing any others
+func overlayNonce() (string, error) {
+	value := make([]byte, 16)
+	if _, err := rand.Read(value); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(value), nil
+}
+
+// streamOverlay sends the overlay's state as server sent events until the page or server is closed
+func (w *WebTiming) streamOverlay(writer http.ResponseWriter, r *http.Request, feed *overlayFeed) {
+	controller := http.NewResponseController(writer)
+	// The server's write timeout would end the stream
+	if err := controller.SetWriteDeadline(time.Time{}); err != nil {
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writer.Header().Set("Content-Type", "text/event-stream")
+	writer.Header().Set("Cache-Control", "no-cache")
+	fmt.Fprint(writer, "retry: 2000\n\n")
+	if controller.Flush() != nil {
+		return
+	}
+
+	updates, unsubscribe := feed.subscribe()
+	defer unsubscribe()
+
+	// Stops proxies and browsers dropping a quiet connection
+	keepAlive := time.NewTicker(15 * time.Second)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-w.ctx.Done():
+			return
+		case data := <-updates:
+			fmt.Fprintf(writer, "data: %s\n\n", data)
+		case <-keepAlive.C:
+			fmt.Fprint(writer, ": keep alive\n\n")
+		}
+
+		if controller.Flush() != nil {
+			return
+		}
+	}
+}
+
+// updateOverlays pushes the state of any overlays that have changed
+func (w *WebTiming) updateOverlays() {
+	for _, current := range overlays {
+		data, err := json.Marshal(current.state(w))
+		if err != nil {
+			continue
+		}
+		w.overlayFeeds[current.name].publish(data)
+	}
+}
+
+type towerRow struct {
+	Position  int    `json:"position"`
+	Number    int    `json:"number"`
+	Name      string `json:"name"`
+	Color     string `json:"color"`
+	Gap       string `json:"gap"`
+	Interval  string `json:"interval"`
+	Tyre      string `json:"tyre"`
+	TyreColor string `json:"tyreColor"`
+	InPit     bool   `json:"inPit"`
+	Out       bool   `json:"out"`
+}
+
+type towerState struct {
+	Title string     `json:"title"`
+	Rows  []towerRow `json:"rows"`
+}
+
+type battleDriver struct {
+	Number   int    `json:"number"`
+	Name     string `json:"name"`
+	Color    string `json:"color"`
+	Position int    `json:"position"`
+}
+
+type battleState struct {
+	Active   bool           `json:"active"`
+	Position int            `json:"position"`
+	Gap      string         `json:"gap"`
+	Trend    string         `json:"trend"`
+	Drivers  []battleDriver `json:"drivers"`
+}
+
+type flagState struct {
+	Show      bool   `json:"show"`
+	Text      string `json:"text"`
+	Color     string `json:"color"`
+	TextColor string `json:"textColor"`
+}
+
+type clockState struct {
+	Session   string `json:"session"`
+	Remaining string `json:"remaining"`
+	Lap       string `json:"lap"`
+	Running   bool   `json:"running"`
+}
+
+type rcmEntry struct {
+	Time  string `json:"time"`
+	Text  string `json:"text"`
+	Color string `json:"color"`
+}
+
+type rcmState struct {
+	Messages []rcmEntry `json:"messages"`
+}
+
+func (w *WebTiming) sortedDrivers() []Messages.Timing {
+	w.dataLock.Lock()
+	drivers := make([]Messages.Timing, 0, len(w.data))
+	for _, driver := range w.data {
+		drivers = append(drivers, driver)
+	}
+	w.dataLock.Unlock()
+
+	sort.Slice(drivers, func(i, j int) bool {
+		return drivers[i].Position < drivers[j].Position
+	})
+	return drivers
+}
+
+func (w *WebTiming) currentEvent() Messages.Event {
+	w.eventLock.Lock()
+	defer w.eventLock.Unlock()
+
+	return w.event
+}
+
+func (w *WebTiming) towerState() towerState {
+	state := towerState{Rows: []towerRow{}}
+	if w.dataSrc == nil {
+		return state
+	}
+
+	event := w.currentEvent()
+	if w.raceSession {
+		state.Title = fmt.Sprintf("LAP %d/%d", event.CurrentLap, event.TotalLaps)
+	} else {
+		state.Title = event.Type.String()
+	}
+
+	for _, driver := range w.sortedDrivers() {
+		gap := driver.TimeDiffToFastest
+		if w.raceSession {
+			gap = driver.GapToLeader
+		}
+
+		row := towerRow{
+			Position:  driver.Position,
+			Number:    driver.Number,
+			Name:      driver.ShortName,
+			Color:     driver.HexColor,
+			Gap:       fmtOverlayGap(gap),
+			Interval:  fmtOverlayGap(driver.TimeDiffToPositionAhead),
+			Tyre:      driver.Tire.String(),
+			TyreColor: tireColor(driver.Tire),
+			InPit:     driver.Location == Messages.Pitlane || driver.Location == Messages.PitOut,
+			Out: driver.KnockedOutOfQualifying || driver.Location == Messages.Stopped ||
+				driver.Location == Messages.OutOfRace,
+		}
+
+		// The leader shows their time in qualifying and practice instead of a gap
+		if driver.Position == 1 {
+			row.Gap = "Leader"
+			if !w.raceSession {
+				row.Gap = outputFormat.LapTime(driver.FastestLap)
+			}
+			row.Interval = row.Gap
+		}
+
+		state.Rows = append(state.Rows, row)
+	}
+	return state
+}
+
+// battleState returns the two cars with the smallest gap in the battles found for the window during a race
+func (w *WebTiming) battleState() battleState {
+	state := battleState{Drivers: []battleDriver{}}
+	if w.dataSrc == nil || !w.raceSession {
+		return state
+	}
+
+	w.overlayLock.Lock()
+	battles := w.battles
+	w.overlayLock.Unlock()
+	if battles == nil {
+		return state
+	}
+
+	pair, found := battles.ClosestPair()
+	if !found {
+		return state
+	}
+
+	w.dataLock.Lock()
+	ahead, aheadExists := w.data[pair.Ahead]
+	behind, behindExists := w.data[pair.Behind]
+	w.dataLock.Unlock()
+	if !aheadExists || !behindExists {
+		return state
+	}
+
+	state.Active = true
+	state.Position = ahead.Position
+	state.Gap = fmtOverlayGap(pair.Gap)
+	state.Trend = pair.Trend
+	for _, driver := range []Messages.Timing{ahead, behind} {
+		state.Drivers = append(state.Drivers, battleDriver{
+			Number:   driver.Number,
+			Name:     driver.ShortName,
+			Color:    driver.HexColor,
+			Position: driver.Position,
+		})
+	}
+	return state
+}
+
+// flagState returns the banner for anything other than green running, the most serious first
+func (w *WebTiming) flagState() flagState {
+	if w.dataSrc == nil {
+		return flagState{}
+	}
+
+	event := w.currentEvent()
+	switch {
+	case event.TrackStatus == Messages.RedFlag:
+		return flagState{Show: true, Text: "RED FLAG", Color: "#FF0000", TextColor: "#FFFFFF"}
+	case event.SafetyCar == Messages.SafetyCar:
+		return flagState{Show: true, Text: "SAFETY CAR", Color: "#FFA500", TextColor: "#000000"}
+	case event.SafetyCar == Messages.SafetyCarEnding:
+		return flagState{Show: true, Text: "SAFETY CAR IN THIS LAP", Color: "#FFA500", TextColor: "#000000"}
+	case event.SafetyCar == Messages.VirtualSafetyCar:
+		return flagState{Show: true, Text: "VIRTUAL SAFETY CAR", Color: "#FFA500", TextColor: "#000000"}
+	case event.SafetyCar == Messages.VirtualSafetyCarEnding:
+		return flagState{Show: true, Text: "VSC ENDING", Color: "#FFA500", TextColor: "#000000"}
+	case event.TrackStatus == Messages.ChequeredFlag:
+		return flagState{Show: true, Text: "CHEQUERED FLAG", Color: "#FFFFFF", TextColor: "#000000"}
+	case event.TrackStatus == Messages.DoubleYellowFlag:
+		return flagState{Show: true, Text: "DOUBLE YELLOW", Color: "#FFFF00", TextColor: "#000000"}
+	case event.TrackStatus == Messages.YellowFlag:
+		return flagState{Show: true, Text: "YELLOW FLAG", Color: "#FFFF00", TextColor: "#000000"}
+	default:
+		return flagState{}
+	}
+}
+
+func (w *WebTiming) clockState() clockState {
+	if w.dataSrc == nil {
+		return clockState{}
+	}
+
+	event := w.currentEvent()
+	w.eventLock.Lock()
+	remaining := w.remainingTime
+	w.eventLock.Unlock()
+
+	state := clockState{
+		Session:   event.Type.String(),
+		Remaining: outputFormat.Clock(remaining),
+		Running:   !event.ClockStopped,
+	}
+	if w.raceSession && event.TotalLaps > 0 {
+		state.Lap = fmt.Sprintf("LAP %d/%d", event.CurrentLap, event.TotalLaps)
+	}
+	return state
+}
+
+func (w *WebTiming) rcmState() rcmState {
+	state := rcmState{Messages: []rcmEntry{}}
+	if w.dataSrc == nil {
+		return state
+	}
+
+	w.rcMessagesLock.Lock()
+	defer w.rcMessagesLock.Unlock()
+
+	for x := len(w.rcMessages) - 1; x >= 0 && x >= len(w.rcMessages)-overlayRCMCount; x-- {
+		msg := w.rcMessages[x]
+		state.Messages = append(state.Messages, rcmEntry{
+			Time:  msg.Timestamp.In(w.dataSrc.CircuitTimezone()).Format("15:04:05"),
+			Text:  msg.Msg,
+			Color: rcmFlagColor(msg.Flag),
+		})
+	}
+	return state
+}
@@ -0,0 +1,43 @@
+package webTimingView
+
+import (
+	"context"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+func TestOverlayContentSecurityPolicy(t *testing.T) {
+	web := CreateWebTimingView(&sync.WaitGroup{}, context.Background(), nil, nil, nil)
+	router := mux.NewRouter()
+	web.addOverlayRoutes(router)
+
+	nonceRegex := regexp.MustCompile(`'nonce-([^']+)'`)
+	nonces := map[string]bool{}
+	for _, current := range overlays {
+		response := httptest.NewRecorder()
+		router.ServeHTTP(response, httptest.NewRequest("GET", "/overlay/"+current.name, nil))
+
+		policy := response.Header().Get("Content-Security-Policy")
+		nonce := nonceRegex.FindStringSubmatch(policy)
+		if !strings.HasPrefix(policy, "default-src 'none';") || nonce == nil {
+			t.Errorf("%s: expected a policy with a nonce, got %q", current.name, policy)
+			continue
+		}
+		if nonces[nonce[1]] {
+			t.Errorf("%s: expected a new nonce for each page", current.name)
+		}
+		nonces[nonce[1]] = true
+
+		body := response.Body.String()
+		for _, tag := range []string{"<style", "<script"} {
+			if strings.Count(body, tag) != strings.Count(body, tag+` nonce="`+nonce[1]+`"`) {
+				t.Errorf("%s: expected every %s> to have the nonce", current.name, tag)
+			}
+		}
+	}
+}
@@ -0,0 +1,67 @@
+// The timing page is served with a content security policy that blocks inline scripts and styles so everything
+// dynamic is done from here
+"use strict";
+
+const themeQuery = window.matchMedia("(prefers-color-scheme: light)");
+
+function applyTheme() {
+	const theme = localStorage.getItem("f1gopherTheme") || (themeQuery.matches ? "light" : "dark");
+	document.documentElement.dataset.theme = theme;
+}
+
+function applyGap() {
+	const summary = document.querySelector("[data-default-gap]");
+	const defaultGap = summary !== null ? summary.dataset.defaultGap : "leader";
+	document.body.dataset.gap = localStorage.getItem("f1gopherGap") || defaultGap;
+}
+
+async function refreshTiming() {
+	try {
+		const response = await fetch("/data");
+		const display = document.getElementById("display");
+		// The content is escaped by the server
+		display.innerHTML = await response.text();
+
+		for (const element of display.querySelectorAll("[data-color]")) {
+			element.style.backgroundColor = element.dataset.color;
+		}
+		applyGap();
+	} catch (error) {
+		// Try again next time, the server may be restarting
+	}
+
+	setTimeout(refreshTiming, 1000);
+}
+
+// The charts are redrawn by the server when the data changes so reload them regularly
+function refreshCharts() {
+	const width = Math.min(Math.max(window.innerWidth - 40, 100), 1200);
+	for (const image of document.querySelectorAll("img[data-chart]")) {
+		image.src = "/chart/" + image.dataset.chart + ".png?width=" + width + "&height=300&t=" + Date.now();
+	}
+}
+
+for (const image of document.querySelectorAll("img[data-chart]")) {
+	image.addEventListener("load", function() { image.classList.remove("hidden"); });
+	image.addEventListener("error", function() { image.classList.add("hidden"); });
+}
+
+const gapSelect = document.getElementById("gap");
+gapSelect.value = localStorage.getItem("f1gopherGap") || "";
+gapSelect.addEventListener("change", function() {
+	localStorage.setItem("f1gopherGap", gapSelect.value);
+	applyGap();
+});
+
+const themeSelect = document.getElementById("theme");
+themeSelect.value = localStorage.getItem("f1gopherTheme") || "";
+themeSelect.addEventListener("change", function() {
+	localStorage.setItem("f1gopherTheme", themeSelect.value);
+	applyTheme();
+});
+themeQuery.addEventListener("change", applyTheme);
+
+applyTheme();
+refreshTiming();
+refreshCharts();
+setInterval(refreshCharts, 5000);
@@ -0,0 +1,348 @@
+// F1Gopher - Copyright (C) 2025 f1gopher
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package webTimingView
+
+import (
+	"bytes"
+	_ "embed"
+	"f1gopher/outputFormat"
+	"f1gopher/regulations"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/f1gopher/f1gopherlib/Messages"
+	"github.com/gorilla/mux"
+)
+
+// Everything on the timing page is served from here so scripts, styles and images from anywhere else are blocked
+const timingContentSecurityPolicy = "default-src 'none'; script-src 'self'; style-src 'self'; img-src 'self'; " +
+	"connect-src 'self'; base-uri 'none'; form-action 'none'; frame-ancestors 'none'"
+
+// Number of race control messages shown, newest first
+const timingMaxMessages = 19
+
+var (
+	//go:embed timing.html
+	timingPageSource string
+	//go:embed timingData.html
+	timingDataSource string
+	//go:embed timing.css
+	timingStyle []byte
+	//go:embed timing.js
+	timingScript []byte
+
+	timingPage = template.Must(template.New("timing").Parse(timingPageSource))
+	timingData = template.Must(template.New("timingData").Parse(timingDataSource))
+)
+
+type timingCell struct {
+	Value string
+	Class string
+}
+
+type timingFlag struct {
+	Glyph string
+	Class string
+}
+
+type timingRow struct {
+	Position string
+	Name     string
+	Color    string
+	// Empty, dropzone or out for qualifying
+	Class string
+	// Segment classes grouped by sector
+	Segments [][]string
+
+	Fastest     timingCell
+	GapLeader   string
+	GapInterval string
+	Sectors     [3]timingCell
+	LastLap     timingCell
+	Overtake    timingCell
+	Tire        timingCell
+	LapsOnTire  string
+	Pitstops    string
+	SpeedTrap   timingCell
+	Location    timingCell
+	Chequered   bool
+}
+
+type timingMessage struct {
+	Time    string
+	Flags   []timingFlag
+	Message string
+}
+
+// timingView is everything shown on the timing page, the values are already formatted and the colors are CSS classes
+// so the theme picks the actual colors
+type timingView struct {
+	Session   string
+	EventType string
+	TrackTime string
+	Status    timingCell
+	TrackFlag string
+	Remaining string
+	Countdown string
+
+	Race          bool
+	Lap           string
+	SafetyCar     timingCell
+	OvertakeAid   string
+	OvertakeState string
+	// Interval when the gap to the car in front is the default
+	DefaultGap string
+
+	Rows        []timingRow
+	TrackStatus [][]string
+	BestSectors [3]string
+	Theoretical string
+	TopSpeed    string
+
+	Messages  []timingMessage
+	AirTemp   string
+	TrackTemp string
+	Raining   bool
+}
+
+func (w *WebTiming) addTimingRoutes(router *mux.Router) {
+	router.HandleFunc("/", func(writer http.ResponseWriter, r *http.Request) {
+		charts := make([]string, len(chartPanels))
+		for x, chart := range chartPanels {
+			charts[x] = chart.String()
+		}
+
+		var page bytes.Buffer
+		if err := timingPage.Execute(&page, charts); err != nil {
+			http.Error(writer, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeTimingResponse(writer, "text/html; charset=utf-8", page.Bytes())
+	})
+
+	router.HandleFunc("/data", func(writer http.ResponseWriter, r *http.Request) {
+		writeTimingResponse(writer, "text/html; charset=utf-8", []byte(w.html))
+	})
+
+	router.HandleFunc("/static/timing.css", func(writer http.ResponseWriter, r *http.Request) {
+		writeTimingResponse(writer, "text/css; charset=utf-8", timingStyle)
+	})
+
+	router.HandleFunc("/static/timing.js", func(writer http.ResponseWriter, r *http.Request) {
+		writeTimingResponse(writer, "text/javascript; charset=utf-8", timingScript)
+	})
+}
+
+func writeTimingResponse(writer http.ResponseWriter, contentType string, content []byte) {
+	writer.Header().Set("Content-Type", contentType)
+	writer.Header().Set("Content-Security-Policy", timingContentSecurityPolicy)
+	writer.Header().Set("X-Content-Type-Options", "nosniff")
+	writer.Header().Set("Referrer-Policy", "no-referrer")
+	writer.Write(content)
+}
+
+func (w *WebTiming) updateHTML() {
+	// If no data connection do nothing
+	if w.dataSrc == nil {
+		w.html = `<p class="message">No data source selected.</p>`
+		return
+	}
+
+	var content bytes.Buffer
+	if err := timingData.Execute(&content, w.timingView()); err != nil {
+		w.html = `<p class="message">` + template.HTMLEscapeString(err.Error()) + `</p>`
+		return
+	}
+	w.html = content.String()
+}
+
+func (w *WebTiming) timingView() timingView {
+	w.eventLock.Lock()
+	event := w.event
+	eventTime := w.eventTime
+	remaining := w.remainingTime
+	w.eventLock.Unlock()
+
+	view := timingView{
+		Session:       w.dataSrc.Name(),
+		EventType:     event.Type.String(),
+		TrackTime:     eventTime.In(w.dataSrc.CircuitTimezone()).Format("2006-01-02 15:04:05"),
+		Status:        timingCell{Value: event.Status.String(), Class: sessionStatusClass(event.Status)},
+		TrackFlag:     flagClass(event.TrackStatus),
+		Remaining:     outputFormat.Clock(remaining),
+		Race:          w.raceSession,
+		Lap:           fmt.Sprintf("%d/%d", event.CurrentLap, event.TotalLaps),
+		SafetyCar:     timingCell{Value: event.SafetyCar.String(), Class: safetyCarClass(event.SafetyCar)},
+		OvertakeAid:   w.overtakeAid.String(),
+		OvertakeState: w.overtakeAid.EnabledState(event),
+		DefaultGap:    "leader",
+		TrackStatus: sectorGroups(event, func(x int) string {
+			return flagClass(event.SegmentFlags[x])
+		}),
+		BestSectors: [3]string{fmtDuration(w.fastestSector1), fmtDuration(w.fastestSector2), fmtDuration(w.fastestSector3)},
+		Theoretical: fmtDuration(w.theoreticalFastestLap),
+	}
+	if w.gapToInfront {
+		view.DefaultGap = "interval"
+	}
+	if w.fastestSpeedTrap > 0 {
+		view.TopSpeed = strconv.Itoa(w.fastestSpeedTrap)
+	}
+
+	// If it is a race and the session hasn't started yet (remaining time count down hasn't started) then
+	// display a count down to the start of the session
+	if w.raceSession && event.Status == Messages.UnknownState {
+		view.Countdown = fmtCountdown(w.dataSrc.SessionStart().Sub(eventTime))
+	}
+
+	drivers := w.sortedDrivers()
+	qualifying := regulations.QualifyingRules(w.dataSrc.SessionStart().Year(), w.qualifying, len(drivers))
+	qualifyingSegment := qualifying.Segment(event.Type)
+
+	for x, driver := range drivers {
+		row := timingRow{
+			Position:  strconv.Itoa(driver.Position),
+			Name:      driver.ShortName,
+			Color:     driver.HexColor,
+			Fastest:   timingCell{Value: fmtDuration(driver.FastestLap), Class: fastestLapClass(driver.OverallFastestLap)},
+			Location:  timingCell{Value: driver.Location.String(), Class: locationClass(driver.Location)},
+			Chequered: driver.ChequeredFlag,
+		}
+
+		switch {
+		case w.raceSession && driver.Location == Messages.Stopped:
+			// Only the fastest lap and location are relevant once stopped
+		case !w.raceSession && driver.KnockedOutOfQualifying:
+			row.Class = "out"
+			row.Fastest.Class = ""
+			row.Location = timingCell{Value: "Out"}
+			row.Chequered = false
+		default:
+			if !w.raceSession && qualifying.InDropZone(qualifyingSegment, x+1) {
+				row.Class = "dropzone"
+			}
+			w.addTimingDetails(&row, event, driver)
+		}
+
+		view.Rows = append(view.Rows, row)
+	}
+
+	w.rcMessagesLock.Lock()
+	for x := len(w.rcMessages) - 1; x >= 0 && x >= len(w.rcMessages)-timingMaxMessages; x-- {
+		msg := w.rcMessages[x]
+		view.Messages = append(view.Messages, timingMessage{
+			Time:    msg.Timestamp.In(w.dataSrc.CircuitTimezone()).Format("02-01-2006 15:04:05"),
+			Flags:   messageFlags(msg),
+			Message: msg.Msg,
+		})
+	}
+	w.rcMessagesLock.Unlock()
+
+	w.weatherLock.Lock()
+	view.AirTemp = fmt.Sprintf("%.1f°C", w.weather.AirTemp)
+	view.TrackTemp = fmt.Sprintf("%.1f°C", w.weather.TrackTemp)
+	view.Raining = w.weather.Rainfall
+	w.weatherLock.Unlock()
+
+	return view
+}
+
+// addTimingDetails fills in the columns for a driver that is still running
+func (w *WebTiming) addTimingDetails(row *timingRow, event Messages.Event, driver Messages.Timing) {
+	row.Segments = sectorGroups(event, func(x int) string {
+		return segmentClass(driver.Segment[x])
+	})
+
+	row.GapInterval = fmtDuration(driver.TimeDiffToPositionAhead)
+	if w.raceSession {
+		row.GapLeader = fmtDuration(driver.GapToLeader)
+	} else {
+		row.GapLeader = fmtDuration(driver.TimeDiffToFastest)
+	}
+
+	row.Sectors = [3]timingCell{
+		{Value: fmtDuration(driver.Sector1), Class: timeClass(driver.Sector1PersonalFastest, driver.Sector1OverallFastest)},
+		{Value: fmtDuration(driver.Sector2), Class: timeClass(driver.Sector2PersonalFastest, driver.Sector2OverallFastest)},
+		{Value: fmtDuration(driver.Sector3), Class: timeClass(driver.Sector3PersonalFastest, driver.Sector3OverallFastest)},
+	}
+	row.LastLap = timingCell{Value: fmtDuration(driver.LastLap), Class: timeClass(driver.LastLapPersonalFastest, driver.LastLapOverallFastest)}
+	row.Tire = timingCell{Value: driver.Tire.String(), Class: tireClass(driver.Tire)}
+	row.LapsOnTire = strconv.Itoa(driver.LapsOnTire)
+	row.Pitstops = strconv.Itoa(driver.Pitstops)
+	if driver.SpeedTrap > 0 {
+		row.SpeedTrap = timingCell{
+			Value: strconv.Itoa(driver.SpeedTrap),
+			Class: timeClass(driver.SpeedTrapPersonalFastest, driver.SpeedTrapOverallFastest),
+		}
+	}
+
+	// Only the DRS state is sent so for overtake mode show if the driver can use it
+	if w.overtakeAid.ReportsState() {
+		row.Overtake.Value = "Closed"
+		if driver.DRSOpen {
+			row.Overtake.Value = "Open"
+		}
+	}
+	if w.overtakeAid.Eligible(event, driver.TimeDiffToPositionAhead) {
+		row.Overtake.Class = "good"
+		if !w.overtakeAid.ReportsState() {
+			row.Overtake.Value = "Eligible"
+		}
+	}
+}
+
+// sectorGroups returns the class for each segment of the track grouped by sector
+func sectorGroups(event Messages.Event, class func(x int) string) [][]string {
+	count := min(event.TotalSegments, Messages.MaxSegments)
+	if count == 0 {
+		return nil
+	}
+
+	groups := [][]string{{}}
+	for x := 0; x < count; x++ {
+		groups[len(groups)-1] = append(groups[len(groups)-1], class(x))
+		if (x == event.Sector1Segments-1 || x == event.Sector1Segments+event.Sector2Segments-1) && x != count-1 {
+			groups = append(groups, []string{})
+		}
+	}
+	return groups
+}
+
+func messageFlags(msg Messages.RaceControlMessage) []timingFlag {
+	flag := timingFlag{Glyph: "⚑", Class: flagClass(msg.Flag)}
+
+	switch msg.Flag {
+	case Messages.ChequeredFlag:
+		return []timingFlag{{Glyph: "🏁"}}
+	case Messages.GreenFlag, Messages.RedFlag:
+		// Pit exit lights instead of flags
+		if strings.HasPrefix(msg.Msg, "GREEN LIGHT") || strings.HasPrefix(msg.Msg, "RED LIGHT") {
+			flag.Glyph = "⬤"
+		}
+		return []timingFlag{flag}
+	case Messages.YellowFlag, Messages.BlueFlag:
+		return []timingFlag{flag}
+	case Messages.DoubleYellowFlag:
+		return []timingFlag{flag, flag}
+	case Messages.BlackAndWhite:
+		return []timingFlag{{Glyph: "⚑", Class: "flag-black"}, {Glyph: "⚑", Class: "flag-white"}}
+	default:
+		return nil
+	}
+}
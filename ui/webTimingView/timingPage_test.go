@@ -0,0 +1,35 @@
+package webTimingView
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestTimingDataEscaped(t *testing.T) {
+	const injected = `<script>alert("xss")</script>`
+
+	view := timingView{
+		Session: "British Grand Prix",
+		Race:    true,
+		Rows: []timingRow{
+			{Position: "1", Name: injected, Color: `"><script>alert(1)</script>`, Class: "out"},
+		},
+		Messages: []timingMessage{
+			{Time: "07-07-2024 15:03:12", Message: "CAR 1 (VER) " + injected},
+		},
+	}
+
+	var content bytes.Buffer
+	if err := timingData.Execute(&content, view); err != nil {
+		t.Fatal(err)
+	}
+
+	html := content.String()
+	if strings.Contains(html, "<script") {
+		t.Errorf("expected the driver name, color and message to be escaped, got %s", html)
+	}
+	if strings.Count(html, "&lt;script&gt;alert(&#34;xss&#34;)&lt;/script&gt;") != 2 {
+		t.Errorf("expected the escaped name and message in the page, got %s", html)
+	}
+}
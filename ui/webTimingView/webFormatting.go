@@ -17,7 +17,6 @@ package webTimingView
 
 import (
 	"fmt"
-	"github.com/charmbracelet/lipgloss"
 	"github.com/f1gopher/f1gopherlib/Messages"
 	"time"
 )
@@ -52,9 +51,9 @@ func fmtDuration(d time.Duration) string {
 		return fmt.Sprintf("-%02d:%02d.%03d", minutes, seconds, milliseconds)
 	}
 
-	// If no minutes then don't display zero but pad with spaces for display alignment
+	// If no minutes then don't display zero
 	if minutes == 0 {
-		return fmt.Sprintf("   %02d.%03d", seconds, milliseconds)
+		return fmt.Sprintf("%02d.%03d", seconds, milliseconds)
 	}
 
 	return fmt.Sprintf("%02d:%02d.%03d", minutes, seconds, milliseconds)
@@ -82,46 +81,44 @@ func fmtCountdown(d time.Duration) string {
 	return fmt.Sprintf("%02d:%02d", minutes, seconds)
 }
 
-func timeColor(personalFastest bool, overallFastest bool) string {
+// The timing page colors are CSS classes so the theme decides the actual color
 
+func timeClass(personalFastest bool, overallFastest bool) string {
 	if overallFastest {
-		return "#D500D5"
+		return "overall"
 	} else if personalFastest {
-		return "#00FF00"
+		return "good"
 	} else {
-		return "#FFFF00"
+		return "warn"
 	}
 }
 
-func segmentColor(segmentType Messages.SegmentType) lipgloss.Color {
+func segmentClass(segmentType Messages.SegmentType) string {
 	switch segmentType {
+	case Messages.None:
+		return "none"
 	case Messages.YellowSegment:
-		return "#FFFF00"
+		return "warn"
 	case Messages.GreenSegment:
-		return "#00FF00"
+		return "good"
 	case Messages.InvalidSegment:
-		return "#000000"
+		return "invalid"
 	case Messages.PurpleSegment:
-		return "#D500D5"
+		return "overall"
 	case Messages.RedSegment:
-		return "#FF0000"
-	case Messages.PitlaneSegment:
-		return "#FFFFFF"
+		return "bad"
 	case Messages.Mystery, Messages.Mystery2, Messages.Mystery3:
-		return "#0000FF"
+		return "blue"
 	default:
-		return "#FFFFFF"
+		return "plain"
 	}
 }
 
-func fastestLapColor(overallFastest bool) string {
-
+func fastestLapClass(overallFastest bool) string {
 	if overallFastest {
-		return "#D500D5"
-	} else {
-		// TODO - use default text color
-		return "#B4B0B0"
+		return "overall"
 	}
+	return "muted"
 }
 
 func tireColor(tire Messages.TireType) string {
@@ -141,59 +138,93 @@ func tireColor(tire Messages.TireType) string {
 	}
 }
 
-func trackStatusColor(state Messages.FlagState) string {
+func tireClass(tire Messages.TireType) string {
+	switch tire {
+	case Messages.Soft:
+		return "tire-soft"
+	case Messages.Medium:
+		return "tire-medium"
+	case Messages.Hard:
+		return "tire-hard"
+	case Messages.Intermediate:
+		return "tire-inter"
+	case Messages.Wet:
+		return "tire-wet"
+	default:
+		return "tire-other"
+	}
+}
+
+// flagClass is used for the track status, the segment flags and race control messages
+func flagClass(state Messages.FlagState) string {
 	switch state {
 	case Messages.GreenFlag:
-		return "#00FF00"
+		return "good"
 	case Messages.YellowFlag, Messages.DoubleYellowFlag:
-		return "#FFFF00"
+		return "warn"
 	case Messages.RedFlag:
-		return "#FF0000"
+		return "bad"
+	case Messages.BlueFlag:
+		return "blue"
 	case Messages.ChequeredFlag:
-		return "#FFFFFF"
-	case Messages.NoFlag:
-		return ""
+		return "plain"
 	default:
-		panic("Unhandled track status color: " + state.String())
+		return "none"
 	}
 }
 
-func sessionStatusColor(state Messages.SessionState) string {
+func sessionStatusClass(state Messages.SessionState) string {
 	switch state {
-	case Messages.UnknownState, Messages.Inactive, Messages.Finished, Messages.Finalised, Messages.Ended:
-		return "#FFFFFF"
 	case Messages.Started:
-		return "#00FF00"
+		return "good"
 	case Messages.Aborted:
-		return "#FF0000"
+		return "bad"
 	default:
-		panic("Unhandled session status color: " + state.String())
+		return "plain"
 	}
 }
 
-func safetyCarFormat(state Messages.TrackState) string {
-
-	var color = "#00FF00"
-
+func safetyCarClass(state Messages.TrackState) string {
 	switch state {
 	case Messages.VirtualSafetyCar, Messages.VirtualSafetyCarEnding:
-		color = "#FFFF00"
+		return "warn"
 	case Messages.SafetyCar, Messages.SafetyCarEnding:
-		color = "#FF0000"
+		return "bad"
+	default:
+		return "good"
 	}
-
-	return color
 }
 
-func locationColor(location Messages.CarLocation) string {
+func locationClass(location Messages.CarLocation) string {
 	switch location {
-	case Messages.Pitlane, Messages.PitOut, Messages.NoLocation:
-		return "#FFFFFF"
 	case Messages.OnTrack, Messages.OutLap:
-		return "#00FF00"
+		return "good"
 	case Messages.Stopped, Messages.OutOfRace:
+		return "bad"
+	default:
+		return "plain"
+	}
+}
+
+// fmtOverlayGap returns the gap in seconds, empty when there isn't one
+func fmtOverlayGap(gap time.Duration) string {
+	if gap <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("+%.3f", gap.Seconds())
+}
+
+func rcmFlagColor(flag Messages.FlagState) string {
+	switch flag {
+	case Messages.GreenFlag:
+		return "#00FF00"
+	case Messages.YellowFlag, Messages.DoubleYellowFlag:
+		return "#FFFF00"
+	case Messages.RedFlag:
 		return "#FF0000"
+	case Messages.BlueFlag:
+		return "#4D8BFF"
 	default:
-		panic("Unhandled location color: " + location.String())
+		return ""
 	}
 }
@@ -17,23 +17,19 @@ package webTimingView
 
 import (
 	"context"
+	"f1gopher/regulations"
+	"f1gopher/remoteControl"
 	"f1gopher/ui/panel"
-	"fmt"
 	"net/http"
-	"sort"
-	"strings"
 	"sync"
 	"time"
 
 	"github.com/AllenDang/giu"
-	"github.com/charmbracelet/lipgloss"
 	"github.com/f1gopher/f1gopherlib"
 	"github.com/f1gopher/f1gopherlib/Messages"
 	"github.com/gorilla/mux"
 )
 
-const timeWidth = 11
-
 type WebTiming struct {
 	shutdownWg   *sync.WaitGroup
 	ctx          context.Context
@@ -70,14 +66,26 @@ type WebTiming struct {
 
 	gapToInfront bool
 	raceSession  bool
+	overtakeAid  regulations.OvertakeAid
+	qualifying   regulations.QualifyingType
 
 	html string
+
+	// Pushed to the streaming overlay pages when they change
+	overlayFeeds map[string]*overlayFeed
+	overlayLock  sync.Mutex
+	battles      BattleSource
+
+	remote *remoteControl.Handler
+	charts ChartSource
 }
 
 func CreateWebTimingView(
 	shutdownWg *sync.WaitGroup,
 	ctx context.Context,
-	servers []string) *WebTiming {
+	servers []string,
+	remote remoteControl.Controller,
+	charts ChartSource) *WebTiming {
 
 	web := WebTiming{
 		shutdownWg:   shutdownWg,
@@ -86,7 +94,14 @@ func CreateWebTimingView(
 		data:         map[int]Messages.Timing{},
 		started:      false,
 		stopTickChan: make(chan bool),
+		overlayFeeds: map[string]*overlayFeed{},
+		charts:       charts,
 	}
+
+	for _, current := range overlays {
+		web.overlayFeeds[current.name] = &overlayFeed{subscribers: map[chan []byte]bool{}}
+	}
+	web.remote = remoteControl.NewHandler(remote, web.remoteDrivers)
 	return &web
 }
 
@@ -120,6 +135,7 @@ func (w *WebTiming) Start() {
 				return
 			case <-w.redrawTicker.C:
 				w.updateHTML()
+				w.updateOverlays()
 			case <-w.stopTickChan:
 				return
 			}
@@ -153,10 +169,33 @@ func (w *WebTiming) Stop() {
 	w.started = false
 }
 
+// SetRemoteControlToken sets the token the remote control requests need, empty disables them
+func (w *WebTiming) SetRemoteControlToken(token string) {
+	w.remote.SetToken(token)
+}
+
+// SetBattles sets where the battle overlay gets the battles from, the window's view sets it when it starts a session
+func (w *WebTiming) SetBattles(battles BattleSource) {
+	w.overlayLock.Lock()
+	w.battles = battles
+	w.overlayLock.Unlock()
+}
+
 func (w *WebTiming) Init(dataSrc f1gopherlib.F1GopherLib, config panel.PanelConfig) {
 	w.dataSrc = dataSrc
+
+	// Clear previous session data
+	w.dataLock.Lock()
+	w.data = map[int]Messages.Timing{}
+	w.dataLock.Unlock()
+	w.rcMessagesLock.Lock()
+	w.rcMessages = nil
+	w.rcMessagesLock.Unlock()
+
 	w.raceSession = dataSrc.Session() == Messages.RaceSession || dataSrc.Session() == Messages.SprintSession
 	w.gapToInfront = w.raceSession
+	w.overtakeAid = regulations.OvertakeAidForSeason(dataSrc.SessionStart().Year())
+	w.qualifying = regulations.QualifyingSessionType(dataSrc.SessionStart())
 }
 
 func (w *WebTiming) ProcessTiming(data Messages.Timing) {
@@ -166,8 +205,10 @@ func (w *WebTiming) ProcessTiming(data Messages.Timing) {
 }
 
 func (w *WebTiming) ProcessEventTime(data Messages.EventTime) {
+	w.eventLock.Lock()
 	w.eventTime = data.Timestamp
 	w.remainingTime = data.Remaining
+	w.eventLock.Unlock()
 }
 
 func (w *WebTiming) ProcessEvent(data Messages.Event) {
@@ -194,39 +235,12 @@ func (w *WebTiming) Draw(width int, height int) (widgets []giu.Widget) {
 
 func (w *WebTiming) runWebServer() {
 	router := mux.NewRouter()
-	router.HandleFunc("/", func(writer http.ResponseWriter, r *http.Request) {
-		abc := `<html>
-<title>F1Gopher</title>
-<head>
-	<meta charset="utf-8">
-</head>
-<script language="javascript">
-	async function subscribe() {
-  		let response = await fetch("/data");
-
-		let message = await response.text();
-		document.getElementById("display").innerHTML = message;
-
-		await new Promise(resolve => setTimeout(resolve, 1000));
-		await subscribe();
-  	}
-
-	subscribe();
-
-</script>
-<body style="background-color:black; color:white">
-	<div>
-		<pre id="display"></pre>
-	</div>
-</body>
-</html>`
-
-		writer.Write([]byte(abc))
-	})
-
-	router.HandleFunc("/data", func(writer http.ResponseWriter, r *http.Request) {
-		writer.Write([]byte(w.html))
-	})
+	w.addTimingRoutes(router)
+	w.addOverlayRoutes(router)
+	w.addChartRoutes(router)
+
+	router.Handle("/remote", w.remote)
+	router.PathPrefix("/api/").Handler(w.remote)
 
 	for x := range w.servers {
 
@@ -243,372 +257,16 @@ func (w *WebTiming) runWebServer() {
 	}
 }
 
-func (w *WebTiming) updateHTML() {
-	// If no data connection do nothing
-	if w.dataSrc == nil {
-		w.html = "No data source selected."
-		return
-	}
-
-	drivers := make([]Messages.Timing, 0)
-	w.dataLock.Lock()
-	for _, a := range w.data {
-		drivers = append(drivers, a)
-	}
-	w.dataLock.Unlock()
-
-	// Sort drivers into their position order
-	sort.Slice(drivers, func(i, j int) bool {
-		return drivers[i].Position < drivers[j].Position
-	})
-
-	hour := int(w.remainingTime.Seconds() / 3600)
-	minute := int(w.remainingTime.Seconds()/60) % 60
-	second := int(w.remainingTime.Seconds()) % 60
-	remaining := fmt.Sprintf("%d:%02d:%02d", hour, minute, second)
-	segmentCount := w.event.TotalSegments
-	if segmentCount == 0 {
-		segmentCount = len("Segment")
-	}
-
-	var table, separator string
-	if w.raceSession {
-		table, separator = w.raceDisplay(segmentCount, remaining, drivers)
-	} else {
-		table, separator = w.practiceQualiDisplay(segmentCount, remaining, drivers)
-	}
-
-	table += separator + "\n"
-	trackStatus := "Track Status: |"
-	for x := 0; x < segmentCount; x++ {
-		switch w.event.SegmentFlags[x] {
-		case Messages.GreenFlag:
-			trackStatus += "<font color=\"#00FF00\">&#x25a0;</font>"
-		case Messages.YellowFlag:
-			trackStatus += "<font color=\"#FFFF00\">&#x25a0;</font>"
-		case Messages.DoubleYellowFlag:
-			trackStatus += "<font color=\"#FBFF00\">&#x25a0;</font>"
-		case Messages.RedFlag:
-			trackStatus += "<font color=\"#FF0000\">&#x25a0;</font>"
-		}
-		if x == w.event.Sector1Segments-1 || x == w.event.Sector1Segments+w.event.Sector2Segments-1 {
-			trackStatus += "|"
-		}
-	}
-	if w.dataSrc.Session() == Messages.RaceSession || w.dataSrc.Session() == Messages.SprintSession {
-		trackStatus += fmt.Sprintf("|                   |%s|%s|%s|%s|                        |%s|",
-			fmt.Sprintf("<font color=\"#D500D5\">%s</font>", lipgloss.NewStyle().Align(lipgloss.Center).Width(timeWidth-2).Render(fmtDuration(w.fastestSector1))),
-			fmt.Sprintf("<font color=\"#D500D5\">%s</font>", lipgloss.NewStyle().Align(lipgloss.Center).Width(timeWidth-2).Render(fmtDuration(w.fastestSector2))),
-			fmt.Sprintf("<font color=\"#D500D5\">%s</font>", lipgloss.NewStyle().Align(lipgloss.Center).Width(timeWidth-2).Render(fmtDuration(w.fastestSector3))),
-			fmt.Sprintf("<font color=\"#D500D5\">%s</font>", lipgloss.NewStyle().Align(lipgloss.Center).Width(timeWidth-2).Render(fmtDuration(w.theoreticalFastestLap))),
-			fmt.Sprintf("<font color=\"#D500D5\">%s</font>", lipgloss.NewStyle().Align(lipgloss.Center).Width(5).Render(fmt.Sprintf("%d", w.fastestSpeedTrap))))
-	} else {
-		trackStatus += fmt.Sprintf("|                       |%s|%s|%s|%s|                |%s|",
-			fmt.Sprintf("<font color=\"#D500D5\">%s</font>", lipgloss.NewStyle().Align(lipgloss.Center).Width(timeWidth).Padding(0, 1, 0, 1).Render(fmtDuration(w.fastestSector1))),
-			fmt.Sprintf("<font color=\"#D500D5\">%s</font>", lipgloss.NewStyle().Align(lipgloss.Center).Width(timeWidth).Padding(0, 1, 0, 1).Render(fmtDuration(w.fastestSector2))),
-			fmt.Sprintf("<font color=\"#D500D5\">%s</font>", lipgloss.NewStyle().Align(lipgloss.Center).Width(timeWidth).Padding(0, 1, 0, 1).Render(fmtDuration(w.fastestSector3))),
-			fmt.Sprintf("<font color=\"#D500D5\">%s</font>", lipgloss.NewStyle().Align(lipgloss.Center).Width(timeWidth).Padding(0, 1, 0, 1).Render(fmtDuration(w.theoreticalFastestLap))),
-			fmt.Sprintf("<font color=\"#D500D5\">%s</font>", lipgloss.NewStyle().Align(lipgloss.Center).Width(5).Render(fmt.Sprintf("%d", w.fastestSpeedTrap))))
-	}
-
-	table += trackStatus + "\n"
-
-	table += separator + "\n"
-	w.rcMessagesLock.Lock()
-	if len(w.rcMessages) > 0 {
-		for x := len(w.rcMessages) - 1; x >= 0 && x >= len(w.rcMessages)-19; x-- {
-			lastMessage := w.rcMessages[x]
-			prefix := ""
-
-			switch lastMessage.Flag {
-			case Messages.ChequeredFlag:
-				prefix = "🏁 "
-			case Messages.GreenFlag:
-				if strings.HasPrefix(lastMessage.Msg, "GREEN LIGHT") {
-					prefix = "<font color=\"#00FF00\">&#11044; </font>"
-				} else {
-					prefix = "<font color=\"#00FF00\">&#x2691; </font>"
-				}
-			case Messages.YellowFlag:
-				prefix = "<font color=\"#FFFF00\">&#x2691; </font>"
-			case Messages.DoubleYellowFlag:
-				prefix = "<font color=\"#FFFF00\">&#x2691;&#x2691; </font>"
-			case Messages.BlueFlag:
-				prefix = "<font color=\"#0000FF\">&#x2691; </font>"
-			case Messages.RedFlag:
-				if strings.HasPrefix(lastMessage.Msg, "RED LIGHT") {
-					prefix = "<font color=\"#FF0000\">&#11044; </font>"
-				} else {
-					prefix = "<font color=\"#FF0000\">&#x2691; </font>"
-				}
-			case Messages.BlackAndWhite:
-				prefix = "<font color=\"#000000\">&#x2691;</font>" + "<font color=\"#FFFFFF\">&#x2691; </font>"
-			}
-
-			table += fmt.Sprintf("%s - %s%s\n", lastMessage.Timestamp.In(w.dataSrc.CircuitTimezone()).Format("02-01-2006 15:04:05"), prefix, lastMessage.Msg)
-		}
-	}
-	w.rcMessagesLock.Unlock()
-
-	status := separator + "\n"
-
-	w.weatherLock.Lock()
-	status += fmt.Sprintf("Air Temp: %.2f°C, Track Temp: %.2f°C", w.weather.AirTemp, w.weather.TrackTemp)
-	if w.weather.Rainfall {
-		status += ", <font color=\"#009DD3\">Raining</font>"
-	}
-	w.weatherLock.Unlock()
-
-	// If it is a race and the session hasn't started yet (remaining time count down hasn't started) then
-	// display a count down to the start of the session
-	if (w.dataSrc.Session() == Messages.RaceSession || w.dataSrc.Session() == Messages.SprintSession) && w.event.Status == Messages.UnknownState {
-		status += fmt.Sprintf(", <font color=\"#00FF00\">Session Starts in: %s</font>", fmtCountdown(w.dataSrc.SessionStart().Sub(w.eventTime)))
-	}
-
-	table += status
-
-	w.html = table
-}
-
-func (w *WebTiming) practiceQualiDisplay(segmentCount int, remaining string, v []Messages.Timing) (table string, separator string) {
-
-	separator = "------------------------------------------------------------------------------------------------------------------------------------------------------"
-
-	title := fmt.Sprintf("%s: %v, Track Time: %v, Status: %s, DRS: %s, Remaining: %s %s\n",
-		w.dataSrc.Name(),
-		w.event.Type.String(),
-		w.eventTime.In(w.dataSrc.CircuitTimezone()).Format("2006-01-02 15:04:05"),
-		fmt.Sprintf("<font color=\"%s\">%s</font>", sessionStatusColor(w.event.Status), w.event.Status.String()),
-		w.event.DRSEnabled.String(),
-		remaining,
-		fmt.Sprintf("<font color=\"%s\">&#x2691</font>", trackStatusColor(w.event.TrackStatus)))
-
-	header := fmt.Sprintf("%s|%s|%s|%s|%s|%s|%s|%s|%s|%s|%s|%s|%s",
-		lipgloss.NewStyle().Align(lipgloss.Center).Width(5).Padding(0, 1, 0, 1).Render("Pos"),
-		lipgloss.NewStyle().Align(lipgloss.Center).Width(8).Padding(0, 1, 0, 1).Render("Driver"),
-		lipgloss.NewStyle().Align(lipgloss.Center).Width(segmentCount+2).Padding(0, 1, 0, 1).Render("Segment"),
-		lipgloss.NewStyle().Align(lipgloss.Center).Width(timeWidth).Padding(0, 1, 0, 1).Render("Fastest"),
-		lipgloss.NewStyle().Align(lipgloss.Center).Width(timeWidth).Padding(0, 1, 0, 1).Render("Gap"),
-		lipgloss.NewStyle().Align(lipgloss.Center).Width(timeWidth).Padding(0, 1, 0, 1).Render("S1"),
-		lipgloss.NewStyle().Align(lipgloss.Center).Width(timeWidth).Padding(0, 1, 0, 1).Render("S2"),
-		lipgloss.NewStyle().Align(lipgloss.Center).Width(timeWidth).Padding(0, 1, 0, 1).Render("S3"),
-		lipgloss.NewStyle().Align(lipgloss.Center).Width(timeWidth).Padding(0, 1, 0, 1).Render("Last Lap"),
-		lipgloss.NewStyle().Align(lipgloss.Center).Width(10).Padding(0, 1, 0, 1).Render("Tire"),
-		lipgloss.NewStyle().Align(lipgloss.Center).Width(5).Padding(0, 1, 0, 1).Render("Lap"),
-		lipgloss.NewStyle().Align(lipgloss.Center).Width(5).Render("Speed"),
-		lipgloss.NewStyle().Align(lipgloss.Center).Width(10).Padding(0, 1, 0, 1).Render("Location"))
-
-	table = title + header + "\n" + separator + "\n"
-
-	outBackground := "#4545E4"
-	dropZoneBackground := "#53544E"
-
-	for x, driver := range v {
-		speedTrap := ""
-		if driver.SpeedTrap > 0 {
-			speedTrap = fmt.Sprintf("%d", driver.SpeedTrap)
-		}
-
-		gap := driver.TimeDiffToFastest
-		if w.gapToInfront {
-			gap = driver.TimeDiffToPositionAhead
-		}
-
-		segments := ""
-		for x := 0; x < segmentCount; x++ {
-			switch driver.Segment[x] {
-			case Messages.None:
-				segments += " "
-			default:
-				segments += fmt.Sprintf("<font color=\"%s\">&#x25a0;</font>", segmentColor(driver.Segment[x]))
-			}
-
-			if x == w.event.Sector1Segments-1 || x == w.event.Sector1Segments+w.event.Sector2Segments-1 {
-				segments += "|"
-			}
-		}
-
-		var row string
-		if !driver.KnockedOutOfQualifying {
-
-			if w.event.Type == Messages.Qualifying1 && x >= 15 ||
-				w.event.Type == Messages.Qualifying2 && x >= 10 {
-
-				row = fmt.Sprintf("<pr style=\"background-color: %s\">%s|%s|%s|%s|%s|%s|%s|%s|%s|%s|%s|%s|%s</pr>",
-					dropZoneBackground,
-					lipgloss.NewStyle().Align(lipgloss.Center).Width(5).Padding(0, 1, 0, 1).Render(fmt.Sprintf("%d", driver.Position)),
-					fmt.Sprintf("<font color=\"%s\">%s</font>", driver.HexColor, lipgloss.NewStyle().Align(lipgloss.Center).Width(8).Padding(0, 1, 0, 1).Render(driver.ShortName)),
-					segments,
-					lipgloss.NewStyle().Align(lipgloss.Center).Width(timeWidth).Padding(0, 1, 0, 1).Render(fmtDuration(driver.FastestLap)),
-					lipgloss.NewStyle().Align(lipgloss.Center).Width(timeWidth).Padding(0, 1, 0, 1).Render(fmtDuration(gap)),
-					fmt.Sprintf("<font color=\"%s\">%s</font>", timeColor(driver.Sector1PersonalFastest, driver.Sector1OverallFastest), lipgloss.NewStyle().Align(lipgloss.Center).Width(timeWidth).Padding(0, 1, 0, 1).Render(fmtDuration(driver.Sector1))),
-					fmt.Sprintf("<font color=\"%s\">%s</font>", timeColor(driver.Sector2PersonalFastest, driver.Sector2OverallFastest), lipgloss.NewStyle().Align(lipgloss.Center).Width(timeWidth).Padding(0, 1, 0, 1).Render(fmtDuration(driver.Sector2))),
-					fmt.Sprintf("<font color=\"%s\">%s</font>", timeColor(driver.Sector3PersonalFastest, driver.Sector3OverallFastest), lipgloss.NewStyle().Align(lipgloss.Center).Width(timeWidth).Padding(0, 1, 0, 1).Render(fmtDuration(driver.Sector3))),
-					fmt.Sprintf("<font color=\"%s\">%s</font>", timeColor(driver.LastLapPersonalFastest, driver.LastLapOverallFastest), lipgloss.NewStyle().Align(lipgloss.Center).Width(timeWidth).Padding(0, 1, 0, 1).Render(fmtDuration(driver.LastLap))),
-					fmt.Sprintf("<font color=\"%s\">%s</font>", tireColor(driver.Tire), lipgloss.NewStyle().Align(lipgloss.Center).Width(10).Padding(0, 1, 0, 1).Render(driver.Tire.String())),
-					lipgloss.NewStyle().Align(lipgloss.Center).Width(5).Padding(0, 1, 0, 1).Render(fmt.Sprintf("%d", driver.LapsOnTire)),
-					fmt.Sprintf("<font color=\"%s\">%s</font>", timeColor(driver.SpeedTrapPersonalFastest, driver.SpeedTrapOverallFastest), lipgloss.NewStyle().Align(lipgloss.Center).Width(5).Render(speedTrap)),
-					fmt.Sprintf("<font color=\"%s\">%s</font>", locationColor(driver.Location), lipgloss.NewStyle().Align(lipgloss.Center).Width(10).Padding(0, 1, 0, 1).Render(driver.Location.String())))
-
-			} else {
-				row = fmt.Sprintf("%s|%s|%s|%s|%s|%s|%s|%s|%s|%s|%s|%s|%s",
-					lipgloss.NewStyle().Align(lipgloss.Center).Width(5).Padding(0, 1, 0, 1).Render(fmt.Sprintf("%d", driver.Position)),
-					fmt.Sprintf("<font color=\"%s\">%s</font>", driver.HexColor, lipgloss.NewStyle().Align(lipgloss.Center).Width(8).Padding(0, 1, 0, 1).Render(driver.ShortName)),
-					segments,
-					lipgloss.NewStyle().Align(lipgloss.Center).Width(timeWidth).Padding(0, 1, 0, 1).Render(fmtDuration(driver.FastestLap)),
-					lipgloss.NewStyle().Align(lipgloss.Center).Width(timeWidth).Padding(0, 1, 0, 1).Render(fmtDuration(gap)),
-					fmt.Sprintf("<font color=\"%s\">%s</font>", timeColor(driver.Sector1PersonalFastest, driver.Sector1OverallFastest), lipgloss.NewStyle().Align(lipgloss.Center).Width(timeWidth).Padding(0, 1, 0, 1).Render(fmtDuration(driver.Sector1))),
-					fmt.Sprintf("<font color=\"%s\">%s</font>", timeColor(driver.Sector2PersonalFastest, driver.Sector2OverallFastest), lipgloss.NewStyle().Align(lipgloss.Center).Width(timeWidth).Padding(0, 1, 0, 1).Render(fmtDuration(driver.Sector2))),
-					fmt.Sprintf("<font color=\"%s\">%s</font>", timeColor(driver.Sector3PersonalFastest, driver.Sector3OverallFastest), lipgloss.NewStyle().Align(lipgloss.Center).Width(timeWidth).Padding(0, 1, 0, 1).Render(fmtDuration(driver.Sector3))),
-					fmt.Sprintf("<font color=\"%s\">%s</font>", timeColor(driver.LastLapPersonalFastest, driver.LastLapOverallFastest), lipgloss.NewStyle().Align(lipgloss.Center).Width(timeWidth).Padding(0, 1, 0, 1).Render(fmtDuration(driver.LastLap))),
-					fmt.Sprintf("<font color=\"%s\">%s</font>", tireColor(driver.Tire), lipgloss.NewStyle().Align(lipgloss.Center).Width(10).Padding(0, 1, 0, 1).Render(driver.Tire.String())),
-					lipgloss.NewStyle().Align(lipgloss.Center).Width(5).Padding(0, 1, 0, 1).Render(fmt.Sprintf("%d", driver.LapsOnTire)),
-					fmt.Sprintf("<font color=\"%s\">%s</font>", timeColor(driver.SpeedTrapPersonalFastest, driver.SpeedTrapOverallFastest), lipgloss.NewStyle().Align(lipgloss.Center).Width(5).Render(speedTrap)),
-					fmt.Sprintf("<font color=\"%s\">%s</font>", locationColor(driver.Location), lipgloss.NewStyle().Align(lipgloss.Center).Width(10).Padding(0, 1, 0, 1).Render(driver.Location.String())))
-			}
-
-			if driver.ChequeredFlag {
-				row = row + " 🏁"
-			}
-
-		} else {
-
-			row = fmt.Sprintf("<pr style=\"background-color: %s\">%s|%s|%s|%s|%s|%s|%s|%s|%s|%s|%s|%s|%s</pr>",
-				outBackground,
-				lipgloss.NewStyle().Align(lipgloss.Center).Width(5).Padding(0, 1, 0, 1).Render(fmt.Sprintf("%d", driver.Position)),
-				fmt.Sprintf("<font color=\"%s\">%s</font>", driver.Color, lipgloss.NewStyle().Align(lipgloss.Center).Width(8).Padding(0, 1, 0, 1).Render(driver.ShortName)),
-				lipgloss.NewStyle().Align(lipgloss.Left).Width(segmentCount+2).Render(""),
-				lipgloss.NewStyle().Align(lipgloss.Center).Width(timeWidth).Padding(0, 1, 0, 1).Render(fmtDuration(driver.FastestLap)),
-				lipgloss.NewStyle().Align(lipgloss.Center).Width(timeWidth).Padding(0, 1, 0, 1).Render(""),
-				lipgloss.NewStyle().Align(lipgloss.Center).Width(timeWidth).Padding(0, 1, 0, 1).Render(""),
-				lipgloss.NewStyle().Align(lipgloss.Center).Width(timeWidth).Padding(0, 1, 0, 1).Render(""),
-				lipgloss.NewStyle().Align(lipgloss.Center).Width(timeWidth).Padding(0, 1, 0, 1).Render(""),
-				lipgloss.NewStyle().Align(lipgloss.Center).Width(timeWidth).Padding(0, 1, 0, 1).Render(""),
-				lipgloss.NewStyle().Align(lipgloss.Center).Width(10).Padding(0, 1, 0, 1).Render(""),
-				lipgloss.NewStyle().Align(lipgloss.Center).Width(5).Padding(0, 1, 0, 1).Render(""),
-				lipgloss.NewStyle().Align(lipgloss.Center).Width(5).Render(""),
-				lipgloss.NewStyle().Align(lipgloss.Center).Width(10).Padding(0, 1, 0, 1).Render("Out"))
-		}
-
-		table += row + "\n"
-	}
-
-	return table, separator
-}
-
-func (w *WebTiming) raceDisplay(segmentCount int, remaining string, v []Messages.Timing) (table string, separator string) {
-
-	separator = "---------------------------------------------------------------------------------------------------------------------------------------------"
-
-	title := fmt.Sprintf("%s: %v, Track Time: %v, Status: %s, DRS: %v, Safety Car: %s, Lap: %d/%d, Remaining: %s %s\n",
-		w.dataSrc.Name(),
-		w.event.Type.String(),
-		w.eventTime.In(w.dataSrc.CircuitTimezone()).Format("2006-01-02 15:04:05"),
-		fmt.Sprintf("<font color=\"%s\">%s</font>", sessionStatusColor(w.event.Status), w.event.Status.String()),
-		w.event.DRSEnabled.String(),
-		fmt.Sprintf("<font color=\"%s\">%s</font>", safetyCarFormat(w.event.SafetyCar), w.event.SafetyCar),
-		w.event.CurrentLap,
-		w.event.TotalLaps,
-		remaining,
-		fmt.Sprintf("<font color=\"%s\">&#x2691</font>", trackStatusColor(w.event.TrackStatus)))
-
-	header := fmt.Sprintf("%s|%s|%s|%s|%s|%s|%s|%s|%s|%s|%s|%s|%s|%s|%s",
-		lipgloss.NewStyle().Align(lipgloss.Center).Width(5).Padding(0, 1, 0, 1).Render("Pos"),
-		lipgloss.NewStyle().Align(lipgloss.Center).Width(8).Padding(0, 1, 0, 1).Render("Driver"),
-		lipgloss.NewStyle().Align(lipgloss.Center).Width(segmentCount+2).Padding(0, 1, 0, 1).Render("Segment"),
-		lipgloss.NewStyle().Align(lipgloss.Center).Width(timeWidth-2).Render("Fastest"),
-		lipgloss.NewStyle().Align(lipgloss.Center).Width(timeWidth-2).Render("Gap"),
-		lipgloss.NewStyle().Align(lipgloss.Center).Width(timeWidth-2).Render("S1"),
-		lipgloss.NewStyle().Align(lipgloss.Center).Width(timeWidth-2).Render("S2"),
-		lipgloss.NewStyle().Align(lipgloss.Center).Width(timeWidth-2).Render("S3"),
-		lipgloss.NewStyle().Align(lipgloss.Center).Width(timeWidth-2).Render("Last Lap"),
-		lipgloss.NewStyle().Align(lipgloss.Center).Width(6).Render("DRS"),
-		lipgloss.NewStyle().Align(lipgloss.Center).Width(8).Render("Tire"),
-		lipgloss.NewStyle().Align(lipgloss.Center).Width(3).Render("Lap"),
-		lipgloss.NewStyle().Align(lipgloss.Center).Width(4).Render("Pits"),
-		lipgloss.NewStyle().Align(lipgloss.Center).Width(5).Render("Speed"),
-		lipgloss.NewStyle().Align(lipgloss.Center).Width(8).Render("Location"))
-
-	table = title + header + "\n" + separator + "\n"
-
-	for _, driver := range v {
-		if driver.Location == Messages.Stopped {
-			row := fmt.Sprintf("%s|%s|%s|%s|%s|%s|%s|%s|%s|%s|%s|%s|%s|%s|%s",
-				lipgloss.NewStyle().Align(lipgloss.Center).Width(5).Padding(0, 1, 0, 1).Render(fmt.Sprintf("%d", driver.Position)),
-				fmt.Sprintf("<font color=\"%s\">%s</font>", driver.HexColor, lipgloss.NewStyle().Align(lipgloss.Center).Width(8).Padding(0, 1, 0, 1).Render(driver.ShortName)),
-				lipgloss.NewStyle().Align(lipgloss.Left).Width(segmentCount+2).Render(""),
-				fmt.Sprintf("<font color=\"%s\">%s</font>", fastestLapColor(driver.OverallFastestLap), lipgloss.NewStyle().Align(lipgloss.Center).Width(timeWidth).Padding(0, 1, 0, 1).Render(fmtDuration(driver.FastestLap))),
-				lipgloss.NewStyle().Align(lipgloss.Center).Width(timeWidth-2).Render(""),
-				lipgloss.NewStyle().Align(lipgloss.Center).Width(timeWidth-2).Render(""),
-				lipgloss.NewStyle().Align(lipgloss.Center).Width(timeWidth-2).Render(""),
-				lipgloss.NewStyle().Align(lipgloss.Center).Width(timeWidth-2).Render(""),
-				lipgloss.NewStyle().Align(lipgloss.Center).Width(timeWidth-2).Render(""),
-				lipgloss.NewStyle().Align(lipgloss.Center).Width(6).Render(""),
-				lipgloss.NewStyle().Align(lipgloss.Center).Width(8).Render(""),
-				lipgloss.NewStyle().Align(lipgloss.Center).Width(3).Render(""),
-				lipgloss.NewStyle().Align(lipgloss.Center).Width(4).Render(""),
-				lipgloss.NewStyle().Align(lipgloss.Center).Width(5).Render(""),
-				fmt.Sprintf("<font color=\"%s\">%s</font>", locationColor(driver.Location), lipgloss.NewStyle().Align(lipgloss.Center).Width(8).Render(driver.Location.String())))
-			table += row + "\n"
-			continue
-		}
-
-		speedTrap := ""
-		if driver.SpeedTrap > 0 {
-			speedTrap = fmt.Sprintf("%d", driver.SpeedTrap)
-		}
-
-		gap := driver.GapToLeader
-		if w.gapToInfront {
-			gap = driver.TimeDiffToPositionAhead
-		}
-		drs := "Closed"
-		if driver.DRSOpen {
-			drs = "Open"
-		}
-
-		segments := ""
-		for x := 0; x < segmentCount; x++ {
-			switch driver.Segment[x] {
-			case Messages.None:
-				segments += " "
-			default:
-				segments += fmt.Sprintf("<font color=\"%s\">&#x25a0;</font>", segmentColor(driver.Segment[x]))
-			}
-
-			if x == w.event.Sector1Segments-1 || x == w.event.Sector1Segments+w.event.Sector2Segments-1 {
-				segments += "|"
-			}
-		}
-
-		drsColor := lipgloss.Color("#FFFFFF")
-		if driver.TimeDiffToPositionAhead > 0 && driver.TimeDiffToPositionAhead < time.Second {
-			drsColor = "#00FF00"
-		}
-
-		row := fmt.Sprintf("%s|%s|%s|%s|%s|%s|%s|%s|%s|%s|%s|%s|%s|%s|%s",
-			lipgloss.NewStyle().Align(lipgloss.Center).Width(5).Padding(0, 1, 0, 1).Render(fmt.Sprintf("%d", driver.Position)),
-			fmt.Sprintf("<font color=\"%s\">%s</font>", driver.HexColor, lipgloss.NewStyle().Align(lipgloss.Center).Width(8).Padding(0, 1, 0, 1).Render(driver.ShortName)),
-			segments,
-			fmt.Sprintf("<font color=\"%s\">%s</font>", fastestLapColor(driver.OverallFastestLap), lipgloss.NewStyle().Align(lipgloss.Center).Width(timeWidth-2).Render(fmtDuration(driver.FastestLap))),
-			lipgloss.NewStyle().Align(lipgloss.Center).Width(timeWidth-2).Render(fmtDuration(gap)),
-			fmt.Sprintf("<font color=\"%s\">%s</font>", timeColor(driver.Sector1PersonalFastest, driver.Sector1OverallFastest), lipgloss.NewStyle().Align(lipgloss.Center).Width(timeWidth-2).Render(fmtDuration(driver.Sector1))),
-			fmt.Sprintf("<font color=\"%s\">%s</font>", timeColor(driver.Sector2PersonalFastest, driver.Sector2OverallFastest), lipgloss.NewStyle().Align(lipgloss.Center).Width(timeWidth-2).Render(fmtDuration(driver.Sector2))),
-			fmt.Sprintf("<font color=\"%s\">%s</font>", timeColor(driver.Sector3PersonalFastest, driver.Sector3OverallFastest), lipgloss.NewStyle().Align(lipgloss.Center).Width(timeWidth-2).Render(fmtDuration(driver.Sector3))),
-			fmt.Sprintf("<font color=\"%s\">%s</font>", timeColor(driver.LastLapPersonalFastest, driver.LastLapOverallFastest), lipgloss.NewStyle().Align(lipgloss.Center).Width(timeWidth-2).Render(fmtDuration(driver.LastLap))),
-			fmt.Sprintf("<font color=\"%s\">%s</font>", drsColor, lipgloss.NewStyle().Align(lipgloss.Center).Width(6).Render(drs)),
-			fmt.Sprintf("<font color=\"%s\">%s</font>", tireColor(driver.Tire), lipgloss.NewStyle().Align(lipgloss.Center).Width(8).Render(driver.Tire.String())),
-			lipgloss.NewStyle().Align(lipgloss.Center).Width(3).Render(fmt.Sprintf("%d", driver.LapsOnTire)),
-			lipgloss.NewStyle().Align(lipgloss.Center).Width(4).Render(fmt.Sprintf("%d", driver.Pitstops)),
-			fmt.Sprintf("<font color=\"%s\">%s</font>", timeColor(driver.SpeedTrapPersonalFastest, driver.SpeedTrapOverallFastest), lipgloss.NewStyle().Align(lipgloss.Center).Width(5).Render(speedTrap)),
-			fmt.Sprintf("<font color=\"%s\">%s</font>", locationColor(driver.Location), lipgloss.NewStyle().Align(lipgloss.Center).Width(8).Render(driver.Location.String())))
-
-		if driver.ChequeredFlag {
-			row = row + " 🏁"
-		}
-
-		table += row + "\n"
+// remoteDrivers returns the drivers that can be focused by the remote control in position order
+func (w *WebTiming) remoteDrivers() []remoteControl.Driver {
+	drivers := []remoteControl.Driver{}
+	for _, driver := range w.sortedDrivers() {
+		drivers = append(drivers, remoteControl.Driver{
+			Number:   driver.Number,
+			Name:     driver.ShortName,
+			Color:    driver.HexColor,
+			Position: driver.Position,
+		})
 	}
-
-	return table, separator
+	return drivers
 }
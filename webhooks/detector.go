@@ -0,0 +1,165 @@
+// F1Gopher - Copyright (C) 2025 f1gopher
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package webhooks
+
+import (
+	"f1gopher/outputFormat"
+	"f1gopher/raceControl"
+	"time"
+
+	"github.com/f1gopher/f1gopherlib/Messages"
+)
+
+// detector turns the session data into payloads. Events are only sent when something changes so nothing is sent for
+// the first message of each type, which avoids sending the state of a session that was joined part way through.
+type detector struct {
+	session     string
+	raceSession bool
+
+	names      map[int]string
+	timing     map[int]Messages.Timing
+	event      *Messages.Event
+	leader     int
+	fastestLap time.Duration
+}
+
+func newDetector() *detector {
+	d := &detector{}
+	d.reset("", Messages.Practice1Session)
+	return d
+}
+
+func (d *detector) reset(session string, sessionType Messages.SessionType) {
+	d.session = session
+	d.raceSession = sessionType == Messages.RaceSession || sessionType == Messages.SprintSession
+	d.names = map[int]string{}
+	d.timing = map[int]Messages.Timing{}
+	d.event = nil
+	d.leader = 0
+	d.fastestLap = 0
+}
+
+func (d *detector) processDrivers(data Messages.Drivers) {
+	for _, driver := range data.Drivers {
+		d.names[driver.Number] = driver.ShortName
+	}
+}
+
+func (d *detector) processTiming(data Messages.Timing) []Payload {
+	previous, exists := d.timing[data.Number]
+	d.timing[data.Number] = data
+
+	payloads := []Payload{}
+
+	if exists && previous.Location != Messages.Pitlane && data.Location == Messages.Pitlane {
+		payload := d.driverPayload(PitStop, data)
+		payload.Lap = data.Lap
+		payloads = append(payloads, payload)
+	}
+
+	if data.OverallFastestLap && data.FastestLap > 0 && data.FastestLap != d.fastestLap {
+		// Only the first lap of the session can't be compared to anything
+		if exists && previous.FastestLap != data.FastestLap {
+			payload := d.driverPayload(FastestLap, data)
+			payload.Lap = data.Lap - 1
+			payload.LapTime = data.FastestLap.Seconds()
+			payloads = append(payloads, payload)
+		}
+		d.fastestLap = data.FastestLap
+	}
+
+	// The fastest car leads in the other sessions so only races have lead changes
+	if d.raceSession && data.Position == 1 && data.Number != d.leader {
+		if d.leader != 0 {
+			payload := d.driverPayload(LeadChange, data)
+			payload.Lap = data.Lap
+			payload.PreviousDriver = d.leader
+			payload.PreviousDriverName = d.name(d.leader)
+			payloads = append(payloads, payload)
+		}
+		d.leader = data.Number
+	}
+
+	return payloads
+}
+
+func (d *detector) processEvent(data Messages.Event) []Payload {
+	previous := d.event
+	d.event = &data
+	if previous == nil {
+		return nil
+	}
+
+	payloads := []Payload{}
+
+	if data.Status != previous.Status {
+		if data.Status == Messages.Started {
+			payloads = append(payloads, d.eventPayload(SessionStart, data.Timestamp, data.Status.String()))
+		} else if isSessionOver(data.Status) && !isSessionOver(previous.Status) {
+			payloads = append(payloads, d.eventPayload(SessionEnd, data.Timestamp, data.Status.String()))
+		}
+	}
+
+	if data.TrackStatus != previous.TrackStatus && data.TrackStatus != Messages.NoFlag {
+		payloads = append(payloads, d.eventPayload(Flag, data.Timestamp, data.TrackStatus.String()))
+	}
+
+	if data.SafetyCar != previous.SafetyCar {
+		payloads = append(payloads, d.eventPayload(SafetyCar, data.Timestamp, outputFormat.SafetyCarStatus(data.SafetyCar)))
+	}
+
+	return payloads
+}
+
+func (d *detector) processRaceControlMessage(data Messages.RaceControlMessage) []Payload {
+	msg := raceControl.Parse(data)
+	// Serving a penalty isn't a new penalty
+	if msg.Category != raceControl.Penalties || msg.PenaltyServed() {
+		return nil
+	}
+
+	payload := d.eventPayload(Penalty, data.Timestamp, "")
+	payload.Message = data.Msg
+	payload.Cars = msg.Cars
+	payload.Lap = msg.Lap
+	return []Payload{payload}
+}
+
+func (d *detector) eventPayload(event EventType, timestamp time.Time, status string) Payload {
+	return Payload{Event: event, Timestamp: timestamp, Session: d.session, Status: status}
+}
+
+func (d *detector) driverPayload(event EventType, data Messages.Timing) Payload {
+	payload := d.eventPayload(event, data.Timestamp, "")
+	payload.Driver = data.Number
+	payload.DriverName = d.name(data.Number)
+	payload.Team = data.Team
+	return payload
+}
+
+func (d *detector) name(driverNumber int) string {
+	if name, exists := d.names[driverNumber]; exists {
+		return name
+	}
+	if timing, exists := d.timing[driverNumber]; exists {
+		return timing.ShortName
+	}
+	return ""
+}
+
+func isSessionOver(status Messages.SessionState) bool {
+	return status == Messages.Finished || status == Messages.Finalised || status == Messages.Ended
+}
@@ -0,0 +1,106 @@
+package webhooks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/f1gopher/f1gopherlib/Messages"
+)
+
+func TestSessionEvents(t *testing.T) {
+	d := newDetector()
+	d.reset("Bahrain Grand Prix", Messages.RaceSession)
+
+	events := []struct {
+		event    Messages.Event
+		expected []Payload
+	}{
+		// Nothing for the state when joining the session
+		{event: Messages.Event{Status: Messages.Inactive, TrackStatus: Messages.GreenFlag}},
+		{event: Messages.Event{Status: Messages.Started, TrackStatus: Messages.GreenFlag},
+			expected: []Payload{{Event: SessionStart, Status: "Started"}}},
+		{event: Messages.Event{Status: Messages.Started, TrackStatus: Messages.YellowFlag, SafetyCar: Messages.SafetyCar},
+			expected: []Payload{{Event: Flag, Status: "Yellow"}, {Event: SafetyCar, Status: "Safety Car"}}},
+		{event: Messages.Event{Status: Messages.Started, TrackStatus: Messages.YellowFlag, SafetyCar: Messages.SafetyCar}},
+		{event: Messages.Event{Status: Messages.Finished, TrackStatus: Messages.ChequeredFlag},
+			expected: []Payload{{Event: SessionEnd, Status: "Finished"}, {Event: Flag, Status: "Chequered"},
+				{Event: SafetyCar, Status: "Clear"}}},
+		{event: Messages.Event{Status: Messages.Finalised, TrackStatus: Messages.ChequeredFlag}},
+	}
+
+	for x, event := range events {
+		payloads := d.processEvent(event.event)
+		if len(payloads) != len(event.expected) {
+			t.Fatalf("event %d: expected %v, got %v", x, event.expected, payloads)
+		}
+		for y := range payloads {
+			if payloads[y].Event != event.expected[y].Event || payloads[y].Status != event.expected[y].Status ||
+				payloads[y].Session != "Bahrain Grand Prix" {
+				t.Errorf("event %d: expected %v, got %v", x, event.expected[y], payloads[y])
+			}
+		}
+	}
+}
+
+func TestTimingEvents(t *testing.T) {
+	d := newDetector()
+	d.reset("Race", Messages.RaceSession)
+	d.processDrivers(Messages.Drivers{Drivers: []Messages.DriverInfo{
+		{Number: 1, ShortName: "VER"}, {Number: 44, ShortName: "HAM"}}})
+
+	d.processTiming(Messages.Timing{Number: 1, Position: 1, Location: Messages.OnTrack})
+	d.processTiming(Messages.Timing{Number: 44, Position: 2, Location: Messages.OnTrack,
+		FastestLap: 92 * time.Second, OverallFastestLap: true})
+
+	payloads := d.processTiming(Messages.Timing{Number: 1, Position: 2, Lap: 20, Location: Messages.Pitlane})
+	if len(payloads) != 1 || payloads[0].Event != PitStop || payloads[0].DriverName != "VER" || payloads[0].Lap != 20 {
+		t.Errorf("expected VER to pit, got %v", payloads)
+	}
+
+	payloads = d.processTiming(Messages.Timing{Number: 44, Position: 1, Lap: 20, Location: Messages.OnTrack,
+		FastestLap: 91500 * time.Millisecond, OverallFastestLap: true})
+	if len(payloads) != 2 {
+		t.Fatalf("expected a fastest lap and lead change, got %v", payloads)
+	}
+	if payloads[0].Event != FastestLap || payloads[0].LapTime != 91.5 {
+		t.Errorf("expected a fastest lap, got %v", payloads[0])
+	}
+	if payloads[1].Event != LeadChange || payloads[1].Driver != 44 || payloads[1].PreviousDriverName != "VER" {
+		t.Errorf("expected HAM to lead, got %v", payloads[1])
+	}
+
+	if payloads = d.processTiming(Messages.Timing{Number: 44, Position: 1, Lap: 21, Location: Messages.OnTrack,
+		FastestLap: 91500 * time.Millisecond, OverallFastestLap: true}); len(payloads) != 0 {
+		t.Errorf("nothing has changed, got %v", payloads)
+	}
+}
+
+func TestNoLeadChangesOutsideRaces(t *testing.T) {
+	d := newDetector()
+	d.reset("Qualifying", Messages.QualifyingSession)
+
+	d.processTiming(Messages.Timing{Number: 1, Position: 1})
+	if payloads := d.processTiming(Messages.Timing{Number: 44, Position: 1}); len(payloads) != 0 {
+		t.Errorf("expected no lead change, got %v", payloads)
+	}
+}
+
+func TestPenalties(t *testing.T) {
+	d := newDetector()
+
+	payloads := d.processRaceControlMessage(Messages.RaceControlMessage{
+		Msg: "FIA STEWARDS: 5 SECOND TIME PENALTY FOR CAR 44 (HAM) - CAUSING A COLLISION"})
+	if len(payloads) != 1 || payloads[0].Event != Penalty || len(payloads[0].Cars) != 1 || payloads[0].Cars[0] != 44 {
+		t.Errorf("expected a penalty for car 44, got %v", payloads)
+	}
+
+	if payloads = d.processRaceControlMessage(Messages.RaceControlMessage{Msg: "DRS ENABLED"}); len(payloads) != 0 {
+		t.Errorf("expected no penalty, got %v", payloads)
+	}
+
+	payloads = d.processRaceControlMessage(Messages.RaceControlMessage{
+		Msg: "FIA STEWARDS: 5 SECOND TIME PENALTY SERVED BY CAR 44 (HAM)"})
+	if len(payloads) != 0 {
+		t.Errorf("expected no penalty for serving one, got %v", payloads)
+	}
+}
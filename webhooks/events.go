@@ -0,0 +1,122 @@
+// F1Gopher - Copyright (C) 2025 f1gopher
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package webhooks
+
+import (
+	"fmt"
+	"time"
+)
+
+type EventType int
+
+const (
+	SessionStart EventType = iota
+	SessionEnd
+	Flag
+	SafetyCar
+	PitStop
+	FastestLap
+	Penalty
+	LeadChange
+	Test
+)
+
+var eventNames = [...]string{"Session Start", "Session End", "Flag", "Safety Car", "Pit Stop", "Fastest Lap", "Penalty",
+	"Lead Change", "Test"}
+
+// The names used in the payloads and the config file
+var eventIds = [...]string{"session_start", "session_end", "flag", "safety_car", "pit_stop", "fastest_lap", "penalty",
+	"lead_change", "test"}
+
+func (e EventType) String() string {
+	return eventNames[e]
+}
+
+// EventTypes returns every event that can be sent to a hook in display order, the test event is always sent
+func EventTypes() []EventType {
+	return []EventType{SessionStart, SessionEnd, Flag, SafetyCar, PitStop, FastestLap, Penalty, LeadChange}
+}
+
+func (e EventType) MarshalText() ([]byte, error) {
+	return []byte(eventIds[e]), nil
+}
+
+func (e *EventType) UnmarshalText(text []byte) error {
+	for x, id := range eventIds {
+		if id == string(text) {
+			*e = EventType(x)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown webhook event: %s", text)
+}
+
+// Payload is the JSON body posted to the hooks. Only the fields for the event are set.
+type Payload struct {
+	Event     EventType `json:"event"`
+	Timestamp time.Time `json:"timestamp"`
+	Session   string    `json:"session"`
+
+	// The car for pit stop, fastest lap and lead change events
+	Driver     int    `json:"driver,omitempty"`
+	DriverName string `json:"driverName,omitempty"`
+	Team       string `json:"team,omitempty"`
+	Lap        int    `json:"lap,omitempty"`
+	// The previous leader for lead change events
+	PreviousDriver     int    `json:"previousDriver,omitempty"`
+	PreviousDriverName string `json:"previousDriverName,omitempty"`
+	// The fastest lap in seconds
+	LapTime float64 `json:"lapTime,omitempty"`
+	// The session, flag or safety car state
+	Status string `json:"status,omitempty"`
+	// The race control message and the cars it mentions for penalty events
+	Message string `json:"message,omitempty"`
+	Cars    []int  `json:"cars,omitempty"`
+}
+
+// Hook is a URL the events are posted to
+type Hook struct {
+	Name    string `json:"name"`
+	URL     string `json:"url"`
+	Enabled bool   `json:"enabled"`
+	// Payloads are signed with this if it is set
+	Secret string `json:"secret,omitempty"`
+	// The events to send, all events are sent if this is empty
+	Events []EventType `json:"events,omitempty"`
+}
+
+// NewHook returns an enabled hook that is sent every event
+func NewHook() Hook {
+	return Hook{Name: "Webhook", URL: "http://localhost:8090/webhook", Enabled: true}
+}
+
+// Wants returns if the event should be sent to the hook
+func (h *Hook) Wants(event EventType) bool {
+	if !h.Enabled || h.URL == "" {
+		return false
+	}
+
+	if event == Test || len(h.Events) == 0 {
+		return true
+	}
+
+	for _, wanted := range h.Events {
+		if wanted == event {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+// F1Gopher - Copyright (C) 2025 f1gopher
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package webhooks
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+// Largest body the receiver will read
+const maxBodySize = 1 << 20
+
+// Received is a payload received by the test endpoint
+type Received struct {
+	Payload Payload
+	Body    []byte
+	// If the payload was signed and if the signature matched one of the secrets
+	Signed bool
+	Valid  bool
+}
+
+// Receiver is a local endpoint to test the hooks against. It accepts payloads, checks their signatures against the
+// secrets and passes them to the callback.
+type Receiver struct {
+	secrets   []string
+	onReceive func(Received)
+}
+
+func NewReceiver(secrets []string, onReceive func(Received)) *Receiver {
+	return &Receiver{secrets: secrets, onReceive: onReceive}
+}
+
+func (r *Receiver) ServeHTTP(w http.ResponseWriter, request *http.Request) {
+	if request.Method != http.MethodPost {
+		http.Error(w, "only POST is supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(request.Body, maxBodySize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	received := Received{Body: body}
+	if err = json.Unmarshal(body, &received.Payload); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	signature := request.Header.Get(SignatureHeader)
+	received.Signed = signature != ""
+	for _, secret := range r.secrets {
+		if received.Signed && Verify(secret, body, signature) {
+			received.Valid = true
+		}
+	}
+
+	if r.onReceive != nil {
+		r.onReceive(received)
+	}
+
+	// Reject bad signatures so the sender shows the problem
+	if received.Signed && !received.Valid {
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
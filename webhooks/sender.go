@@ -0,0 +1,251 @@
+// F1Gopher - Copyright (C) 2025 f1gopher
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const SignatureHeader = "X-F1Gopher-Signature"
+const EventHeader = "X-F1Gopher-Event"
+
+const maxAttempts = 5
+const firstBackoff = time.Second
+const requestTimeout = 10 * time.Second
+
+// Payloads waiting to be sent to a hook, any more are dropped
+const queueSize = 100
+
+// Number of deliveries kept for Results
+const resultsSize = 50
+
+// Sign returns the signature header value for the body
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify returns if the signature header value is valid for the body
+func Verify(secret string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, body)), []byte(signature))
+}
+
+// Result is the outcome of sending a payload to a hook
+type Result struct {
+	Timestamp  time.Time
+	Hook       string
+	Event      EventType
+	Attempts   int
+	StatusCode int
+	Err        error
+}
+
+func (r Result) Succeeded() bool {
+	return r.Err == nil
+}
+
+type delivery struct {
+	hook    Hook
+	payload Payload
+}
+
+// queue is the deliveries waiting for a URL and the worker sending them
+type queue struct {
+	items  chan delivery
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// sender posts payloads to the hooks in the background. Each URL has its own queue so a hook receives the events in
+// order and a hook that is down doesn't delay the others.
+type sender struct {
+	client  *http.Client
+	backoff time.Duration
+
+	ctx      context.Context
+	cancel   context.CancelFunc
+	workers  sync.WaitGroup
+	queues   map[string]*queue
+	results  []Result
+	lock     sync.Mutex
+	isClosed bool
+}
+
+func newSender() *sender {
+	s := &sender{
+		client:  &http.Client{Timeout: requestTimeout},
+		backoff: firstBackoff,
+		queues:  map[string]*queue{},
+	}
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	return s
+}
+
+func (s *sender) send(hook Hook, payload Payload) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.isClosed {
+		return
+	}
+
+	urlQueue, exists := s.queues[hook.URL]
+	if !exists {
+		urlQueue = &queue{items: make(chan delivery, queueSize)}
+		urlQueue.ctx, urlQueue.cancel = context.WithCancel(s.ctx)
+		s.queues[hook.URL] = urlQueue
+		s.workers.Add(1)
+		go s.worker(urlQueue)
+	}
+
+	select {
+	case urlQueue.items <- delivery{hook: hook, payload: payload}:
+	default:
+		s.addResult(Result{
+			Timestamp: time.Now(),
+			Hook:      hook.Name,
+			Event:     payload.Event,
+			Err:       fmt.Errorf("too many events waiting to be sent"),
+		})
+	}
+}
+
+// removeUnused stops the workers for URLs that aren't in urls and drops anything they still had to send
+func (s *sender) removeUnused(urls map[string]bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for url, urlQueue := range s.queues {
+		if !urls[url] {
+			urlQueue.cancel()
+			delete(s.queues, url)
+		}
+	}
+}
+
+func (s *sender) worker(urlQueue *queue) {
+	defer s.workers.Done()
+
+	for {
+		select {
+		case <-urlQueue.ctx.Done():
+			return
+		case item := <-urlQueue.items:
+			result := s.deliver(urlQueue.ctx, item)
+
+			s.lock.Lock()
+			s.addResult(result)
+			s.lock.Unlock()
+		}
+	}
+}
+
+// deliver posts the payload, retrying with an increasing delay if the hook can't be reached or has a server error
+func (s *sender) deliver(ctx context.Context, item delivery) Result {
+	result := Result{Hook: item.hook.Name, Event: item.payload.Event}
+
+	body, err := json.Marshal(item.payload)
+	if err != nil {
+		result.Timestamp = time.Now()
+		result.Err = err
+		return result
+	}
+
+	backoff := s.backoff
+	for result.Attempts = 1; ; result.Attempts++ {
+		var retry bool
+		result.StatusCode, retry, result.Err = s.post(ctx, item.hook, item.payload.Event, body)
+		result.Timestamp = time.Now()
+		if !retry || result.Attempts == maxAttempts {
+			return result
+		}
+
+		select {
+		case <-ctx.Done():
+			return result
+		case <-time.After(backoff):
+			backoff *= 2
+		}
+	}
+}
+
+func (s *sender) post(ctx context.Context, hook Hook, event EventType, body []byte) (statusCode int, retry bool, err error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, false, err
+	}
+
+	eventId, _ := event.MarshalText()
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "F1Gopher")
+	request.Header.Set(EventHeader, string(eventId))
+	if hook.Secret != "" {
+		request.Header.Set(SignatureHeader, Sign(hook.Secret, body))
+	}
+
+	response, err := s.client.Do(request)
+	if err != nil {
+		return 0, true, err
+	}
+	response.Body.Close()
+
+	switch {
+	case response.StatusCode >= 200 && response.StatusCode < 300:
+		return response.StatusCode, false, nil
+	case response.StatusCode == http.StatusTooManyRequests || response.StatusCode >= 500:
+		return response.StatusCode, true, fmt.Errorf("server error: %s", response.Status)
+	default:
+		return response.StatusCode, false, fmt.Errorf("rejected: %s", strings.TrimSpace(response.Status))
+	}
+}
+
+// addResult must be called with the lock held
+func (s *sender) addResult(result Result) {
+	s.results = append(s.results, result)
+	if len(s.results) > resultsSize {
+		s.results = s.results[len(s.results)-resultsSize:]
+	}
+}
+
+func (s *sender) getResults() []Result {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	results := make([]Result, len(s.results))
+	copy(results, s.results)
+	return results
+}
+
+// close stops any retries and drops the payloads that haven't been sent
+func (s *sender) close() {
+	s.lock.Lock()
+	s.isClosed = true
+	s.lock.Unlock()
+
+	s.cancel()
+	s.workers.Wait()
+}
@@ -0,0 +1,157 @@
+package webhooks
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/f1gopher/f1gopherlib/Messages"
+)
+
+// waitForResults waits for the number of deliveries to finish
+func waitForResults(t *testing.T, sink *Sink, count int) []Result {
+	for start := time.Now(); time.Since(start) < 5*time.Second; time.Sleep(10 * time.Millisecond) {
+		if results := sink.Results(); len(results) >= count {
+			return results
+		}
+	}
+	t.Fatalf("timed out waiting for %d results, got %v", count, sink.Results())
+	return nil
+}
+
+func TestSignedDelivery(t *testing.T) {
+	var received []Received
+	var lock sync.Mutex
+	server := httptest.NewServer(NewReceiver([]string{"secret"}, func(r Received) {
+		lock.Lock()
+		received = append(received, r)
+		lock.Unlock()
+	}))
+	defer server.Close()
+
+	sink := NewSink([]Hook{
+		{Name: "signed", URL: server.URL, Enabled: true, Secret: "secret", Events: []EventType{Flag}},
+		{Name: "wrong secret", URL: server.URL + "/wrong", Enabled: true, Secret: "other"},
+		{Name: "disabled", URL: server.URL + "/disabled", Enabled: false},
+		{Name: "filtered", URL: server.URL + "/filtered", Enabled: true, Events: []EventType{PitStop}},
+	})
+	defer sink.Close()
+	sink.Reset("Monaco Grand Prix", Messages.RaceSession)
+
+	sink.ProcessEvent(Messages.Event{TrackStatus: Messages.GreenFlag})
+	sink.ProcessEvent(Messages.Event{TrackStatus: Messages.RedFlag})
+
+	results := waitForResults(t, sink, 2)
+	time.Sleep(50 * time.Millisecond)
+	if len(sink.Results()) != 2 {
+		t.Errorf("only the enabled hooks that want flags should be sent the event, got %v", sink.Results())
+	}
+
+	for _, result := range results {
+		switch result.Hook {
+		case "signed":
+			if !result.Succeeded() || result.Attempts != 1 {
+				t.Errorf("expected the signed hook to succeed first time, got %v", result)
+			}
+		case "wrong secret":
+			if result.Succeeded() || result.StatusCode != http.StatusUnauthorized || result.Attempts != 1 {
+				t.Errorf("expected the wrong secret to be rejected without retrying, got %v", result)
+			}
+		default:
+			t.Errorf("unexpected delivery %v", result)
+		}
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+	for _, r := range received {
+		if r.Payload.Event != Flag || r.Payload.Status != "Red" || r.Payload.Session != "Monaco Grand Prix - Race" {
+			t.Errorf("unexpected payload %v", r.Payload)
+		}
+	}
+}
+
+func TestRetries(t *testing.T) {
+	var attempts int
+	var lock sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		attempts++
+		if attempts < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	sink := NewSink(nil)
+	defer sink.Close()
+	sink.sender.backoff = time.Millisecond
+
+	sink.SendTest(Hook{Name: "flaky", URL: server.URL})
+	results := waitForResults(t, sink, 1)
+	if !results[0].Succeeded() || results[0].Attempts != 3 || results[0].Event != Test {
+		t.Errorf("expected success after 3 attempts, got %v", results[0])
+	}
+}
+
+func TestGivesUp(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	sink := NewSink(nil)
+	defer sink.Close()
+	sink.sender.backoff = time.Millisecond
+
+	sink.SendTest(Hook{Name: "down", URL: server.URL})
+	results := waitForResults(t, sink, 1)
+	if results[0].Succeeded() || results[0].Attempts != maxAttempts {
+		t.Errorf("expected failure after %d attempts, got %v", maxAttempts, results[0])
+	}
+}
+
+func TestRemovedHooksStopSending(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	sink := NewSink([]Hook{{Name: "down", URL: server.URL, Enabled: true}})
+	defer sink.Close()
+	sink.sender.backoff = time.Hour
+
+	// Waits to retry after the first attempt fails until the hook is removed
+	sink.ProcessEvent(Messages.Event{TrackStatus: Messages.GreenFlag})
+	sink.ProcessEvent(Messages.Event{TrackStatus: Messages.RedFlag})
+	time.Sleep(50 * time.Millisecond)
+	sink.SetHooks(nil)
+
+	results := waitForResults(t, sink, 1)
+	if results[0].Succeeded() || results[0].Attempts != 1 {
+		t.Errorf("expected the removed hook to stop after 1 attempt, got %v", results[0])
+	}
+
+	sink.sender.lock.Lock()
+	queues := len(sink.sender.queues)
+	sink.sender.lock.Unlock()
+	if queues != 0 {
+		t.Errorf("expected the removed hook's worker to be stopped, got %d", queues)
+	}
+}
+
+func TestHookEventsSavedByName(t *testing.T) {
+	var event EventType
+	if err := event.UnmarshalText([]byte("lead_change")); err != nil || event != LeadChange {
+		t.Errorf("expected a lead change, got %v %v", event, err)
+	}
+	if err := event.UnmarshalText([]byte("Lead Change")); err == nil {
+		t.Errorf("expected an error for the display name")
+	}
+}
@@ -0,0 +1,123 @@
+// F1Gopher - Copyright (C) 2025 f1gopher
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package webhooks
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/f1gopher/f1gopherlib/Messages"
+)
+
+// Sink posts the session events to the hooks. It is given the same messages as the panels so it can be used by the
+// GUI and headless modes. Safe for concurrent use.
+type Sink struct {
+	detector *detector
+	sender   *sender
+	hooks    []Hook
+	lock     sync.Mutex
+}
+
+func NewSink(hooks []Hook) *Sink {
+	s := &Sink{
+		detector: newDetector(),
+		sender:   newSender(),
+	}
+	s.SetHooks(hooks)
+	return s
+}
+
+// Reset clears the session data ready for a new session, name is included in the payloads
+func (s *Sink) Reset(name string, session Messages.SessionType) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.detector.reset(fmt.Sprintf("%s - %s", name, session.String()), session)
+}
+
+// SetHooks replaces the hooks, anything waiting to be sent to a URL that is no longer used is dropped
+func (s *Sink) SetHooks(hooks []Hook) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.hooks = make([]Hook, len(hooks))
+	copy(s.hooks, hooks)
+
+	urls := map[string]bool{}
+	for _, hook := range hooks {
+		urls[hook.URL] = true
+	}
+	s.sender.removeUnused(urls)
+}
+
+func (s *Sink) ProcessDrivers(data Messages.Drivers) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.detector.processDrivers(data)
+}
+
+func (s *Sink) ProcessTiming(data Messages.Timing) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.send(s.detector.processTiming(data))
+}
+
+func (s *Sink) ProcessEvent(data Messages.Event) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.send(s.detector.processEvent(data))
+}
+
+func (s *Sink) ProcessRaceControlMessage(data Messages.RaceControlMessage) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.send(s.detector.processRaceControlMessage(data))
+}
+
+// SendTest sends a test event to the hook even if it is disabled
+func (s *Sink) SendTest(hook Hook) {
+	s.lock.Lock()
+	session := s.detector.session
+	s.lock.Unlock()
+
+	s.sender.send(hook, Payload{Event: Test, Timestamp: time.Now(), Session: session, Message: "Test from F1Gopher"})
+}
+
+// Results returns the outcome of the latest deliveries, oldest first
+func (s *Sink) Results() []Result {
+	return s.sender.getResults()
+}
+
+// Close stops sending, the sink can't be used afterwards
+func (s *Sink) Close() {
+	s.sender.close()
+}
+
+// send must be called with the lock held
+func (s *Sink) send(payloads []Payload) {
+	for _, payload := range payloads {
+		for x := range s.hooks {
+			if s.hooks[x].Wants(payload.Event) {
+				s.sender.send(s.hooks[x], payload)
+			}
+		}
+	}
+}